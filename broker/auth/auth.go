@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"wasimoff/broker/net/transport"
+)
+
+// Scope is a permission granted to a token. The admin scope includes all others.
+type Scope string
+
+const (
+	Provider Scope = "provider" // connect as a Provider
+	Submit   Scope = "submit"   // submit tasks and jobs as a client
+	Upload   Scope = "upload"   // upload and manage files in storage
+	Admin    Scope = "admin"    // everything, including history, metrics and tokens
+)
+
+// Scopes lists all known scopes, to validate configured tokens.
+var Scopes = []Scope{Provider, Submit, Upload, Admin}
+
+// Identity is the owner of a token. The name is recorded as the requester of
+// tasks and the uploader of files.
+type Identity struct {
+	Name    string    `json:"name"`
+	Scopes  []Scope   `json:"scopes"`
+	Created time.Time `json:"created"`
+
+	certified bool // named by a client certificate, which has no namespace
+}
+
+// Has checks if the identity was granted a scope, either directly or as admin.
+func (id *Identity) Has(scope Scope) bool {
+	return slices.Contains(id.Scopes, scope) || slices.Contains(id.Scopes, Admin)
+}
+
+// TokenStore looks up identities by the hash of their token.
+type TokenStore interface {
+	Lookup(hash string) *Identity
+}
+
+// Hash a token for storage. Tokens are long random strings, so a plain digest
+// is sufficient and allows direct lookups.
+func Hash(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
+
+// Authenticator checks bearer tokens on HTTP routes. All methods are safe to call
+// on a nil *Authenticator, in which case authentication is disabled.
+type Authenticator struct {
+	tokens TokenStore
+}
+
+// New returns an Authenticator, which checks tokens against the store.
+func New(tokens TokenStore) *Authenticator {
+	return &Authenticator{tokens}
+}
+
+// Require wraps a handler to let requests through only if their token has at least
+// one of the scopes. Without a token, a verified client certificate is accepted
+// in its place. The identity can be retrieved from the request with Get.
+func (a *Authenticator) Require(handler http.Handler, scopes ...Scope) http.Handler {
+	if a == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var id *Identity
+		if token := bearer(r); token != "" {
+			id = a.tokens.Lookup(Hash(token))
+		} else if id = Certificate(r); id == nil {
+			w.Header().Set("www-authenticate", `Bearer realm="wasimoff"`)
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		if id == nil {
+			w.Header().Set("www-authenticate", `Bearer realm="wasimoff", error="invalid_token"`)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		if !slices.ContainsFunc(scopes, id.Has) {
+			http.Error(w, "token does not have the required scope", http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, id)))
+	})
+}
+
+// bearer returns the token from the authorization header. Browsers can't set
+// headers on WebSockets, so upgrade requests may use a `token` query parameter.
+func bearer(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	if strings.EqualFold(r.Header.Get("upgrade"), "websocket") {
+		return r.URL.Query().Get("token")
+	}
+	return ""
+}
+
+// Certificate returns the identity of a client certificate, which was verified
+// against the configured CAs during the TLS handshake, or nil. It is named by the
+// certificate's subject and may only connect as a Provider.
+func Certificate(r *http.Request) *Identity {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	leaf := r.TLS.VerifiedChains[0][0]
+	name := leaf.Subject.CommonName
+	if name == "" {
+		name = leaf.Subject.String()
+	}
+	// slashes separate the namespaces of tenants and colons the port of addresses
+	name = strings.NewReplacer("/", "_", ":", "_").Replace(name)
+	return &Identity{Name: name, Scopes: []Scope{Provider}, Created: leaf.NotBefore, certified: true}
+}
+
+// the context key for the identity of authenticated requests
+type identityKey struct{}
+
+// Get returns the identity of an authenticated request or nil. Client certificates
+// are recognized even when token authentication is disabled.
+func Get(r *http.Request) *Identity {
+	if id, ok := r.Context().Value(identityKey{}).(*Identity); ok {
+		return id
+	}
+	return Certificate(r)
+}
+
+// Requester identifies who sent a request: the name of its token or certificate,
+// or the remote address, when authentication is disabled.
+func Requester(r *http.Request) string {
+	if id := Get(r); id != nil {
+		return id.Name
+	}
+	return transport.ProxiedAddr(r)
+}
+
+// Tenant returns the namespace of a request's files and jobs: the name of its
+// identity, or empty for admins, certificates and without authentication, which
+// share the global namespace. Certificates are named by their subject, which may
+// equal a token name, so they must not see that tenant's files.
+func Tenant(r *http.Request) string {
+	if id := Get(r); id != nil && !id.Has(Admin) && !id.certified {
+		return id.Name
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// The ListHandler returns a HTTP handler to list all tokens as JSON, without
+// their secrets.
+func (t *BoltTokens) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list, err := t.List()
+		if err != nil {
+			log.Printf("ERR: auth: listing tokens for [%s] failed: %s", r.RemoteAddr, err)
+			http.Error(w, "listing tokens failed", http.StatusInternalServerError)
+			return
+		}
+		if list == nil {
+			list = []Identity{} // encode as an empty array instead of null
+		}
+		writeJSON(w, r, list)
+	}
+}
+
+// The CreateHandler returns a HTTP handler to create a token from a JSON body
+// like `{"name": "alice", "scopes": ["submit"]}`. The response contains the
+// secret token, which is not shown again.
+func (t *BoltTokens) CreateHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Name   string  `json:"name"`
+			Scopes []Scope `json:"scopes"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&request); err != nil {
+			http.Error(w, "invalid json body", http.StatusBadRequest)
+			return
+		}
+		token, err := t.Create(request.Name, request.Scopes)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("auth: token %q created by %q", request.Name, Get(r).Name)
+		writeJSON(w, r, map[string]any{
+			"name":   request.Name,
+			"scopes": request.Scopes,
+			"token":  token,
+		})
+	}
+}
+
+// The RevokeHandler returns a HTTP handler to revoke the token named in the path.
+func (t *BoltTokens) RevokeHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if err := t.Revoke(name); errors.Is(err, ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			log.Printf("ERR: auth: revoking token %q for [%s] failed: %s", name, r.RemoteAddr, err)
+			http.Error(w, "revoking token failed", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("auth: token %q revoked by %q", name, Get(r).Name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// write a JSON response
+func writeJSON(w http.ResponseWriter, r *http.Request, value any) {
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Printf("ERR: auth: writing response to [%s] failed: %s", r.RemoteAddr, err)
+	}
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNotFound is returned when revoking a token name that doesn't exist.
+var ErrNotFound = errors.New("token not found")
+
+// only store digests in the expected format, so lookups can't miss silently
+var hashPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// checkName validates the name of a new token, which is also its tenant
+func checkName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if strings.Contains(name, "/") {
+		return fmt.Errorf("name cannot contain a slash")
+	}
+	if strings.Contains(name, ":") {
+		// the fair share and quotas strip the port from remote addresses
+		return fmt.Errorf("name cannot contain a colon")
+	}
+	return nil
+}
+
+// checkScopes validates the scopes of a new token
+func checkScopes(scopes []Scope) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+	return nil
+}
+
+// -------------------- json file -------------------- >>
+
+// fileTokens are read once from a JSON file, keyed by their hash
+type fileTokens map[string]*Identity
+
+// NewFileTokens reads hashed tokens from a JSON file, which maps names to a hash
+// and the granted scopes. The hash of a token is its hex-encoded SHA-256 digest,
+// optionally prefixed with `sha256:`, e.g. from `printf %s "$TOKEN" | sha256sum`.
+//
+//	{ "alice": { "hash": "sha256:4d2f...", "scopes": ["submit", "upload"] } }
+func NewFileTokens(path string) TokenStore {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		// to keep the API clean, we just abort in here since this happens only at startup
+		log.Fatalf("auth: cannot read tokens: %s", err)
+	}
+	var entries map[string]struct {
+		Hash   string  `json:"hash"`
+		Scopes []Scope `json:"scopes"`
+	}
+	if err := json.Unmarshal(buf, &entries); err != nil {
+		log.Fatalf("auth: cannot parse tokens: %s", err)
+	}
+	tokens := make(fileTokens, len(entries))
+	for name, entry := range entries {
+		hash := strings.ToLower(entry.Hash)
+		if !strings.HasPrefix(hash, "sha256:") {
+			hash = "sha256:" + hash
+		}
+		if !hashPattern.MatchString(hash) {
+			log.Fatalf("auth: token %q: invalid hash", name)
+		}
+		if err := checkName(name); err != nil {
+			log.Fatalf("auth: token %q: %s", name, err)
+		}
+		if err := checkScopes(entry.Scopes); err != nil {
+			log.Fatalf("auth: token %q: %s", name, err)
+		}
+		tokens[hash] = &Identity{Name: name, Scopes: entry.Scopes}
+	}
+	log.Printf("auth: loaded %d tokens from %s", len(tokens), path)
+	return tokens
+}
+
+func (t fileTokens) Lookup(hash string) *Identity {
+	return t[hash]
+}
+
+// -------------------- boltdb -------------------- >>
+
+// BoltTokens keeps hashed tokens in a BoltDB database, where they can be created
+// and revoked at runtime through the admin API.
+type BoltTokens struct {
+	db *bolt.DB
+}
+
+var tokenBucket = []byte("tokens")
+
+// NewBoltTokens opens or creates the database at path. When it contains no tokens
+// yet, an initial admin token is created and logged once.
+func NewBoltTokens(path string) *BoltTokens {
+
+	// open the boltdb file
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		// to keep the API clean, we just abort in here since this happens only at startup
+		log.Fatalf("auth: cannot open db: %s", err)
+	}
+	empty := false
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(tokenBucket)
+		if err != nil {
+			return err
+		}
+		first, _ := bucket.Cursor().First()
+		empty = first == nil
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("auth: cannot create bucket: %s", err)
+	}
+
+	// bootstrap the admin api
+	t := &BoltTokens{db}
+	if empty {
+		token, err := t.Create("admin", []Scope{Admin})
+		if err != nil {
+			log.Fatalf("auth: cannot create initial token: %s", err)
+		}
+		log.Printf("auth: created initial admin token, it is not shown again: %s", token)
+	}
+	return t
+}
+
+// Lookup an identity by the hash of its token.
+func (t *BoltTokens) Lookup(hash string) (id *Identity) {
+	t.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(tokenBucket).Get([]byte(hash)); value != nil {
+			id = &Identity{}
+			if err := json.Unmarshal(value, id); err != nil {
+				log.Printf("ERR: auth: decoding token %s: %s", hash, err)
+				id = nil
+			}
+		}
+		return nil
+	})
+	return id
+}
+
+// List all identities, sorted by name.
+func (t *BoltTokens) List() (list []Identity, err error) {
+	err = t.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tokenBucket).ForEach(func(k, v []byte) error {
+			var id Identity
+			if err := json.Unmarshal(v, &id); err != nil {
+				return fmt.Errorf("decoding token %s: %w", k, err)
+			}
+			list = append(list, id)
+			return nil
+		})
+	})
+	slices.SortFunc(list, func(a, b Identity) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return list, err
+}
+
+// Create a new random token for a unique name. Only its hash is stored, so the
+// returned token can't be retrieved again.
+func (t *BoltTokens) Create(name string, scopes []Scope) (token string, err error) {
+	if err := checkName(name); err != nil {
+		return "", err
+	}
+	if err := checkScopes(scopes); err != nil {
+		return "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token = hex.EncodeToString(secret)
+	value, err := json.Marshal(Identity{Name: name, Scopes: scopes, Created: time.Now()})
+	if err != nil {
+		return "", err
+	}
+	err = t.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tokenBucket)
+		if hash, err := find(bucket, name); err != nil {
+			return err
+		} else if hash != nil {
+			return fmt.Errorf("name %q exists already", name)
+		}
+		return bucket.Put([]byte(Hash(token)), value)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Revoke the token with the given name.
+func (t *BoltTokens) Revoke(name string) error {
+	return t.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(tokenBucket)
+		hash, err := find(bucket, name)
+		if err != nil {
+			return err
+		}
+		if hash == nil {
+			return ErrNotFound
+		}
+		return bucket.Delete(hash)
+	})
+}
+
+// find the hash of a token by name, or nil
+func find(bucket *bolt.Bucket, name string) (hash []byte, err error) {
+	err = bucket.ForEach(func(k, v []byte) error {
+		var id Identity
+		if err := json.Unmarshal(v, &id); err != nil {
+			return fmt.Errorf("decoding token %s: %w", k, err)
+		}
+		if id.Name == name {
+			hash = slices.Clone(k)
+		}
+		return nil
+	})
+	return hash, err
+}
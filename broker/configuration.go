@@ -6,6 +6,7 @@ import (
 	"os"
 	"slices"
 	"text/tabwriter"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
@@ -22,15 +23,88 @@ type Configuration struct {
 	HttpCert string `split_words:"true" desc:"Path to TLS certificate to use"`
 	HttpKey  string `split_words:"true" desc:"Path to TLS key to use"`
 
+	// HttpClientCa is a path to CA certificates to verify optional client certificates.
+	// Providers with a valid certificate are named by its subject and need no token.
+	HttpClientCa string `split_words:"true" desc:"Path to CA certificates to verify Provider client certificates"`
+
 	// AllowedOrigins is a list of allowed Origin headers for transport connections.
 	AllowedOrigins []string `split_words:"true" desc:"List of allowed Origins for WebSocket"`
 
+	// AuthTokens enables bearer token authentication on all API routes. Hashed tokens are
+	// managed in a BoltDB database at this path or read from a JSON file with a "json:" prefix.
+	AuthTokens string `split_words:"true" desc:"Authenticate with tokens from a BoltDB (path) or JSON file (json:path)"`
+
+	// TaskTimeout is the maximum runtime of a task on a Provider. Jobs can set a shorter
+	// timeout in the QoS parameters.
+	TaskTimeout time.Duration `split_words:"true" desc:"Cancel tasks running longer than this, 0 is unlimited" default:"10m"`
+
+	// HeartbeatInterval and HeartbeatMisses configure the liveness checks of Providers,
+	// which are disconnected when they miss too many consecutive pings.
+	HeartbeatInterval time.Duration `split_words:"true" desc:"Ping Providers in this interval, 0 disables" default:"5s"`
+	HeartbeatMisses   int           `split_words:"true" desc:"Disconnect Providers after this many missed pings" default:"3"`
+
+	// ResumeGrace is how long the session of a disconnected Provider with a persistent id
+	// is kept. When it reconnects meanwhile, its running tasks are resumed instead of failed.
+	ResumeGrace time.Duration `split_words:"true" desc:"Keep sessions of disconnected Providers this long to resume them, 0 disables" default:"30s"`
+
 	// StaticFiles is a path with static files to serve; usually the webprovider frontend dist.
 	StaticFiles string `split_words:"true" default:"../webprovider/dist/" desc:"Serve static files on \"/\" from here"`
 
-	// FileStorage is a path to use for a persistent BoltDB database.
+	// FileStorage is a path to use for a persistent BoltDB database, or a directory
+	// to store plain files in with a "dir:" prefix, or an S3-compatible bucket with
+	// an "s3:" prefix and a URL like "s3:https://host/bucket/prefix"; "s3::memory:"
+	// uses an in-process fake bucket.
 	// An empty string will use an ephemeral in-memory map[string]*File.
-	FileStorage string `desc:"Use persistent BoltDB storage for files" default:":memory:"`
+	FileStorage string `desc:"Use persistent BoltDB (path), directory (dir:path) or S3 (s3:url) storage for files" default:":memory:"`
+
+	// StorageRedirect lets clients and Providers download files from the storage backend
+	// directly, using presigned URLs. Only supported by the S3 storage.
+	StorageRedirect time.Duration `split_words:"true" desc:"Redirect downloads to presigned URLs valid this long, 0 disables" default:"0"`
+
+	// MaxBodySize limits the size of request bodies on the client API, including uploads,
+	// which are streamed to storage.
+	MaxBodySize int64 `split_words:"true" desc:"Maximum request body size in MiB, 0 is unlimited" default:"1024"`
+
+	// StorageTTL is the time after which files without names are collected from storage,
+	// when no task used them. Files in use by jobs in flight are kept. Uses are not
+	// persisted, so the Broker's startup counts as the last use of older files.
+	StorageTTL time.Duration `split_words:"true" desc:"Collect unnamed files unused for this long, 0 keeps all" default:"0"`
+
+	// StorageQuota and StorageUploaderQuota limit the size of stored files overall and for
+	// each uploader. Unnamed files are evicted to make room, otherwise uploads fail.
+	StorageQuota         int64 `split_words:"true" desc:"Total size of stored files in MiB, 0 is unlimited" default:"0"`
+	StorageUploaderQuota int64 `split_words:"true" desc:"Size of stored files per uploader in MiB, 0 is unlimited" default:"0"`
+
+	// PushFiles uploads missing files to the selected Provider before running a task,
+	// instead of waiting for the Provider to download them. PrewarmFiles is the number of
+	// recently used files, which are pushed to new Providers as soon as they connect.
+	PushFiles    bool `split_words:"true" desc:"Push missing files to Providers before running tasks" default:"true"`
+	PrewarmFiles int  `split_words:"true" desc:"Push this many recently used files to new Providers" default:"0"`
+
+	// ChunkSize is the size of chunks, in which larger files are transferred to and from
+	// Providers, so they don't block other messages on the connection for too long.
+	ChunkSize int `split_words:"true" desc:"Transfer files to Providers in chunks of this many KiB, 0 disables" default:"1024"`
+
+	// History is a path to a BoltDB database to record finished jobs and tasks in.
+	// An empty string disables the history.
+	History string `desc:"Record job and task history in this BoltDB file"`
+
+	// HistoryRetention is the age after which recorded jobs and tasks are pruned.
+	HistoryRetention time.Duration `split_words:"true" desc:"Prune history records older than this, 0 keeps all" default:"168h"`
+
+	// FairshareWeights gives requesters (host or identity) a larger share of provider slots.
+	FairshareWeights map[string]int `split_words:"true" desc:"Relative shares per requester, e.g. \"10.0.0.5:4,alice:2\""`
+
+	// FairshareInflight caps the number of dispatched tasks per requester.
+	FairshareInflight    map[string]int `split_words:"true" desc:"Caps on in-flight tasks per requester"`
+	FairshareMaxInflight int            `split_words:"true" desc:"Default cap on in-flight tasks per requester, 0 is unlimited" default:"0"`
+
+	// AdmissionRate and AdmissionBurst limit the tasks per second of each requester with a
+	// token bucket. AdmissionMaxTasks bounds the number of unfinished tasks in total.
+	// Rejected submissions are answered with 429 Too Many Requests and a Retry-After.
+	AdmissionRate     float64 `split_words:"true" desc:"Tasks per second each requester may submit, 0 is unlimited" default:"0"`
+	AdmissionBurst    int     `split_words:"true" desc:"Tasks a requester may submit at once" default:"100"`
+	AdmissionMaxTasks int     `split_words:"true" desc:"Reject submissions beyond this many unfinished tasks, 0 is unlimited" default:"0"`
 
 	// Activate the benchmarking mode where the Broker produces workload itself
 	Benchmode int `desc:"Activate benchmarking mode" default:"0"`
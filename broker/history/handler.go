@@ -0,0 +1,84 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Queries return at most this many records, unless a smaller limit is given.
+const maxQueryLimit = 1000
+
+// The JobsHandler returns a HTTP handler to query recorded jobs as JSON. The
+// query parameters `job`, `requester`, `since`, `until` and `limit` filter the records.
+func (h *History) JobsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobs, err := h.Jobs(filter)
+		writeRecords(w, r, jobs, err)
+	}
+}
+
+// The TasksHandler returns a HTTP handler to query recorded tasks as JSON. In
+// addition to the parameters for jobs, tasks can be filtered by `provider`.
+func (h *History) TasksHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tasks, err := h.Tasks(filter)
+		writeRecords(w, r, tasks, err)
+	}
+}
+
+// parseFilter reads the filter from query parameters; times must be in RFC 3339
+func parseFilter(r *http.Request) (filter Filter, err error) {
+	query := r.URL.Query()
+	filter.Job = query.Get("job")
+	filter.Requester = query.Get("requester")
+	filter.Provider = query.Get("provider")
+	if since := query.Get("since"); since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if until := query.Get("until"); until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	filter.Limit = maxQueryLimit
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return filter, fmt.Errorf("invalid limit: %q", limit)
+		}
+		filter.Limit = min(n, maxQueryLimit)
+	}
+	return filter, nil
+}
+
+// write the queried records as a JSON array
+func writeRecords[T any](w http.ResponseWriter, r *http.Request, records []T, err error) {
+	if err != nil {
+		log.Printf("ERR: history: query from [%s] failed: %s", r.RemoteAddr, err)
+		http.Error(w, "query failed", http.StatusInternalServerError)
+		return
+	}
+	if records == nil {
+		records = []T{} // encode as an empty array instead of null
+	}
+	w.Header().Set("content-type", "application/json")
+	if err := json.NewEncoder(w).Encode(records); err != nil {
+		log.Printf("ERR: history: writing response to [%s] failed: %s", r.RemoteAddr, err)
+	}
+}
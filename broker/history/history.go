@@ -0,0 +1,277 @@
+package history
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"log"
+	"time"
+	"wasimoff/broker/metrics"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// History records finished jobs and tasks in a BoltDB database for auditing. All
+// methods are safe to call on a nil *History, in which case nothing is recorded.
+type History struct {
+	db        *bolt.DB
+	retention time.Duration
+	records   chan record // queue for the writer
+}
+
+// record is a marshalled record, ready to be written
+type record struct {
+	bucket, key, value []byte
+}
+
+var (
+	jobBucket  = []byte("jobs")
+	taskBucket = []byte("tasks")
+)
+
+// Old records are pruned in this interval, if a retention is set.
+const pruneInterval = 10 * time.Minute
+
+// JobRecord is the summary of a finished OffloadingJob.
+type JobRecord struct {
+	ID        string    `json:"id"`
+	Requester string    `json:"requester"`
+	Tasks     int       `json:"tasks"`
+	Completed int       `json:"completed"`
+	Failed    int       `json:"failed"`
+	Cancelled bool      `json:"cancelled,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Submitted time.Time `json:"submitted"`
+	Finished  time.Time `json:"finished"`
+}
+
+// TaskRecord is a single Task_Request/Task_Response pair with information about
+// the Provider which executed it. Only the sizes of the outputs are recorded.
+type TaskRecord struct {
+	ID        string     `json:"id"`
+	Job       string     `json:"job"`
+	Requester string     `json:"requester"`
+	Provider  string     `json:"provider,omitempty"` // address of the executing Provider
+	Type      string     `json:"type"`               // wasip1 or pyodide
+	Binary    string     `json:"binary,omitempty"`   // ref of the wasip1 binary
+	Created   time.Time  `json:"created"`
+	Started   *time.Time `json:"started,omitempty"` // last sent to a Provider
+	Finished  time.Time  `json:"finished"`
+	Status    *int32     `json:"status,omitempty"` // exit code of wasip1 tasks
+	Error     string     `json:"error,omitempty"`
+	Stdout    int        `json:"stdout"`
+	Stderr    int        `json:"stderr"`
+	Artifacts int        `json:"artifacts,omitempty"`
+	Pickle    int        `json:"pickle,omitempty"`
+}
+
+// Filter selects records in queries. Empty fields match anything; the time range
+// applies to the time a record finished.
+type Filter struct {
+	Job       string
+	Requester string
+	Provider  string // only applies to tasks
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// NewBoltHistory opens or creates the database at path and starts pruning
+// records older than retention; a zero retention keeps records forever.
+func NewBoltHistory(path string, retention time.Duration) *History {
+
+	// open the boltdb file
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 3 * time.Second})
+	if err != nil {
+		// to keep the API clean, we just abort in here since this happens only at startup
+		log.Fatalf("history: cannot open db: %s", err)
+	}
+
+	// ensure that all buckets exist
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{jobBucket, taskBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("history: cannot create buckets: %s", err)
+	}
+
+	h := &History{db, retention, make(chan record, 1000)}
+	go h.writer()
+	if retention > 0 {
+		go h.pruner()
+	}
+	return h
+}
+
+// -------------------- recording -------------------- >>
+
+// RecordJob stores the summary of a finished job.
+func (h *History) RecordJob(job *JobRecord) {
+	if h != nil {
+		h.put(jobBucket, job.Finished, job.ID, job)
+	}
+}
+
+// RecordTask stores a finished task.
+func (h *History) RecordTask(task *TaskRecord) {
+	if h != nil {
+		h.put(taskBucket, task.Finished, task.ID, task)
+	}
+}
+
+// put a record as JSON, keyed by its finishing time and ID, so keys are ordered
+// chronologically and job IDs may repeat after a restart of the Broker
+func (h *History) put(bucket []byte, finished time.Time, id string, value any) {
+	blob, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("ERR: history: marshalling record %s: %s", id, err)
+		return
+	}
+	// never block the job which finished, rather drop the record
+	select {
+	case h.records <- record{bucket, append(timekey(finished), id...), blob}:
+	default:
+		metrics.HistoryDropped.Inc()
+		log.Printf("WARN: history: queue is full, dropped record %s", id)
+	}
+}
+
+// writer stores queued records, batching all available ones in a single transaction
+func (h *History) writer() {
+	for r := range h.records {
+		batch := []record{r}
+	drain:
+		for len(batch) < cap(h.records) {
+			select {
+			case r := <-h.records:
+				batch = append(batch, r)
+			default:
+				break drain
+			}
+		}
+		err := h.db.Update(func(tx *bolt.Tx) error {
+			for _, r := range batch {
+				if err := tx.Bucket(r.bucket).Put(r.key, r.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("ERR: history: storing %d records: %s", len(batch), err)
+		}
+	}
+}
+
+// timekey encodes a time as a big-endian prefix for lexicographic ordering
+func timekey(t time.Time) []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(t.UnixNano()))
+}
+
+// -------------------- queries -------------------- >>
+
+// Jobs returns all job records matching the filter in chronological order.
+func (h *History) Jobs(filter Filter) (jobs []*JobRecord, err error) {
+	err = h.scan(jobBucket, filter, func(value []byte) (bool, error) {
+		job := &JobRecord{}
+		if err := json.Unmarshal(value, job); err != nil {
+			return false, err
+		}
+		if filter.Job != "" && job.ID != filter.Job ||
+			filter.Requester != "" && job.Requester != filter.Requester {
+			return false, nil
+		}
+		jobs = append(jobs, job)
+		return true, nil
+	})
+	return
+}
+
+// Tasks returns all task records matching the filter in chronological order.
+func (h *History) Tasks(filter Filter) (tasks []*TaskRecord, err error) {
+	err = h.scan(taskBucket, filter, func(value []byte) (bool, error) {
+		task := &TaskRecord{}
+		if err := json.Unmarshal(value, task); err != nil {
+			return false, err
+		}
+		if filter.Job != "" && task.Job != filter.Job ||
+			filter.Requester != "" && task.Requester != filter.Requester ||
+			filter.Provider != "" && task.Provider != filter.Provider {
+			return false, nil
+		}
+		tasks = append(tasks, task)
+		return true, nil
+	})
+	return
+}
+
+// scan all records in the filter's time range until the limit of matches is reached
+func (h *History) scan(bucket []byte, filter Filter, match func(value []byte) (bool, error)) error {
+	if h == nil {
+		return nil
+	}
+	return h.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		k, v := c.First()
+		if !filter.Since.IsZero() {
+			k, v = c.Seek(timekey(filter.Since))
+		}
+		until := timekey(filter.Until)
+		matches := 0
+		for ; k != nil; k, v = c.Next() {
+			if !filter.Until.IsZero() && bytes.Compare(k[:8], until) > 0 {
+				break
+			}
+			ok, err := match(v)
+			if err != nil {
+				return err
+			}
+			if ok {
+				matches++
+				if filter.Limit > 0 && matches >= filter.Limit {
+					break
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// -------------------- retention -------------------- >>
+
+// pruner regularly deletes records older than the retention
+func (h *History) pruner() {
+	ticker := time.NewTicker(pruneInterval)
+	for ; true; <-ticker.C {
+		cutoff := time.Now().Add(-h.retention)
+		if err := h.prune(cutoff); err != nil {
+			log.Printf("ERR: history: pruning failed: %s", err)
+		}
+	}
+}
+
+// prune deletes all records which finished before cutoff
+func (h *History) prune(cutoff time.Time) error {
+	return h.db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{jobBucket, taskBucket} {
+			b := tx.Bucket(bucket)
+			// collect keys first, deleting while iterating skips entries
+			var expired [][]byte
+			c := b.Cursor()
+			for k, _ := c.First(); k != nil && bytes.Compare(k[:8], timekey(cutoff)) < 0; k, _ = c.Next() {
+				expired = append(expired, bytes.Clone(k))
+			}
+			for _, k := range expired {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
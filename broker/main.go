@@ -5,10 +5,14 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"strings"
+	"wasimoff/broker/auth"
+	"wasimoff/broker/history"
 	"wasimoff/broker/metrics"
 	"wasimoff/broker/net/server"
 	"wasimoff/broker/provider"
 	"wasimoff/broker/scheduler"
+	"wasimoff/broker/storage"
 )
 
 func main() {
@@ -20,7 +24,7 @@ func main() {
 
 	// create a new broker on a new http handler
 	mux := http.NewServeMux()
-	broker, err := server.NewServer(mux, conf.HttpListen, conf.HttpCert, conf.HttpKey)
+	broker, err := server.NewServer(mux, conf.HttpListen, conf.HttpCert, conf.HttpKey, conf.HttpClientCa)
 	if err != nil {
 		log.Fatalf("failed to start server: %s", err)
 	}
@@ -28,37 +32,104 @@ func main() {
 	// create a provider store and scheduler
 	store := provider.NewProviderStore(conf.FileStorage)
 	selector := scheduler.NewSimpleMatchSelector(store)
+	store.TaskTimeout = conf.TaskTimeout
+	store.MaxBodySize = conf.MaxBodySize << 20
+	store.PushFiles = conf.PushFiles
+	store.PrewarmFiles = conf.PrewarmFiles
+	store.ChunkSize = conf.ChunkSize << 10
+	store.ResumeGrace = conf.ResumeGrace
+	store.Storage.TTL = conf.StorageTTL
+	store.Storage.Redirect = conf.StorageRedirect
+	store.Storage.Quota = storage.Quota{
+		Total:    conf.StorageQuota << 20,
+		Uploader: conf.StorageUploaderQuota << 20,
+	}
+	if conf.History != "" {
+		store.History = history.NewBoltHistory(conf.History, conf.HistoryRetention)
+	}
 	// selector := scheduler.NewRoundRobinSelector(store)
 	// selector := scheduler.NewAnyFreeSelector(store)
 
+	// authenticate all api routes with bearer tokens, if configured
+	var authn *auth.Authenticator
+	if path, ok := strings.CutPrefix(conf.AuthTokens, "json:"); ok {
+		authn = auth.New(auth.NewFileTokens(path))
+	} else if conf.AuthTokens != "" {
+		tokens := auth.NewBoltTokens(conf.AuthTokens)
+		authn = auth.New(tokens)
+		mux.Handle("GET /api/tokens", authn.Require(tokens.ListHandler(), auth.Admin))
+		mux.Handle("POST /api/tokens", authn.Require(tokens.CreateHandler(), auth.Admin))
+		mux.Handle("DELETE /api/tokens/{name}", authn.Require(tokens.RevokeHandler(), auth.Admin))
+		log.Printf("Token API at %s/api/tokens", broker.Addr())
+	} else {
+		log.Println("WARNING: authentication is disabled, anyone can use the API!")
+	}
+
 	// provider transports
-	mux.HandleFunc("/api/provider/ws", provider.WebSocketHandler(store, conf.AllowedOrigins))
+	mux.Handle("/api/provider/ws", authn.Require(provider.WebSocketHandler(store, conf.AllowedOrigins, provider.Heartbeat{
+		Interval: conf.HeartbeatInterval,
+		Misses:   conf.HeartbeatMisses,
+	}), auth.Provider))
 	log.Printf("Provider socket: %s/api/provider/ws", broker.Addr())
 
 	// storage: serve files from and upload into store storage
-	mux.Handle("/api/storage/{filename}", store.Storage)
-	mux.HandleFunc("/api/storage/upload", scheduler.UploadHandler(store))
+	mux.Handle("/api/storage/{filename}", authn.Require(scheduler.DownloadHandler(store), auth.Provider, auth.Submit, auth.Upload))
+	mux.Handle("POST /api/storage/upload", authn.Require(scheduler.UploadHandler(store), auth.Upload))
+	mux.Handle("GET /api/storage", authn.Require(scheduler.FilesHandler(store), auth.Upload))
+	mux.Handle("POST /api/storage/alias", authn.Require(scheduler.AliasHandler(store), auth.Upload))
+	mux.Handle("DELETE /api/storage/{filename}", authn.Require(scheduler.DeleteHandler(store), auth.Upload))
 	log.Printf("Storage at %s/api/storage/...", broker.Addr())
 
+	// admission control is shared by all handlers, which accept submissions
+	admission := scheduler.NewAdmitter(scheduler.Admission{
+		Rate:     conf.AdmissionRate,
+		Burst:    conf.AdmissionBurst,
+		MaxTasks: conf.AdmissionMaxTasks,
+	})
+
 	// client offloading request handler
-	mux.HandleFunc("/api/client/run", scheduler.ExecHandler(store, &selector, conf.Benchmode))
+	mux.Handle("/api/client/run", authn.Require(scheduler.ExecHandler(store, &selector, scheduler.FairShare{
+		Weights:     conf.FairshareWeights,
+		Inflight:    conf.FairshareInflight,
+		MaxInflight: conf.FairshareMaxInflight,
+	}, admission, conf.Benchmode), auth.Submit))
 	log.Printf("Client API at %s/api/client/run", broker.Addr())
-	mux.HandleFunc("/api/client/ws", scheduler.ClientSocketHandler(store))
+	mux.Handle("/api/client/ws", authn.Require(scheduler.ClientSocketHandler(store, admission), auth.Submit))
 	log.Printf("Client socket: %s/api/client/ws", broker.Addr())
+	mux.Handle("GET /api/queue", authn.Require(scheduler.QueueHandler(admission), auth.Submit))
+	log.Printf("Queue status at %s/api/queue", broker.Addr())
+
+	// asynchronous jobs, which can be polled for progress and results
+	if conf.Benchmode == 0 {
+		jobs := scheduler.NewJobStore(store, admission)
+		mux.Handle("POST /api/jobs", authn.Require(jobs.SubmitHandler(), auth.Submit))
+		mux.Handle("GET /api/jobs", authn.Require(jobs.ListHandler(), auth.Submit))
+		mux.Handle("GET /api/jobs/{id}", authn.Require(jobs.StatusHandler(), auth.Submit))
+		mux.Handle("GET /api/jobs/{id}/results", authn.Require(jobs.ResultsHandler(), auth.Submit))
+		mux.Handle("DELETE /api/jobs/{id}", authn.Require(jobs.CancelHandler(), auth.Submit))
+		log.Printf("Job API at %s/api/jobs", broker.Addr())
+	}
+
+	// query the history of finished jobs and tasks
+	if store.History != nil {
+		mux.Handle("GET /api/history/jobs", authn.Require(store.History.JobsHandler(), auth.Admin))
+		mux.Handle("GET /api/history/tasks", authn.Require(store.History.TasksHandler(), auth.Admin))
+		log.Printf("History API at %s/api/history/...", broker.Addr())
+	}
 
 	// health message
 	mux.HandleFunc("/healthz", server.Healthz())
 
 	// pprof endpoint for debugging
 	if conf.Debug {
-		pprofHandler(mux, "/debug/pprof")
+		pprofHandler(mux, "/debug/pprof", authn)
 		log.Printf("DEBUG: broker PID is %d", os.Getpid())
 		log.Printf("DEBUG: pprof profiles at %s/debug/pprof", broker.Addr())
 	}
 
 	// prometheus metrics
 	if conf.Metrics {
-		prometheusHandler(mux, "/metrics", store)
+		prometheusHandler(mux, "/metrics", store, authn)
 		log.Printf("Prometheus metrics: %s/metrics", broker.Addr())
 	}
 
@@ -77,19 +148,19 @@ func main() {
 // ---
 
 // pprofHandler mimics what the net/http/pprof.init() does, but on a specified mux
-func pprofHandler(mux *http.ServeMux, prefix string) {
+func pprofHandler(mux *http.ServeMux, prefix string, authn *auth.Authenticator) {
 	// https://cs.opensource.google/go/go/+/refs/tags/go1.23.0:src/net/http/pprof/pprof.go;l=95
-	mux.HandleFunc(prefix+"/", pprof.Index)
-	mux.HandleFunc(prefix+"/cmdline", pprof.Cmdline)
-	mux.HandleFunc(prefix+"/profile", pprof.Profile)
-	mux.HandleFunc(prefix+"/symbol", pprof.Symbol)
-	mux.HandleFunc(prefix+"/trace", pprof.Trace)
+	mux.Handle(prefix+"/", authn.Require(http.HandlerFunc(pprof.Index), auth.Admin))
+	mux.Handle(prefix+"/cmdline", authn.Require(http.HandlerFunc(pprof.Cmdline), auth.Admin))
+	mux.Handle(prefix+"/profile", authn.Require(http.HandlerFunc(pprof.Profile), auth.Admin))
+	mux.Handle(prefix+"/symbol", authn.Require(http.HandlerFunc(pprof.Symbol), auth.Admin))
+	mux.Handle(prefix+"/trace", authn.Require(http.HandlerFunc(pprof.Trace), auth.Admin))
 
 }
 
 // metrics endpoint for Prometheus
-func prometheusHandler(mux *http.ServeMux, prefix string, store *provider.ProviderStore) {
-	mux.Handle(prefix, metrics.MetricsHandler(
+func prometheusHandler(mux *http.ServeMux, prefix string, store *provider.ProviderStore, authn *auth.Authenticator) {
+	mux.Handle(prefix, authn.Require(metrics.MetricsHandler(
 		// I'd love to put these funcs into the metrics package but that leads to an import cycle
 		// gaugeFunc for the providers
 		func() float64 {
@@ -104,6 +175,6 @@ func prometheusHandler(mux *http.ServeMux, prefix string, store *provider.Provid
 			})
 			return float64(sum)
 		},
-	))
+	), auth.Admin))
 
 }
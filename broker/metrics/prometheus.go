@@ -13,6 +13,24 @@ var Throughput = prometheus.NewGauge(prometheus.GaugeOpts{
 	Help: "Current total throughput of successful tasks/second.",
 })
 
+// tasks waiting in the dispatcher
+var QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "wasimoff_queue_depth",
+	Help: "Tasks waiting in the Dispatcher per priority class.",
+}, []string{"priority"})
+
+// submissions rejected by the admission control
+var Rejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "wasimoff_submissions_rejected_total",
+	Help: "Submissions rejected by the admission control per reason.",
+}, []string{"reason"})
+
+// history records dropped because the writer fell behind
+var HistoryDropped = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "wasimoff_history_dropped_total",
+	Help: "History records dropped because the database could not keep up.",
+})
+
 func MetricsHandler(providerFunc, workerFunc func() float64) http.Handler {
 
 	// number of connected providers
@@ -32,5 +50,14 @@ func MetricsHandler(providerFunc, workerFunc func() float64) http.Handler {
 	// current throughput
 	prometheus.MustRegister(Throughput)
 
+	// queued tasks
+	prometheus.MustRegister(QueueDepth)
+
+	// rejected submissions
+	prometheus.MustRegister(Rejected)
+
+	// dropped history records
+	prometheus.MustRegister(HistoryDropped)
+
 	return promhttp.Handler()
 }
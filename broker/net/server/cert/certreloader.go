@@ -3,6 +3,7 @@ package cert
 import (
 	"crypto/sha256"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/hex"
 	"fmt"
 	"log"
@@ -18,31 +19,48 @@ import (
 type CertReloader struct {
 	sync.RWMutex
 	cert *tls.Certificate
+	// optional pool to verify client certificates
+	clientCAs *x509.CertPool
 	// keep file paths to reload
 	certPath string
 	keyPath  string
+	caPath   string
 }
 
-func NewCertReloader(certPath, keyPath string) (*CertReloader, error) {
+// NewCertReloader loads a keypair or creates an ephemeral one. If caPath is given,
+// clients may present a certificate, which is verified against the CAs in that file.
+func NewCertReloader(certPath, keyPath, caPath string) (*CertReloader, error) {
 	// instantiate by "reloading" for the first time
-	cr := &CertReloader{certPath: certPath, keyPath: keyPath}
+	cr := &CertReloader{certPath: certPath, keyPath: keyPath, caPath: caPath}
 	if err := cr.reload(); err != nil {
 		return nil, err
 	}
-	// add handlers to reload
-	if !cr.IsSelfsigned() {
-		// reload from filesystem on SIGHUP
+	if err := cr.reloadCA(); err != nil {
+		return nil, err
+	}
+	// reload files from filesystem on SIGHUP
+	if !cr.IsSelfsigned() || cr.caPath != "" {
 		go func() {
 			hup := make(chan os.Signal, 1)
 			signal.Notify(hup, syscall.SIGHUP)
 			for range hup {
-				log.Printf("Received SIGHUP, reloading TLS keypair from %q and %q", cr.certPath, cr.keyPath)
-				if err := cr.reload(); err != nil {
-					log.Printf("ERR: failed TLS reload, keeping old keypair: %v", err)
+				if !cr.IsSelfsigned() {
+					log.Printf("Received SIGHUP, reloading TLS keypair from %q and %q", cr.certPath, cr.keyPath)
+					if err := cr.reload(); err != nil {
+						log.Printf("ERR: failed TLS reload, keeping old keypair: %v", err)
+					}
+				}
+				if cr.caPath != "" {
+					log.Printf("Received SIGHUP, reloading client CAs from %q", cr.caPath)
+					if err := cr.reloadCA(); err != nil {
+						log.Printf("ERR: failed client CA reload, keeping old CAs: %v", err)
+					}
 				}
 			}
 		}()
-	} else {
+	}
+	// add handlers to recreate ephemeral keys
+	if cr.IsSelfsigned() {
 		log.Printf("using ephemeral keypair: %s", cr.Certhash())
 		// periodically recreate ephemeral
 		normaltick := 24 * time.Hour
@@ -94,6 +112,25 @@ func (cr *CertReloader) reload() (err error) {
 	return nil
 }
 
+// reloadCA reads the pool of CAs for client certificates, if a path is given
+func (cr *CertReloader) reloadCA() error {
+	if cr.caPath == "" {
+		return nil
+	}
+	pem, err := os.ReadFile(cr.caPath)
+	if err != nil {
+		return fmt.Errorf("failed loading client CAs: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("failed loading client CAs: no certificates in %q", cr.caPath)
+	}
+	cr.Lock()
+	defer cr.Unlock()
+	cr.clientCAs = pool
+	return nil
+}
+
 // IsSelfsigned returns if the cert is ephemeral or loaded from disk
 func (cr *CertReloader) IsSelfsigned() bool {
 	// no need for a separate bool in the struct
@@ -115,9 +152,25 @@ func (cr *CertReloader) GetCertificateFunc() func(*tls.ClientHelloInfo) (*tls.Ce
 	}
 }
 
-// GetTLSConfig returns a `tls.Config`, which uses this reloader for its certificate
+// GetTLSConfig returns a `tls.Config`, which uses this reloader for its certificate.
+// Client certificates are requested and verified if they are given, so clients
+// without one can still connect and authenticate otherwise.
 func (cr *CertReloader) GetTLSConfig() *tls.Config {
-	return &tls.Config{
+	config := &tls.Config{
 		GetCertificate: cr.GetCertificateFunc(),
 	}
+	if cr.caPath != "" {
+		// the pool can't be swapped in a shared config, so clone it per connection
+		config.GetConfigForClient = func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			cr.RLock()
+			defer cr.RUnlock()
+			return &tls.Config{
+				GetCertificate: cr.GetCertificateFunc(),
+				ClientAuth:     tls.VerifyClientCertIfGiven,
+				ClientCAs:      cr.clientCAs,
+				NextProtos:     []string{"h2", "http/1.1"}, // as added by http.Server
+			}, nil
+		}
+	}
+	return config
 }
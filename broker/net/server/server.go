@@ -14,8 +14,9 @@ type Server struct {
 	cr   *cert.CertReloader
 }
 
-// Create a new Server with optional TLS using the CertReloader.
-func NewServer(handler http.Handler, httpAddr, httpCert, httpKey string) (s *Server, err error) {
+// Create a new Server with optional TLS using the CertReloader. Client certificates
+// are verified against the CAs in httpClientCA, if given.
+func NewServer(handler http.Handler, httpAddr, httpCert, httpKey, httpClientCA string) (s *Server, err error) {
 
 	// simple http/tls server
 	s = &Server{
@@ -26,8 +27,8 @@ func NewServer(handler http.Handler, httpAddr, httpCert, httpKey string) (s *Ser
 	}
 
 	// maybe load a tls config for the server
-	if httpCert != "" || httpKey != "" {
-		s.cr, err = cert.NewCertReloader(httpCert, httpKey)
+	if httpCert != "" || httpKey != "" || httpClientCA != "" {
+		s.cr, err = cert.NewCertReloader(httpCert, httpKey, httpClientCA)
 		if err != nil {
 			return nil, fmt.Errorf("cannot load tls keypair: %w", err)
 		}
@@ -0,0 +1,158 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequestHandler handles a single incoming request and returns a response or an
+// error, which is sent back to the caller in Envelope.error.
+type RequestHandler func(ctx context.Context, request proto.Message) (proto.Message, error)
+
+// registeredHandler is a RequestHandler with optional concurrency limit.
+type registeredHandler struct {
+	handler RequestHandler
+	tickets chan struct{} // nil if unlimited
+	backlog chan struct{} // requests running or waiting for a ticket
+}
+
+// handlerBacklog is the number of requests that may wait for a ticket of a
+// limited handler; further requests are rejected until the backlog drains.
+const handlerBacklog = 1024
+
+// handlerTable maps protobuf message names to their registered handlers.
+type handlerTable struct {
+	sync.RWMutex
+	handlers map[protoreflect.FullName]*registeredHandler
+}
+
+// -------------------- registration -------------------- >>
+
+// Handle registers a handler for incoming requests with the given message name.
+// At most `limit` requests are handled concurrently and up to handlerBacklog more
+// are waiting, others are rejected; use zero for no limit. Registering a name
+// twice replaces the previous handler.
+func (m *Messenger) Handle(name protoreflect.FullName, limit int, handler RequestHandler) {
+	if handler == nil {
+		log.Panic("Messenger.Handle: handler is nil")
+	}
+	h := &registeredHandler{handler: handler}
+	if limit > 0 {
+		// use "tickets" to limit the number of concurrent requests
+		h.tickets = make(chan struct{}, limit)
+		for len(h.tickets) < cap(h.tickets) {
+			h.tickets <- struct{}{}
+		}
+		h.backlog = make(chan struct{}, limit+handlerBacklog)
+	}
+	m.handlers.Lock()
+	defer m.handlers.Unlock()
+	if m.handlers.handlers == nil {
+		m.handlers.handlers = make(map[protoreflect.FullName]*registeredHandler)
+	}
+	m.handlers.handlers[name] = h
+}
+
+// HandleFunc registers a typed handler on the Messenger. The message name is taken
+// from the request type parameter, so the handler only ever receives that type.
+func HandleFunc[Req, Res proto.Message](m *Messenger, limit int, handler func(ctx context.Context, request Req) (Res, error)) {
+	var zero Req
+	name := zero.ProtoReflect().Descriptor().FullName()
+	m.Handle(name, limit, func(ctx context.Context, request proto.Message) (proto.Message, error) {
+		typed, ok := request.(Req)
+		if !ok {
+			return nil, fmt.Errorf("unexpected request type for %s", name)
+		}
+		response, err := handler(ctx, typed)
+		if err != nil || isNil(response) {
+			// don't wrap a typed nil in the interface
+			return nil, err
+		}
+		return response, nil
+	})
+}
+
+// lookup a registered handler by message name
+func (m *Messenger) handler(name protoreflect.FullName) *registeredHandler {
+	m.handlers.RLock()
+	defer m.handlers.RUnlock()
+	return m.handlers.handlers[name]
+}
+
+// -------------------- dispatch -------------------- >>
+
+// ServeRequests takes incoming requests from the queue and dispatches them to the
+// registered handlers, each in its own goroutine. Requests without a handler or
+// beyond the backlog of a limited handler are answered with an error immediately.
+// It blocks until the Messenger is closed, so register all handlers first and then
+// call it in a gofunc. Don't read from Requests() yourself while this loop is running.
+func (m *Messenger) ServeRequests() {
+	for {
+		select {
+		case <-m.Closing():
+			return
+		case request := <-m.requests:
+			name := request.Request.ProtoReflect().Descriptor().FullName()
+			h := m.handler(name)
+			if h != nil && h.backlog != nil {
+				select {
+				case h.backlog <- struct{}{}:
+				default:
+					go request.Respond(m.lifetime.Context, nil, fmt.Errorf("too many pending %s requests", name))
+					continue
+				}
+			}
+			go m.dispatch(request, h)
+		}
+	}
+}
+
+// dispatch a single request to its handler and send the response
+func (m *Messenger) dispatch(request IncomingRequest, h *registeredHandler) {
+	if h != nil && h.backlog != nil {
+		defer func() { <-h.backlog }()
+	}
+
+	// each request gets a context, which is cancelled when the connection closes
+	ctx, cancel := context.WithCancel(m.lifetime.Context)
+	defer cancel()
+
+	response, err := m.handle(ctx, h, request.Request)
+	if err := request.Respond(ctx, response, err); err != nil && m.Err() == nil {
+		log.Printf("WARN: handler[%s]: responding to request %d failed: %s", m.transport.Addr(), request.Seq, err)
+	}
+}
+
+// handle calls the registered handler while respecting its concurrency limit
+// and recovers from any panics by turning them into errors
+func (m *Messenger) handle(ctx context.Context, h *registeredHandler, request proto.Message) (response proto.Message, err error) {
+	name := request.ProtoReflect().Descriptor().FullName()
+	if h == nil {
+		return nil, fmt.Errorf("unsupported request type: %s", name)
+	}
+
+	// wait for a ticket, if limited
+	if h.tickets != nil {
+		select {
+		case <-h.tickets:
+			defer func() { h.tickets <- struct{}{} }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// recover from panics in the handler
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERR: handler[%s]: panic in %s: %v\n%s", m.transport.Addr(), name, r, debug.Stack())
+			response, err = nil, fmt.Errorf("panic in %s handler: %v", name, r)
+		}
+	}()
+	return h.handler(ctx, request)
+}
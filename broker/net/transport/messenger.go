@@ -8,8 +8,6 @@ package transport
 // Copyright 2009 The Go Authors. All rights reserved.
 // Modified 2024 Anton Semjonov
 
-// TODO: handling of incoming requests (case pb.Envelope_Request) is not implemented yet
-
 import (
 	"context"
 	"crypto/rand"
@@ -20,6 +18,7 @@ import (
 	"reflect"
 	"sync"
 	"sync/atomic"
+	"time"
 	wasimoff "wasimoff/proto/v1"
 
 	"google.golang.org/protobuf/proto"
@@ -32,8 +31,10 @@ type Messenger struct {
 	transport Transport // the underlying transport
 	lifetime  Lifetime  // cancellable long context
 
-	events   chan proto.Message   // incoming event messages
-	requests chan IncomingRequest // incoming request messages
+	events     chan proto.Message   // incoming event messages
+	waitEvents atomic.Bool          // wait for room on events instead of dropping
+	requests   chan IncomingRequest // incoming request messages
+	handlers   handlerTable         // registered request handlers
 
 	sendMutex       sync.Mutex        // only one sender
 	envelope        wasimoff.Envelope // reusable for sending
@@ -84,7 +85,7 @@ func (m *Messenger) Closing() <-chan struct{} {
 }
 
 // Close the Messenger and underlying Transport.
-// The receiver loop will tidy up pending requests.
+// The receiver loop will tidy up pending requests and close the Events channel.
 func (m *Messenger) Close(reason error) {
 	if reason == nil {
 		reason = ErrLifetimeEnded
@@ -99,7 +100,6 @@ func (m *Messenger) Close(reason error) {
 		m.transport.Close(fmt.Errorf("closed from Messenger: %w", reason))
 		m.lifetime.Cancel(reason)
 		<-m.Closing()
-		close(m.events)
 	}
 }
 
@@ -110,8 +110,24 @@ func (m *Messenger) Events() <-chan proto.Message {
 	return m.events
 }
 
-// Write an incoming Event to the channel but never block when doing so!
+// WaitForEvents makes the receiver wait for room on the Events channel instead of
+// dropping events when it is full. A slow reader then applies backpressure to the
+// sender and no Response overtakes an earlier Event, but the channel must be read
+// continuously or nothing else is received either.
+func (m *Messenger) WaitForEvents() {
+	m.waitEvents.Store(true)
+}
+
+// Write an incoming Event to the channel but never block when doing so, unless
+// WaitForEvents was set!
 func (m *Messenger) putEvent(event proto.Message) {
+	if m.waitEvents.Load() {
+		select {
+		case m.events <- event: // ok
+		case <-m.Closing():
+		}
+		return
+	}
 	select {
 	case m.events <- event: // ok
 	default:
@@ -128,7 +144,8 @@ type IncomingRequest struct {
 	Respond func(ctx context.Context, response proto.Message, err error) error
 }
 
-// Get a receive-only channel of incoming Events to handle.
+// Get a receive-only channel of incoming Requests to handle manually.
+// Use either this channel or ServeRequests() with registered handlers.
 func (m *Messenger) Requests() <-chan IncomingRequest {
 	return m.requests
 }
@@ -158,13 +175,17 @@ func (m *Messenger) putRequest(seq uint64, request proto.Message) {
 // -------------------- receiver -------------------- >>
 
 // The receiver will continuously read from the Transport and parse incoming
-// messages. Responses are routed to their pending requests and Events are emitted
-// on a channel. Incoming Requests are not implemented yet and are immediately
-// responded to with an Error message. Call receiver in a gofunc after instantiation.
+// messages. Responses are routed to their pending requests, Events are emitted
+// on a channel and Requests are queued for ServeRequests(). Requests with an
+// unknown payload type are immediately responded to with an Error message.
+// Call receiver in a gofunc after instantiation.
 func (m *Messenger) receiver() {
 	var receiveErr error
 	var envelope wasimoff.Envelope
 
+	// only the receiver sends events, so it closes the channel when it's done
+	defer close(m.events)
+
 	for receiveErr == nil {
 
 		// receive the next letter and switch by message type
@@ -174,13 +195,20 @@ func (m *Messenger) receiver() {
 		switch envelope.GetType() {
 
 		case wasimoff.Envelope_Request:
+			seq := envelope.GetSequence()
 			request, err := envelope.Payload.UnmarshalNew()
 			if err != nil {
-				// this usually means that the message type is not known
-				receiveErr = fmt.Errorf("unpacking request payload: %w", err)
-				break
+				// this usually means that the message type is not known,
+				// which is the caller's problem and not a reason to close
+				go m.SendResponse(m.lifetime.Context, seq, nil, fmt.Errorf("unpacking request payload: %w", err))
+				continue
 			}
-			m.putRequest(*envelope.Sequence, request)
+			// answer heartbeats directly, so they don't wait behind queued requests
+			if _, ok := request.(*wasimoff.PingRequest); ok {
+				go m.SendResponse(m.lifetime.Context, seq, &wasimoff.PingResponse{}, nil)
+				continue
+			}
+			m.putRequest(seq, request)
 			continue
 
 		case wasimoff.Envelope_Event:
@@ -208,7 +236,7 @@ func (m *Messenger) receiver() {
 			}
 			// unpack the payload into expected response
 			if envelope.Error != nil {
-				call.Error = errors.New(*envelope.Error)
+				call.Error = RemoteError(*envelope.Error)
 			} else {
 				err := envelope.Payload.UnmarshalTo(call.Response)
 				// ignore payload err if this is an error response anyway
@@ -247,7 +275,6 @@ func (m *Messenger) receiver() {
 		m.transport.Close(receiveErr)
 		m.lifetime.Cancel(receiveErr)
 		<-m.Closing()
-		close(m.events)
 	}
 	m.pendingMutex.Unlock()
 	m.sendMutex.Unlock()
@@ -272,9 +299,9 @@ func (m *Messenger) send(ctx context.Context, seq *uint64, mt *wasimoff.Envelope
 	defer m.sendMutex.Unlock()
 	m.envelope.Sequence = seq
 	m.envelope.Type = mt
-	if payload != nil {
-		m.envelope.Payload = payload
-	}
+	// always overwrite both fields, so nothing leaks from a previous message
+	m.envelope.Payload = payload
+	m.envelope.Error = nil
 	if reqErr != nil {
 		m.envelope.Error = proto.String(reqErr.Error())
 	}
@@ -349,6 +376,30 @@ func (m *Messenger) RequestSync(ctx context.Context, request, response proto.Mes
 	}
 }
 
+// Ping sends a heartbeat Request and measures the round-trip time. Any Response
+// proves that the other side is alive, even an error from a peer which does not
+// know the PingRequest message yet.
+func (m *Messenger) Ping(ctx context.Context) (rtt time.Duration, err error) {
+	start := time.Now()
+	select {
+	case call := <-m.SendRequest(ctx, &wasimoff.PingRequest{}, &wasimoff.PingResponse{}, make(chan *PendingCall, 1)).Done:
+		var remote RemoteError
+		if call.Error != nil && !errors.As(call.Error, &remote) {
+			return 0, call.Error
+		}
+		return time.Since(start), nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// RemoteError is an error message received in a Response from the other side.
+type RemoteError string
+
+func (e RemoteError) Error() string {
+	return string(e)
+}
+
 // -------------------- pending calls -------------------- >>
 
 // PendingCall is used by Request to have something to write the response to.
@@ -47,3 +47,25 @@ func ProxiedAddr(req *http.Request) string {
 	}
 	return req.RemoteAddr
 }
+
+// BearerTransport adds an API token to all requests to the Broker's host. Other
+// hosts, like presigned storage redirects, must not see the token.
+type BearerTransport struct {
+	Token string
+	Host  string            // host of the Broker, with port
+	Base  http.RoundTripper // uses http.DefaultTransport if nil
+}
+
+func (t *BearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if t.Token == "" || req.URL.Host != t.Host || req.Header.Get("authorization") != "" {
+		return base.RoundTrip(req)
+	}
+	// a RoundTripper must not modify the original request
+	req = req.Clone(req.Context())
+	req.Header.Set("authorization", "Bearer "+t.Token)
+	return base.RoundTrip(req)
+}
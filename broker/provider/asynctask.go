@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"log"
+	"time"
 	wasimoff "wasimoff/proto/v1"
 )
 
@@ -14,6 +15,12 @@ type AsyncTask struct {
 	Response *wasimoff.Task_Response // response containing either an error or specific output
 	Error    error                   // errors encountered internally during scheduling or RPC
 	done     chan *AsyncTask         // received itself when complete
+
+	// information about the execution, e.g. for the history
+	Created  time.Time     // when the task was created
+	Started  time.Time     // when the task was last sent to a Provider
+	Provider string        // address of the Provider which ran the task last
+	Runtime  time.Duration // how long the task ran on that Provider
 }
 
 // NewAsyncTask creates a new call struct for a scheduler
@@ -32,7 +39,13 @@ func NewAsyncTask(
 	if ctx == nil {
 		log.Panic("AsyncTask: context is nil")
 	}
-	return &AsyncTask{ctx, args, res, nil, done}
+	return &AsyncTask{
+		Context:  ctx,
+		Request:  args,
+		Response: res,
+		done:     done,
+		Created:  time.Now(),
+	}
 }
 
 // Done signals on the channel that this call is complete
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"wasimoff/broker/net/transport"
+	"wasimoff/broker/storage"
+	wasimoff "wasimoff/proto/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Files larger than the chunk size are transferred in multiple FileChunks, so a
+// single message never occupies the connection for long. The receiving side keeps
+// partial files by their ref and reports its progress, so an interrupted transfer
+// continues where it stopped instead of starting over, even after a reconnect.
+
+// errChunksUnsupported is returned when the Provider doesn't handle FileChunks
+var errChunksUnsupported = errors.New("chunked upload not supported")
+
+// uploadChunked pushes a large file in chunks, starting at the offset that the
+// Provider already holds from a previous attempt; only one chunk is read at a time
+func (p *Provider) uploadChunked(content io.ReadSeeker, info *storage.FileInfo) (err error) {
+	ref, total := info.Ref, uint64(info.Size)
+
+	// a chunk without contents queries the progress
+	offset, err := p.uploadChunk(ref, info.Media, total, 0, nil)
+	var remote transport.RemoteError
+	if errors.As(err, &remote) {
+		return fmt.Errorf("%w: %w", errChunksUnsupported, err)
+	}
+
+	blob := make([]byte, p.chunkSize)
+	for err == nil && offset < total {
+		chunk := blob[:min(uint64(p.chunkSize), total-offset)]
+		if _, err = content.Seek(int64(offset), io.SeekStart); err != nil {
+			break
+		}
+		if _, err = io.ReadFull(content, chunk); err != nil {
+			break
+		}
+		var received uint64
+		received, err = p.uploadChunk(ref, info.Media, total, offset, chunk)
+		if err == nil && received == offset {
+			err = fmt.Errorf("no progress at offset %d", offset)
+		}
+		offset = received
+	}
+	if err != nil {
+		return fmt.Errorf("provider.Upload %q failed: %w", ref, err)
+	}
+	return nil
+}
+
+// uploadChunk sends a single chunk and returns the number of bytes the Provider has
+func (p *Provider) uploadChunk(ref, media string, total, offset uint64, blob []byte) (uint64, error) {
+	args := wasimoff.FileChunkUploadRequest{Chunk: &wasimoff.FileChunk{
+		Ref:    &ref,
+		Media:  &media,
+		Total:  &total,
+		Offset: &offset,
+		Blob:   blob,
+	}}
+	response := wasimoff.FileChunkUploadResponse{}
+	if err := p.messenger.RequestSync(p.lifetime.Context, &args, &response); err != nil {
+		return 0, err
+	}
+	if response.GetErr() != "" {
+		return 0, fmt.Errorf("at Provider: %s", response.GetErr())
+	}
+	if response.GetReceived() > total {
+		return 0, fmt.Errorf("at Provider: received %d of %d bytes", response.GetReceived(), total)
+	}
+	return response.GetReceived(), nil
+}
+
+// serveChunk answers a Provider's request for a part of a file in storage, so
+// large files can be downloaded incrementally; the length is capped to the chunk size
+func (s *ProviderStore) serveChunk(ctx context.Context, request *wasimoff.FileChunkDownloadRequest) (*wasimoff.FileChunkDownloadResponse, error) {
+	file, info, err := s.Storage.Open(request.GetFile())
+	if errors.Is(err, storage.ErrNotFound) {
+		return &wasimoff.FileChunkDownloadResponse{Err: proto.String("file not found in storage")}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	// read the requested range
+	total, offset := uint64(info.Size), request.GetOffset()
+	if offset > total {
+		return nil, fmt.Errorf("offset %d is beyond the file size %d", offset, total)
+	}
+	length := min(request.GetLength(), total-offset)
+	if s.ChunkSize > 0 {
+		length = min(length, uint64(s.ChunkSize))
+	}
+	blob := make([]byte, length)
+	if _, err := file.Seek(int64(offset), io.SeekStart); err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	if _, err := io.ReadFull(file, blob); err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	return &wasimoff.FileChunkDownloadResponse{Chunk: &wasimoff.FileChunk{
+		Ref:    &info.Ref,
+		Media:  &info.Media,
+		Total:  &total,
+		Offset: &offset,
+		Blob:   blob,
+	}}, nil
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+	"wasimoff/broker/storage"
+)
+
+// Missing files are pushed to a Provider before a task runs on it. Uploads are
+// deduplicated, so concurrent tasks on the same Provider wait for a single one.
+// The measured throughput of past uploads is used to estimate how long it takes
+// to transfer files, so schedulers can prefer Providers which are ready sooner.
+
+// assumedBandwidth is used to estimate transfer times until an upload was measured
+const assumedBandwidth = 10 << 20 // bytes per second
+
+// smaller uploads are dominated by latency and not used to measure the bandwidth
+const minMeasuredUpload = 256 << 10
+
+// upload is a pending upload of a single file to a Provider
+type upload struct {
+	done chan struct{}
+	err  error
+}
+
+// Has returns if this Provider *is known* to have a certain file, without re-probing
+func (p *Provider) Has(file string) bool {
+	p.filesMutex.RLock()
+	defer p.filesMutex.RUnlock()
+	_, ok := p.files[file]
+	return ok
+}
+
+// addFiles marks files as present on the Provider
+func (p *Provider) addFiles(refs ...string) {
+	p.filesMutex.Lock()
+	defer p.filesMutex.Unlock()
+	for _, ref := range refs {
+		p.files[ref] = struct{}{}
+	}
+}
+
+// removeFiles marks files as absent on the Provider
+func (p *Provider) removeFiles(refs ...string) {
+	p.filesMutex.Lock()
+	defer p.filesMutex.Unlock()
+	for _, ref := range refs {
+		delete(p.files, ref)
+	}
+}
+
+// Require makes sure that the Provider has all the files, uploading missing ones
+// from Storage. It returns early if the context is cancelled but the uploads
+// continue in the background for other tasks.
+func (p *Provider) Require(ctx context.Context, refs ...string) error {
+	if p.storage == nil {
+		return nil // Provider fetches files itself
+	}
+	for _, ref := range refs {
+		if p.Has(ref) {
+			continue
+		}
+		up := p.startUpload(ref)
+		select {
+		case <-up.done:
+			if up.err != nil {
+				return up.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// startUpload returns a pending upload of a file or starts a new one
+func (p *Provider) startUpload(ref string) *upload {
+	p.filesMutex.Lock()
+	defer p.filesMutex.Unlock()
+	if up, ok := p.uploads[ref]; ok {
+		return up
+	}
+	up := &upload{done: make(chan struct{})}
+	p.uploads[ref] = up
+	go func() {
+		if content, info, err := p.storage.Open(ref); err != nil {
+			up.err = fmt.Errorf("provider.Require %q: %w", ref, err)
+		} else {
+			up.err = p.Upload(content, info)
+			content.Close()
+		}
+		p.filesMutex.Lock()
+		delete(p.uploads, ref)
+		p.filesMutex.Unlock()
+		close(up.done)
+	}()
+	return up
+}
+
+// measureUpload updates the bandwidth estimate with the duration of an upload
+func (p *Provider) measureUpload(size int, elapsed time.Duration) {
+	elapsed -= p.RTT()
+	if size < minMeasuredUpload || elapsed <= 0 {
+		return
+	}
+	sample := int64(float64(size) / elapsed.Seconds())
+	// exponentially weighted moving average, so outliers don't count too much
+	if previous := p.bandwidth.Load(); previous > 0 {
+		sample = (3*previous + sample) / 4
+	}
+	p.bandwidth.Store(sample)
+}
+
+// TransferTime estimates how long it takes to upload files of a total size
+// to this Provider. It is zero when there is nothing to transfer.
+func (p *Provider) TransferTime(size int64) time.Duration {
+	if size <= 0 {
+		return 0
+	}
+	bandwidth := p.bandwidth.Load()
+	if bandwidth <= 0 {
+		bandwidth = assumedBandwidth
+	}
+	return p.RTT() + time.Duration(float64(size)/float64(bandwidth)*float64(time.Second))
+}
+
+// prewarm uploads the most recently used files to a newly connected Provider,
+// so the first tasks don't need to wait for them
+func (s *ProviderStore) prewarm(p *Provider) {
+	if s.PrewarmFiles <= 0 {
+		return
+	}
+	files := slices.DeleteFunc(s.Storage.List(), func(info storage.FileInfo) bool {
+		return info.Used == nil
+	})
+	slices.SortFunc(files, func(a, b storage.FileInfo) int {
+		return b.Used.Compare(*a.Used)
+	})
+	refs := []string{}
+	for _, info := range files[:min(len(files), s.PrewarmFiles)] {
+		refs = append(refs, info.Ref)
+	}
+	if err := p.Require(p.lifetime.Context, refs...); err != nil && p.Err() == nil {
+		log.Printf("[%s] WARN: prewarming files failed: %s", p.Get(Address), err)
+	}
+}
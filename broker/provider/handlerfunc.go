@@ -1,19 +1,34 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"slices"
 	"time"
+	"wasimoff/broker/auth"
 	"wasimoff/broker/net/transport"
 	wasimoff "wasimoff/proto/v1"
+
+	"google.golang.org/protobuf/proto"
 )
 
+// ErrUnresponsive is the cause of closing a Provider which missed its heartbeats.
+var ErrUnresponsive = errors.New("provider unresponsive")
+
+// Heartbeat configures the liveness checks of connected Providers. A PingRequest
+// is sent in every interval and must be answered before the next one is due.
+type Heartbeat struct {
+	Interval time.Duration // time between pings; zero disables the heartbeat
+	Misses   int           // close the Provider after this many consecutive misses
+}
+
 // WebSocketHandler returns a http.HandlerFunc to be used on a route that shall serve
 // as an endpoint for Providers to connect to. This particular handler uses WebSocket
 // transport with either Protobuf or JSON encoding, negotiated using subprotocol strings.
-func WebSocketHandler(store *ProviderStore, origins []string) http.HandlerFunc {
+func WebSocketHandler(store *ProviderStore, origins []string, heartbeat Heartbeat) http.HandlerFunc {
 
 	// warn about wildcard origin pattern
 	if slices.Contains(origins, "*") {
@@ -31,23 +46,55 @@ func WebSocketHandler(store *ProviderStore, origins []string) http.HandlerFunc {
 		}
 		msg := transport.NewMessengerInterface(wst)
 
-		// setup the provider instance
+		// setup the provider instance, named and keyed by a verified client certificate
 		provider := NewProvider(msg)
+		if id := auth.Certificate(r); id != nil {
+			provider.info[ID] = certKey + id.Name
+			provider.info[Name] = id.Name
+			provider.certified = true
+			log.Printf("[%s] Provider certificate: %s", addr, id.Name)
+		}
+		if id := auth.Get(r); id != nil {
+			provider.owner = id.Name
+		}
+		provider.timeout = store.TaskTimeout
+		provider.chunkSize = store.ChunkSize
+		if store.PushFiles {
+			provider.storage = store.Storage
+		}
 		defer provider.Close(nil)
 
 		// handle incoming event messages
 		go provider.eventTransmitter()
 
+		// evict the provider if it stops responding
+		go provider.heartbeat(heartbeat)
+
+		// serve file downloads from storage, other requests are rejected
+		transport.HandleFunc(msg, 4, store.serveDownload)
+		transport.HandleFunc(msg, 4, store.serveChunk)
+		go msg.ServeRequests()
+
 		// get the list of available files on provider
 		if _, err = provider.ListFiles(); err != nil {
 			log.Printf("[%s] New Provider: %s", addr, err)
 			return
 		}
 
+		// wait for a persistent id to resume a previous session
+		select {
+		case <-provider.hello:
+		case <-time.After(helloTimeout):
+			provider.greet("") // too late, the address is used
+		case <-provider.Closing():
+			return
+		}
+
 		// add provider to the store
 		log.Printf("[%s] New Provider connected using WebSocket", addr)
 		store.Add(provider)
 		defer store.Remove(provider)
+		go store.prewarm(provider)
 
 		// wait until the session ends to defer cleanup
 		select {
@@ -60,76 +107,99 @@ func WebSocketHandler(store *ProviderStore, origins []string) http.HandlerFunc {
 	}
 }
 
-// eventTransmitter loops to receive incoming messages or send updates to the provider
-func (p *Provider) eventTransmitter() {
-
-	// create a ticker to send regular updates
-	ticker := time.NewTicker(time.Second)
+// heartbeat regularly pings the provider to measure the round-trip time and closes
+// it when too many consecutive pings are missed. Tasks in-flight on this provider
+// then fail with a connection error and are rescheduled by the Dispatcher.
+func (p *Provider) heartbeat(config Heartbeat) {
+	if config.Interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(config.Interval)
 	defer ticker.Stop()
 
+	missed := 0
 	for {
 		select {
 
-		// send regular updates
-		case <-ticker.C:
-			// TODO, this branch was used for ClusterInfo originally
+		case <-p.Closing():
+			return
 
-		// reject incoming requests
-		case request, ok := <-p.messenger.Requests():
-			if !ok {
-				return // channel is closing, quit
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(p.lifetime.Context, config.Interval)
+			rtt, err := p.messenger.Ping(ctx)
+			cancel()
+			if err == nil {
+				p.rtt.Store(int64(rtt))
+				missed = 0
+				continue
 			}
-			// log.Printf("[%s] Request %d: %s", p.messenger.Addr(), request.Seq, prototext.Format(request.Request))
-			request.Respond(p.lifetime.Context, nil, fmt.Errorf("requests not supported on client socket"))
-
-		// handle incoming events
-		case event, ok := <-p.messenger.Events():
-			if !ok {
-				return // channel is closing, quit
+			if p.Err() != nil {
+				return // closed in the meantime
 			}
-			switch ev := event.(type) {
-
-			case *wasimoff.Event_GenericMessage:
-				// generic text message
-				log.Printf("[%s] says: %s", p.Get(Address), ev.GetMessage())
-
-			case *wasimoff.Event_ProviderHello:
-				// initial hello with platform information
-				if v := ev.GetName(); v != "" {
-					p.info[Name] = v
-				}
-				if v := ev.GetUseragent(); v != "" {
-					p.info[UserAgent] = v
-					log.Printf("[%s] UserAgent: %s", p.Get(Address), v)
-				}
-
-			case *wasimoff.Event_ProviderResources:
-				// TODO: set active tasks
-				// The problem is that you can't really "set" a semaphore, so possibly
-				// need to switch to a manual atomic, when providers are allowed to receive
-				// tasks from multiple sources and we can't track it ourselves anymore.
-				if ev.Concurrency != nil {
-					log.Printf("[%s] Workers: %d", p.Get(Address), *ev.Concurrency)
-					p.limiter.SetLimit(int(*ev.Concurrency))
-				}
-
-			case *wasimoff.Event_FileSystemUpdate:
-				// update about stored files on provider
-				for _, file := range ev.GetAdded() {
-					// first add
-					p.files[file] = struct{}{}
-				}
-				for _, file := range ev.GetRemoved() {
-					// then remove, i.e. err on _not_ having the file
-					delete(p.files, file)
-				}
-
-			default:
-				log.Printf("[%s] WARN: unknown event: %s", p.Get(Address), event.ProtoReflect().Descriptor().FullName())
-
+			missed++
+			log.Printf("[%s] WARN: missed heartbeat %d/%d: %s", p.Get(Address), missed, config.Misses, err)
+			if missed >= config.Misses {
+				p.Close(fmt.Errorf("%w: missed %d heartbeats", ErrUnresponsive, missed))
+				return
 			}
 
 		}
 	}
+}
+
+// serveDownload answers a Provider's request for a file in storage, so Providers
+// can fetch files by name or ref lazily when a task needs them
+func (s *ProviderStore) serveDownload(ctx context.Context, request *wasimoff.FileDownloadRequest) (*wasimoff.FileDownloadResponse, error) {
+	file := s.Storage.Get(request.GetFile())
+	if file == nil {
+		return &wasimoff.FileDownloadResponse{Err: proto.String("file not found in storage")}, nil
+	}
+	return &wasimoff.FileDownloadResponse{Download: &wasimoff.File{
+		Ref:   proto.String(file.Ref()),
+		Media: &file.Media,
+		Blob:  file.Bytes,
+	}}, nil
+}
+
+// eventTransmitter loops to receive incoming messages from the provider
+func (p *Provider) eventTransmitter() {
+	for event := range p.messenger.Events() {
+		switch ev := event.(type) {
 
+		case *wasimoff.Event_GenericMessage:
+			// generic text message
+			log.Printf("[%s] says: %s", p.Get(Address), ev.GetMessage())
+
+		case *wasimoff.Event_ProviderHello:
+			// initial hello with platform information
+			if v := ev.GetName(); v != "" && !p.certified {
+				p.info[Name] = v
+			}
+			if v := ev.GetUseragent(); v != "" {
+				p.info[UserAgent] = v
+				log.Printf("[%s] UserAgent: %s", p.Get(Address), v)
+			}
+			p.greet(ev.GetId())
+
+		case *wasimoff.Event_ProviderResources:
+			// TODO: set active tasks
+			// The problem is that you can't really "set" a semaphore, so possibly
+			// need to switch to a manual atomic, when providers are allowed to receive
+			// tasks from multiple sources and we can't track it ourselves anymore.
+			if ev.Concurrency != nil {
+				log.Printf("[%s] Workers: %d", p.Get(Address), *ev.Concurrency)
+				p.limiter.SetLimit(int(*ev.Concurrency))
+			}
+
+		case *wasimoff.Event_FileSystemUpdate:
+			// update about stored files on provider;
+			// first add, then remove, i.e. err on _not_ having the file
+			p.addFiles(ev.GetAdded()...)
+			p.removeFiles(ev.GetRemoved()...)
+
+		default:
+			log.Printf("[%s] WARN: unknown event: %s", p.Get(Address), event.ProtoReflect().Descriptor().FullName())
+
+		}
+	}
 }
@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 	"wasimoff/broker/net/transport"
+	"wasimoff/broker/storage"
 	wasimoff "wasimoff/proto/v1"
 
 	"github.com/marusama/semaphore/v2"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 // Provider is a single connection initiated by a computing provider
@@ -30,13 +34,46 @@ type Provider struct {
 	// information about the provider, to be accessed with Get()
 	info map[ProviderInfoKey]string
 
-	// list of files known on this provider
-	files map[string]struct{}
+	// list of files known on this provider and uploads in progress
+	files      map[string]struct{}
+	uploads    map[string]*upload
+	filesMutex sync.RWMutex
+
+	// storage to push missing files from before running tasks; can be `nil`
+	// when the provider shall fetch them itself
+	storage *storage.FileStorage
+
+	// measured upload throughput in bytes per second, zero until measured
+	bandwidth atomic.Int64
+
+	// round-trip time of the last answered heartbeat in nanoseconds
+	rtt atomic.Int64
+
+	// maximum runtime of tasks without a timeout in their QoS parameters
+	timeout time.Duration
+
+	// size of chunks for large uploads, zero uploads files in one message
+	chunkSize int
+
+	// the Name is the subject of a verified client certificate and can't be changed
+	certified bool
+
+	// the authenticated identity, which scopes the persistent id of the Provider
+	owner string
+
+	// closed when the ProviderHello was received or waiting for it timed out
+	hello     chan struct{}
+	helloOnce sync.Once
+
+	// the session continues on another connection after a reconnect; can be `nil`
+	// when the Provider did not present a persistent id
+	session *session
 }
 
 type ProviderInfoKey string
 
 const (
+	ID        ProviderInfoKey = "id"        // the key in the store: certificate, persistent id or address, see greet
 	Name      ProviderInfoKey = "name"      // a unique name for identification
 	Address   ProviderInfoKey = "address"   // remote address of transport conn
 	UserAgent ProviderInfoKey = "useragent" // software and architecture info
@@ -54,9 +91,12 @@ func NewProvider(messenger *transport.Messenger) *Provider {
 		limiter:   semaphore.New(0),
 		info:      make(map[ProviderInfoKey]string),
 		files:     make(map[string]struct{}),
+		uploads:   make(map[string]*upload),
+		hello:     make(chan struct{}),
 	}
 
 	// set known information
+	provider.info[ID] = addressKey + messenger.Addr()
 	provider.info[Name] = messenger.Addr()
 	provider.info[Address] = messenger.Addr()
 	provider.info[UserAgent] = "unknown"
@@ -75,6 +115,12 @@ func (p *Provider) Waiting() bool {
 	return p.waiting
 }
 
+// RTT returns the round-trip time measured by the last answered heartbeat,
+// or zero if none was answered yet.
+func (p *Provider) RTT() time.Duration {
+	return time.Duration(p.rtt.Load())
+}
+
 // -------------------- closure -------------------- >>
 
 // Returns the cause of the closure or nil if Provider isn't closed yet.
@@ -110,6 +156,58 @@ func (p *Provider) CurrentLimit() int {
 	return p.limiter.GetLimit()
 }
 
+// -------------------- timeouts -------------------- >>
+
+// ErrTimeout is the error of tasks which ran longer than their timeout.
+var ErrTimeout = errors.New("task timed out")
+
+// a cancelled task is given up on the Provider within this time
+const cancelTimeout = 5 * time.Second
+
+// withTimeout derives the context for a single run of the task on this Provider,
+// limited by the timeout in the task's QoS parameters, which can only shorten
+// the default timeout of the Broker
+func (p *Provider) withTimeout(task *AsyncTask) (context.Context, context.CancelFunc) {
+	timeout := p.timeout
+	if t := task.Request.GetQos().GetTimeout(); t != nil && t.AsDuration() > 0 {
+		if timeout <= 0 {
+			timeout = t.AsDuration()
+		} else {
+			timeout = min(timeout, t.AsDuration())
+		}
+	}
+	if timeout <= 0 {
+		return context.WithCancel(task.Context)
+	}
+	return context.WithTimeoutCause(task.Context, timeout, ErrTimeout)
+}
+
+// cancel a task on the Provider in the background, so an unresponsive Provider
+// does not hold the slot of the task until it is evicted
+func (p *Provider) cancel(id string, reason error) {
+	ctx, cancel := context.WithTimeout(p.lifetime.Context, cancelTimeout)
+	defer cancel()
+	// don't really care for result or error here, just that it completed somehow
+	_ = p.messenger.RequestSync(ctx, &wasimoff.Task_Cancel{
+		Id:     &id,
+		Reason: proto.String(reason.Error()),
+	}, &wasimoff.Task_Cancel{})
+}
+
+// stampRuntime notes how long a task ran in its result
+func stampRuntime(response *wasimoff.Task_Response, runtime time.Duration) {
+	switch result := response.GetResult().(type) {
+	case *wasimoff.Task_Response_Wasip1:
+		if result.Wasip1 != nil {
+			result.Wasip1.Runtime = durationpb.New(runtime)
+		}
+	case *wasimoff.Task_Response_Pyodide:
+		if result.Pyodide != nil {
+			result.Pyodide.Runtime = durationpb.New(runtime)
+		}
+	}
+}
+
 // -------------------- task channel -------------------- >>
 
 // Accept tasks on an unbuffered channel to submit to the Provider. Channels can
@@ -166,18 +264,34 @@ func (p *Provider) acceptTasks() (err error) {
 				continue
 			}
 
+			// note where this task ran
+			task.Provider = p.Get(Address)
+
 			// run the Request in a goroutine asynchronously
 			// TODO: avoid gofunc by using a second listener on a `chan *PendingCall`
 			go func() {
-				task.Error = p.run(task.Context, task.Request, task.Response)
+				// push missing files first, which doesn't count towards the runtime
+				if err := p.Require(task.Context, task.Request.GetRequiredFiles()...); err != nil {
+					task.Error = err
+					task.Done()
+					p.limiter.Release(1)
+					return
+				}
+				task.Started = time.Now()
+				ctx, cancel := p.withTimeout(task)
+				defer cancel()
+				ran, err := p.runInSession(ctx, task.Request, task.Response)
+				task.Error = err
+				task.Runtime = time.Since(task.Started)
+				task.Provider = ran.Get(Address) // differs when resumed elsewhere
 				// send cancellation event if error is due to context
-				if errors.Is(task.Error, context.Canceled) {
-					// don't really care for result or error here, just that it completed somehow
-					_ = p.messenger.RequestSync(p.lifetime.Context, &wasimoff.Task_Cancel{
-						Id:     task.Request.GetInfo().Id,
-						Reason: proto.String(context.Canceled.Error()),
-					}, &wasimoff.Task_Cancel{})
+				if errors.Is(task.Error, context.Canceled) || errors.Is(task.Error, context.DeadlineExceeded) {
+					go ran.cancel(task.Request.GetInfo().GetId(), context.Cause(ctx))
+					if errors.Is(context.Cause(ctx), ErrTimeout) {
+						task.Error = fmt.Errorf("%w after %s", ErrTimeout, task.Runtime.Round(time.Millisecond))
+					}
 				}
+				stampRuntime(task.Response, task.Runtime)
 				task.Done()
 				p.limiter.Release(1)
 			}()
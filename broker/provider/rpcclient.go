@@ -2,8 +2,12 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"maps"
+	"time"
 	"wasimoff/broker/storage"
 	wasimoff "wasimoff/proto/v1"
 )
@@ -63,12 +67,15 @@ func (p *Provider) ListFiles() (map[string]struct{}, error) {
 	}
 
 	// (re)set known files from received list
-	p.files = make(map[string]struct{})
+	files := make(map[string]struct{})
 	for _, filename := range response.Files {
-		p.files[filename] = struct{}{}
+		files[filename] = struct{}{}
 	}
+	p.filesMutex.Lock()
+	p.files = files
+	p.filesMutex.Unlock()
 
-	return p.files, nil
+	return maps.Clone(files), nil
 }
 
 // ProbeFile sends a content-address name to check if the Provider *has* a file
@@ -84,15 +91,15 @@ func (p *Provider) ProbeFile(addr string) (has bool, err error) {
 	return response.GetOk(), nil
 }
 
-// Upload a file from Storage to this Provider
-func (p *Provider) Upload(file *storage.File) (err error) {
-	ref := file.Ref()
+// Upload a file from Storage to this Provider; large files are read in chunks
+func (p *Provider) Upload(content io.ReadSeeker, info *storage.FileInfo) (err error) {
+	ref := info.Ref
 
 	// when returning without error, add the file to provider's list
 	// (either probe was ok or upload successful)
 	defer func() {
 		if err == nil {
-			p.files[ref] = struct{}{}
+			p.addFiles(ref)
 		}
 	}()
 
@@ -103,24 +110,52 @@ func (p *Provider) Upload(file *storage.File) (err error) {
 		return nil // ok, provider has this file already
 	}
 
-	// otherwise upload it
+	// large files are sent in chunks, if the Provider supports it
+	if p.chunkSize > 0 && info.Size > int64(p.chunkSize) {
+		start := time.Now()
+		if err := p.uploadChunked(content, info); !errors.Is(err, errChunksUnsupported) {
+			if err == nil {
+				p.measureUpload(int(info.Size), time.Since(start))
+			}
+			return err
+		}
+	}
+
+	// otherwise upload it in one message
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("provider.Upload %q failed reading: %w", ref, err)
+	}
+	blob, err := io.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("provider.Upload %q failed reading: %w", ref, err)
+	}
 	args := wasimoff.FileUploadRequest{Upload: &wasimoff.File{
 		Ref:   &ref,
-		Media: &file.Media,
-		Blob:  file.Bytes,
+		Media: &info.Media,
+		Blob:  blob,
 	}}
 	response := wasimoff.FileUploadResponse{}
+	start := time.Now()
 	if err := p.messenger.RequestSync(context.TODO(), &args, &response); err != nil {
 		return fmt.Errorf("provider.Upload %q failed: %w", ref, err)
 	}
+	p.measureUpload(len(blob), time.Since(start))
 	if response.GetErr() != "" {
 		return fmt.Errorf("provider.Upload %q failed at Provider: %s", ref, *response.Err)
 	}
 	return
 }
 
-// Has returns if this Provider *is known* to have a certain file, without re-probing
-func (p *Provider) Has(file string) bool {
-	_, ok := p.files[file]
-	return ok
+// Delete asks the Provider to remove a file, which was deleted from Storage
+func (p *Provider) Delete(ref string) error {
+	args := wasimoff.FileDeleteRequest{File: &ref}
+	response := wasimoff.FileDeleteResponse{}
+	if err := p.messenger.RequestSync(context.TODO(), &args, &response); err != nil {
+		return fmt.Errorf("provider.Delete %q failed: %w", ref, err)
+	}
+	p.removeFiles(ref)
+	if response.GetErr() != "" {
+		return fmt.Errorf("provider.Delete %q failed at Provider: %s", ref, *response.Err)
+	}
+	return nil
 }
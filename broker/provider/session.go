@@ -0,0 +1,175 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"wasimoff/broker/net/transport"
+	wasimoff "wasimoff/proto/v1"
+)
+
+// Providers can present a persistent id in their ProviderHello. Their session then
+// outlives a single connection: when it breaks, the session is kept for a grace
+// period, in which the Provider can reconnect and resume it. Tasks which were
+// running are sent again on the new connection, where the Provider answers with
+// the result of the task it kept running meanwhile. The known files are listed
+// again on every connection but the measured bandwidth is carried over.
+
+// ErrSessionExpired is the cause of failed tasks, whose Provider did not reconnect
+// within the grace period.
+var ErrSessionExpired = errors.New("provider session expired")
+
+// ErrReplaced is the cause of closing a connection, when the same Provider connected
+// again before the old connection was noticed to be broken.
+var ErrReplaced = errors.New("provider reconnected")
+
+// wait this long for the ProviderHello with a persistent id before adding a Provider
+const helloTimeout = 2 * time.Second
+
+// session is the state of a Provider across its connections
+type session struct {
+	mutex   sync.Mutex
+	current *Provider     // the latest connection
+	changed chan struct{} // closed when the connection is replaced or the session expires
+	expired bool
+	timer   *time.Timer // expires the session after a disconnect
+}
+
+// replace the current connection or expire the session and notify waiting tasks
+func (s *session) replace(p *Provider, expired bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.current, s.expired = p, expired
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// resumed waits until another connection resumes the session of the broken
+// connection p and returns it; fails when the session expires first
+func (s *session) resumed(ctx context.Context, p *Provider) (*Provider, error) {
+	for {
+		s.mutex.Lock()
+		current, changed, expired := s.current, s.changed, s.expired
+		s.mutex.Unlock()
+		if expired {
+			return nil, ErrSessionExpired
+		}
+		if current != p && current.Err() == nil {
+			return current, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+}
+
+// runInSession runs a task request on this connection. When the connection breaks
+// meanwhile, the request is sent again to the connection which resumed the session.
+// Returns the connection of the last attempt. Resumed tasks are not counted in the
+// limiter of the new connection; the Provider queues them in its own pool.
+func (p *Provider) runInSession(ctx context.Context, request *wasimoff.Task_Request, response *wasimoff.Task_Response) (*Provider, error) {
+	current := p
+	for {
+		err := current.run(ctx, request, response)
+		var remote transport.RemoteError
+		if err == nil || ctx.Err() != nil || current.session == nil || errors.As(err, &remote) {
+			return current, err // not a broken connection
+		}
+		next, serr := current.session.resumed(ctx, current)
+		if serr != nil {
+			return current, fmt.Errorf("%w: %w", err, serr)
+		}
+		log.Printf("[%s] Resuming task %s", next.Get(Address), request.GetInfo().GetId())
+		current = next
+	}
+}
+
+// Keys in the store are prefixed by their kind, so an announced id can never
+// equal the key of a certificate or another token's Provider.
+const (
+	certKey    = "cert:"  // verified client certificate name
+	tokenKey   = "token:" // token name and announced id
+	idKey      = "id:"    // announced id without authentication
+	addressKey = "addr:"  // remote address, not resumable
+)
+
+// greet sets the persistent id from the first ProviderHello, which is scoped by
+// the authenticated identity, so nobody can take over another's session. Certified
+// Providers are keyed by their certificate only. The id can't change after the
+// Provider was added to the store.
+func (p *Provider) greet(id string) {
+	p.helloOnce.Do(func() {
+		switch {
+		case id == "" || p.certified:
+		case p.owner != "":
+			p.info[ID] = tokenKey + p.owner + "/" + id
+		default:
+			p.info[ID] = idKey + id
+		}
+		close(p.hello)
+	})
+}
+
+// resumable returns if the Provider presented a persistent id or certificate
+func (p *Provider) resumable() bool {
+	return !strings.HasPrefix(p.Get(ID), addressKey)
+}
+
+// attach a new connection to the session of its id, resuming an existing session;
+// must be called with the sessionsMutex held
+func (s *ProviderStore) attach(p *Provider) {
+	key := p.Get(ID)
+	sess, ok := s.sessions[key]
+	if !ok {
+		sess = &session{current: p, changed: make(chan struct{})}
+		s.sessions[key] = sess
+		p.session = sess
+		return
+	}
+	if sess.timer != nil {
+		sess.timer.Stop()
+		sess.timer = nil
+	}
+	previous := sess.current
+	if p.bandwidth.Load() == 0 {
+		p.bandwidth.Store(previous.bandwidth.Load())
+	}
+	p.session = sess
+	sess.replace(p, false)
+	log.Printf("[%s] Provider resumed session of %s", p.Get(Address), previous.Get(Address))
+}
+
+// detach a closed connection from its session, which expires after the grace period
+// unless the Provider reconnects; must be called with the sessionsMutex held
+func (s *ProviderStore) detach(p *Provider) {
+	sess := p.session
+	if sess == nil || sess.current != p {
+		return // not resumable or already replaced
+	}
+	if s.ResumeGrace <= 0 {
+		s.expire(p)
+		return
+	}
+	sess.timer = time.AfterFunc(s.ResumeGrace, func() {
+		s.sessionsMutex.Lock()
+		defer s.sessionsMutex.Unlock()
+		s.expire(p)
+	})
+}
+
+// expire the session of a closed connection, if it was not resumed meanwhile;
+// must be called with the sessionsMutex held
+func (s *ProviderStore) expire(p *Provider) {
+	sess := p.session
+	if sess.current != p {
+		return
+	}
+	delete(s.sessions, p.Get(ID))
+	sess.replace(p, true)
+}
@@ -3,7 +3,10 @@ package provider
 import (
 	"context"
 	"log"
+	"strings"
+	"sync"
 	"time"
+	"wasimoff/broker/history"
 	"wasimoff/broker/metrics"
 	"wasimoff/broker/storage"
 	wasimoff "wasimoff/proto/v1"
@@ -13,16 +16,52 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// Expired files are collected from Storage in this interval.
+const collectInterval = time.Minute
+
 // ProviderStore holds the currently connected providers, safe for concurrent access.
 // It also keeps the list of files known to the provider in memory.
 type ProviderStore struct {
 
-	// Providers are held in a sync.Map safe for concurrent access
+	// Providers are held in a sync.Map safe for concurrent access, keyed by their ID
 	providers *xsync.MapOf[string, *Provider]
 
+	// sessions of Providers with a persistent id, which can be resumed after a
+	// reconnect; the mutex also guards adding and removing Providers
+	sessions      map[string]*session
+	sessionsMutex sync.Mutex
+
+	// ResumeGrace is how long the session of a disconnected Provider is kept, so
+	// it can reconnect and deliver the results of its running tasks; zero fails
+	// them immediately
+	ResumeGrace time.Duration
+
 	// Storage holds the uploaded files in memory
 	Storage *storage.FileStorage
 
+	// History records finished jobs and tasks, if enabled
+	History *history.History
+
+	// TaskTimeout is the maximum runtime of tasks on a Provider, which can only be
+	// shortened in their QoS parameters; zero is unlimited
+	TaskTimeout time.Duration
+
+	// MaxBodySize limits request bodies on the client API, including uploads;
+	// zero is unlimited
+	MaxBodySize int64
+
+	// PushFiles uploads missing files to a Provider before running a task on it,
+	// otherwise the Providers download them on their own
+	PushFiles bool
+
+	// PrewarmFiles is the number of recently used files to push to newly connected
+	// Providers, if pushing is enabled
+	PrewarmFiles int
+
+	// ChunkSize is the size of chunks in bytes, in which larger files are sent to
+	// and served for Providers; zero sends files in a single message
+	ChunkSize int
+
 	// Broadcast is a channel to submit events for all Providers
 	Broadcast chan proto.Message
 
@@ -34,15 +73,21 @@ type ProviderStore struct {
 func NewProviderStore(storagepath string) *ProviderStore {
 	store := ProviderStore{
 		providers:   xsync.NewMapOf[*Provider](),
+		sessions:    make(map[string]*session),
 		Broadcast:   make(chan proto.Message, 10),
 		ratecounter: ratecounter.NewRateCounter(5 * time.Second),
 	}
 	if storagepath == "" || storagepath == ":memory:" {
 		store.Storage = storage.NewMemoryFileStorage()
+	} else if dir, ok := strings.CutPrefix(storagepath, "dir:"); ok {
+		store.Storage = storage.NewDirectoryFileStorage(dir)
+	} else if location, ok := strings.CutPrefix(storagepath, "s3:"); ok {
+		store.Storage = storage.NewS3FileStorage(location)
 	} else {
 		store.Storage = storage.NewBoltFileStorage(storagepath)
 	}
 	go store.transmitter()
+	go store.collector()
 	return &store
 }
 
@@ -64,6 +109,31 @@ func (s *ProviderStore) transmitter() {
 
 }
 
+// ------------- files deleted from storage -------------
+
+// DropFile asks all Providers to remove a file, which was deleted from Storage.
+// The requests are sent in the background and failures are only logged.
+func (s *ProviderStore) DropFile(ref string) {
+	s.Range(func(_ string, p *Provider) bool {
+		go func() {
+			if err := p.Delete(ref); err != nil {
+				log.Printf("WARN: [%s] %s", p.Get(Address), err)
+			}
+		}()
+		return true
+	})
+}
+
+// collector regularly removes expired files from Storage, see Storage.TTL
+func (s *ProviderStore) collector() {
+	for now := range time.Tick(collectInterval) {
+		for _, ref := range s.Storage.Collect(now) {
+			log.Printf("Storage: collected %s", ref)
+			s.DropFile(ref)
+		}
+	}
+}
+
 // -------------- ratecounter in tasks/second --------------
 
 // RateTick should be called on successful Task completion to measure throughput
@@ -94,16 +164,31 @@ func (s *ProviderStore) throughput(tick time.Duration) {
 
 // --------------- stub methods for sync.Map ---------------
 
-// Add a Provider to the Map.
+// Add a Provider to the Map. A previous connection with the same persistent ID
+// is closed and its session is resumed.
 func (s *ProviderStore) Add(provider *Provider) {
-	s.providers.Store(provider.Get(Address), provider)
+	s.sessionsMutex.Lock()
+	if provider.resumable() {
+		s.attach(provider)
+	}
+	previous, replaced := s.providers.LoadAndStore(provider.Get(ID), provider)
+	s.sessionsMutex.Unlock()
+	if replaced {
+		previous.Close(ErrReplaced)
+	}
 	log.Printf("ProviderStore: %d connected", s.Size())
 	s.Broadcast <- &wasimoff.Event_ClusterInfo{Providers: proto.Uint32(uint32(s.Size()))}
 }
 
-// Remove a Provider from the Map.
+// Remove a Provider from the Map, unless it was replaced by a new connection
+// already. Its session can be resumed within the grace period.
 func (s *ProviderStore) Remove(provider *Provider) {
-	s.providers.Delete(provider.Get(Address))
+	s.sessionsMutex.Lock()
+	if current, ok := s.providers.Load(provider.Get(ID)); ok && current == provider {
+		s.providers.Delete(provider.Get(ID))
+	}
+	s.detach(provider)
+	s.sessionsMutex.Unlock()
 	log.Printf("ProviderStore: %d connected", s.Size())
 	s.Broadcast <- &wasimoff.Event_ClusterInfo{Providers: proto.Uint32(uint32(s.Size()))}
 }
@@ -113,9 +198,9 @@ func (s *ProviderStore) Size() int {
 	return s.providers.Size()
 }
 
-// Load a Provider from the Map by its address.
-func (s *ProviderStore) Load(addr string) *Provider {
-	p, ok := s.providers.Load(addr)
+// Load a Provider from the Map by its ID.
+func (s *ProviderStore) Load(id string) *Provider {
+	p, ok := s.providers.Load(id)
 	if !ok {
 		return nil
 	}
@@ -125,15 +210,15 @@ func (s *ProviderStore) Load(addr string) *Provider {
 // Range will iterate over all Providers in the Map and call the given function.
 // If the function returns `false`, the iteration will stop. See xsync.Map.Range()
 // for more usage notes and (lack of) guarantees.
-func (s *ProviderStore) Range(f func(addr string, provider *Provider) bool) {
+func (s *ProviderStore) Range(f func(id string, provider *Provider) bool) {
 	s.providers.Range(f)
 }
 
-// Keys will simply return the current keys (Provider addresses) of the Map.
+// Keys will simply return the current keys (Provider IDs) of the Map.
 func (s *ProviderStore) Keys() []string {
 	keys := make([]string, 0, s.Size())
-	s.Range(func(addr string, _ *Provider) bool {
-		keys = append(keys, addr)
+	s.Range(func(id string, _ *Provider) bool {
+		keys = append(keys, id)
 		return true
 	})
 	return keys
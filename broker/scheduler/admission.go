@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"wasimoff/broker/metrics"
+)
+
+// Submissions are admitted before any of their tasks are queued, so clients get
+// an immediate answer instead of a handler blocking on a full queue. Each
+// requester has a token bucket to limit its rate of tasks and the number of
+// unfinished tasks in total is bounded. Whole jobs are admitted or rejected.
+
+var (
+	ErrRateLimited = errors.New("rate limit exceeded")
+	ErrOverloaded  = errors.New("too many unfinished tasks")
+)
+
+// retry overloaded submissions after this time, when some tasks probably finished
+const overloadRetry = 5 * time.Second
+
+// Admission configures the admission control of submitted tasks.
+type Admission struct {
+	Rate     float64 // tasks per second for each requester; 0 is unlimited
+	Burst    int     // tasks a requester can submit at once after a pause
+	MaxTasks int     // admitted but unfinished tasks in total; 0 is unlimited
+}
+
+// RejectedError is returned for a submission that was not admitted. It can be
+// retried after the given duration.
+type RejectedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", e.Err, e.RetryAfter)
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Err
+}
+
+// tokenBucket holds the tokens of a requester at the time of the last update.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Admitter enforces the Admission limits. A single Admitter must be shared by
+// all handlers, which accept submissions.
+type Admitter struct {
+	config  Admission
+	mutex   sync.Mutex
+	buckets map[string]*tokenBucket
+	tasks   int // admitted but unfinished
+}
+
+// NewAdmitter creates an Admitter with the given limits.
+func NewAdmitter(config Admission) *Admitter {
+	if config.Burst < 1 {
+		config.Burst = max(1, int(math.Ceil(config.Rate)))
+	}
+	return &Admitter{config: config, buckets: make(map[string]*tokenBucket)}
+}
+
+// admit n tasks of a requester or return a *RejectedError. Admitted tasks must
+// be released when they finish.
+func (a *Admitter) admit(requester string, n int) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	// a job larger than the limit is only admitted when nothing else is running
+	if a.config.MaxTasks > 0 && a.tasks > 0 && a.tasks+n > a.config.MaxTasks {
+		metrics.Rejected.WithLabelValues("overloaded").Inc()
+		return &RejectedError{ErrOverloaded, overloadRetry}
+	}
+
+	// take tokens from the bucket; jobs larger than the burst only need a full
+	// bucket and leave a debt, which delays the requester's next submissions
+	if a.config.Rate > 0 {
+		bucket := a.bucket(requesterKey(requester), time.Now())
+		need := float64(min(n, a.config.Burst))
+		if bucket.tokens < need {
+			metrics.Rejected.WithLabelValues("ratelimited").Inc()
+			wait := time.Duration((need - bucket.tokens) / a.config.Rate * float64(time.Second))
+			return &RejectedError{ErrRateLimited, wait.Round(time.Millisecond)}
+		}
+		bucket.tokens -= float64(n)
+	}
+
+	a.tasks += n
+	return nil
+}
+
+// release finished tasks, which were admitted before
+func (a *Admitter) release(n int) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.tasks -= n
+}
+
+// unfinished returns the number of admitted tasks, which did not finish yet
+func (a *Admitter) unfinished() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.tasks
+}
+
+// bucket returns the refilled token bucket of a requester; full buckets of other
+// requesters are forgotten, so they don't accumulate;
+// must be called with the mutex held
+func (a *Admitter) bucket(requester string, now time.Time) *tokenBucket {
+	burst := float64(a.config.Burst)
+	for key, bucket := range a.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*a.config.Rate >= burst && key != requester {
+			delete(a.buckets, key)
+		}
+	}
+	bucket, ok := a.buckets[requester]
+	if !ok {
+		bucket = &tokenBucket{tokens: burst, last: now}
+		a.buckets[requester] = bucket
+	}
+	bucket.tokens = min(burst, bucket.tokens+now.Sub(bucket.last).Seconds()*a.config.Rate)
+	bucket.last = now
+	return bucket
+}
+
+// rejectHTTP answers a rejected submission with 429 and a Retry-After header,
+// or with 400 if it can't be retried
+func rejectHTTP(w http.ResponseWriter, err error) {
+	var rejected *RejectedError
+	if !errors.As(err, &rejected) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	seconds := int(math.Ceil(rejected.RetryAfter.Seconds()))
+	w.Header().Set("retry-after", strconv.Itoa(max(1, seconds)))
+	http.Error(w, err.Error(), http.StatusTooManyRequests)
+}
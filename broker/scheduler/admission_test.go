@@ -0,0 +1,142 @@
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAdmit(t *testing.T) {
+	type submission struct {
+		requester string
+		tasks     int
+		err       error // nil if admitted
+	}
+	for _, tt := range []struct {
+		name        string
+		config      Admission
+		submissions []submission
+	}{
+		{
+			name:   "unlimited",
+			config: Admission{},
+			submissions: []submission{
+				{"alice", 1000, nil}, {"alice", 1000, nil},
+			},
+		},
+		{
+			name:   "burst per requester",
+			config: Admission{Rate: 0.001, Burst: 3},
+			submissions: []submission{
+				{"alice", 2, nil}, {"alice", 1, nil}, {"alice", 1, ErrRateLimited}, {"bob", 3, nil},
+			},
+		},
+		{
+			name:   "addresses share the bucket of their host",
+			config: Admission{Rate: 0.001, Burst: 2},
+			submissions: []submission{
+				{"10.0.0.1:5000", 2, nil}, {"10.0.0.1:5001", 1, ErrRateLimited}, {"10.0.0.2:5000", 1, nil},
+			},
+		},
+		{
+			name:   "jobs larger than the burst need a full bucket and leave a debt",
+			config: Admission{Rate: 0.001, Burst: 2},
+			submissions: []submission{
+				{"alice", 5, nil}, {"alice", 1, ErrRateLimited},
+			},
+		},
+		{
+			name:   "unfinished tasks in total",
+			config: Admission{MaxTasks: 5},
+			submissions: []submission{
+				{"alice", 3, nil}, {"bob", 3, ErrOverloaded}, {"bob", 2, nil}, {"alice", 1, ErrOverloaded},
+			},
+		},
+		{
+			name:   "jobs larger than the limit when nothing else runs",
+			config: Admission{MaxTasks: 5},
+			submissions: []submission{
+				{"alice", 10, nil}, {"bob", 1, ErrOverloaded},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewAdmitter(tt.config)
+			admitted := 0
+			for i, s := range tt.submissions {
+				err := a.admit(s.requester, s.tasks)
+				if !errors.Is(err, s.err) || (err == nil) != (s.err == nil) {
+					t.Fatalf("submission %d of %d tasks by %s: err = %v, expected %v", i, s.tasks, s.requester, err, s.err)
+				}
+				var rejected *RejectedError
+				if err != nil && (!errors.As(err, &rejected) || rejected.RetryAfter <= 0) {
+					t.Errorf("submission %d: no retry time in %v", i, err)
+				}
+				if err == nil {
+					admitted += s.tasks
+				}
+			}
+			if a.unfinished() != admitted {
+				t.Errorf("unfinished = %d, expected %d", a.unfinished(), admitted)
+			}
+		})
+	}
+}
+
+func TestAdmitRelease(t *testing.T) {
+	a := NewAdmitter(Admission{MaxTasks: 2})
+	if err := a.admit("alice", 2); err != nil {
+		t.Fatalf("admit: %s", err)
+	}
+	if err := a.admit("alice", 1); !errors.Is(err, ErrOverloaded) {
+		t.Fatalf("admit beyond the limit: %v", err)
+	}
+	a.release(1)
+	if err := a.admit("alice", 1); err != nil {
+		t.Errorf("admit after release: %s", err)
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	a := NewAdmitter(Admission{Rate: 2})
+	if a.config.Burst != 2 {
+		t.Errorf("default burst = %d, expected the rate rounded up", a.config.Burst)
+	}
+	now := time.Now()
+	bucket := a.bucket("alice", now)
+	bucket.tokens = -1 // debt from a large job
+
+	// refilled at the rate, up to the burst
+	if tokens := a.bucket("alice", now.Add(time.Second)).tokens; tokens != 1 {
+		t.Errorf("tokens after one second = %v, expected 1", tokens)
+	}
+	if tokens := a.bucket("alice", now.Add(time.Hour)).tokens; tokens != 2 {
+		t.Errorf("tokens after one hour = %v, expected 2", tokens)
+	}
+
+	// full buckets of other requesters are forgotten
+	a.bucket("bob", now.Add(time.Hour))
+	if _, ok := a.buckets["alice"]; ok {
+		t.Errorf("full bucket was not forgotten")
+	}
+}
+
+func TestRejectHTTP(t *testing.T) {
+	for _, tt := range []struct {
+		err        error
+		status     int
+		retryAfter string
+	}{
+		{&RejectedError{ErrRateLimited, 1500 * time.Millisecond}, http.StatusTooManyRequests, "2"},
+		{&RejectedError{ErrOverloaded, time.Millisecond}, http.StatusTooManyRequests, "1"},
+		{errors.New("invalid job"), http.StatusBadRequest, ""},
+	} {
+		w := httptest.NewRecorder()
+		rejectHTTP(w, tt.err)
+		if w.Code != tt.status || w.Header().Get("retry-after") != tt.retryAfter {
+			t.Errorf("rejectHTTP(%v): %d, retry-after %q", tt.err, w.Code, w.Header().Get("retry-after"))
+		}
+	}
+}
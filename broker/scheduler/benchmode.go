@@ -20,7 +20,7 @@ func tspbench(store *provider.ProviderStore, parallel int) {
 	log.Printf("BENCHMODE: please upload %q binary", bin)
 	binary := wasimoff.File{Ref: &bin}
 	for {
-		if store.Storage.Get(bin) != nil {
+		if store.Storage.Stat(bin) != nil {
 			// file uploaded
 			log.Printf("BENCHMODE: required binary uploaded, let's go ...")
 			err := store.Storage.ResolvePbFile(&binary) // ! <-- this one is important
@@ -4,13 +4,17 @@ package scheduler
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"mime"
 	"net/http"
+	"sync"
 	"sync/atomic"
+	"time"
+	"wasimoff/broker/auth"
 	"wasimoff/broker/provider"
 	"wasimoff/broker/storage"
 	wasimoff "wasimoff/proto/v1"
@@ -25,27 +29,81 @@ import (
 // Simply use incrementing IDs for jobs
 var jobSequence atomic.Uint64
 
-// An OffloadingJob holds the pb.OffloadWasiArgs from the request along with
+// JobSpec is the job request of any task format, i.e. either a
+// *pb.Client_Job_Wasip1Request or a *pb.Client_Job_PyodideRequest.
+type JobSpec interface {
+	proto.Message
+	GetQos() *wasimoff.Task_QoS
+	TaskRequests() []*wasimoff.Task_Request
+}
+
+// resultMessage is a typed task result or job response, which can carry an error.
+type resultMessage interface {
+	proto.Message
+	GetError() string
+}
+
+// An OffloadingJob holds the JobSpec from the request along with
 // some internal information about the requesting client.
 type OffloadingJob struct {
-	JobID      string // used to track all tasks of this request
-	ClientAddr string // remote address of the requesting client
-	JobSpec    *wasimoff.Client_Job_Wasip1Request
+	JobID      string  // used to track all tasks of this request
+	ClientAddr string  // remote address or identity of the requesting client
+	Tenant     string  // namespace of files and jobs, empty is global
+	JobSpec    JobSpec // the job request of any task format
+
+	// progress of the dispatched tasks, see Dispatch()
+	created   time.Time
+	requests  []*wasimoff.Task_Request // one for each task in JobSpec, with inherited parameters
+	admission *Admitter                // releases the tasks when done, if admitted
+	mutex     sync.Mutex
+	cancel    context.CancelFunc
+	err       error           // the job could not be dispatched at all
+	results   []resultMessage // indexed like requests, nil while pending
+	finished  []int           // task indices in order of completion
+	failed    int             // number of results with an error
+	cancelled bool            // cancelled before all tasks finished
+	changed   chan struct{}   // closed and replaced whenever a task finishes
+	done      chan struct{}   // closed when all tasks have finished
+}
+
+// NewOffloadingJob assigns the next sequential ID to a new job specification.
+func NewOffloadingJob(spec JobSpec, clientAddr, tenant string) *OffloadingJob {
+	return &OffloadingJob{
+		JobID:      fmt.Sprintf("%05d", jobSequence.Add(1)),
+		ClientAddr: clientAddr,
+		Tenant:     tenant,
+		JobSpec:    spec,
+		created:    time.Now(),
+		requests:   spec.TaskRequests(),
+		changed:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// NewJobSpec returns an empty job request for the task format named in the
+// `format` query parameter; the default is wasip1.
+func NewJobSpec(r *http.Request) (JobSpec, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "wasip1":
+		return &wasimoff.Client_Job_Wasip1Request{}, nil
+	case "pyodide":
+		return &wasimoff.Client_Job_PyodideRequest{}, nil
+	default:
+		return nil, fmt.Errorf("unknown task format: %q", format)
+	}
 }
 
 // reuseable task queue for HTTP handler and websocket
 var taskQueue = make(chan *provider.AsyncTask, 100)
 
-// The ExecHandler returns a HTTP handler, which accepts run configurations for
-// existing WASM binaries and dispatches them to available providers. Upon task
-// completion, the results are returned to the HTTP requester.
+// The ExecHandler returns a HTTP handler, which accepts jobs of any task format
+// and dispatches them to available providers. Upon task completion, the results
+// are returned to the HTTP requester in the response type of that format.
 // MARK: ExecHdl
-// TODO: this handler is specific to Wasip1 Jobs .. either handle Task_Request generically or have a route for each
-func ExecHandler(store *provider.ProviderStore, selector Scheduler, benchmode int) http.HandlerFunc {
+func ExecHandler(store *provider.ProviderStore, selector Scheduler, fairshare FairShare, admission *Admitter, benchmode int) http.HandlerFunc {
 
 	// create a queue for the tasks and start the dispatcher
-	// TODO: reuse the ticketing from benchmode to limit concurrent scheduler jobs
-	go Dispatcher(selector, taskQueue)
+	go Dispatcher(selector, taskQueue, fairshare)
 
 	// TODO: remove me
 	// go pytest(4)
@@ -78,34 +136,41 @@ func ExecHandler(store *provider.ProviderStore, selector Scheduler, benchmode in
 			return
 		}
 
-		// read the entire body
+		// read the entire body, up to the maximum size
+		limitBody(w, r, store)
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, "reading body failed", http.StatusUnprocessableEntity)
-			err = fmt.Errorf("reading body failed: %w", err)
+			err = bodyError(w, err)
 			return
 		}
 
-		// read the job specification from the request body
-		job := OffloadingJob{JobSpec: &wasimoff.Client_Job_Wasip1Request{}}
-		err = UnmarshalJobArgs(body, mt, job.JobSpec)
+		// read the job specification of the requested format from the request body
+		spec, err := NewJobSpec(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			err = nil // don't log this
+			return
+		}
+		err = UnmarshalJobArgs(body, mt, spec)
 		if err != nil {
+			w.Header().Set("content-type", mt)
 			w.WriteHeader(http.StatusBadRequest)
-			MarshalJobResponse(w, mt, &wasimoff.Client_Job_Wasip1Response{
-				Error: proto.String(err.Error()),
-			})
+			MarshalJobResponse(w, mt, jobResponse(spec, err, nil))
 			err = nil // don't log this
 			return
 		}
 
 		// amend the job with information about client
-		job.JobID = fmt.Sprintf("%05d", jobSequence.Add(1))
-		job.ClientAddr = r.RemoteAddr
+		job := NewOffloadingJob(spec, auth.Requester(r), auth.Tenant(r))
+		if err := job.admit(admission); err != nil {
+			rejectHTTP(w, err)
+			return
+		}
 		log.Printf("OffloadingJob [%s] from %q: %d tasks\n",
-			job.JobID, job.ClientAddr, len(job.JobSpec.Tasks))
+			job.JobID, job.ClientAddr, len(job.requests))
 
 		// compute all the tasks of a request
-		results := DispatchTasks(r.Context(), store, &job, taskQueue)
+		results := DispatchTasks(r.Context(), store, job, taskQueue)
 
 		// send the result back, if not canceled
 		if cerr := r.Context().Err(); cerr != nil {
@@ -114,6 +179,7 @@ func ExecHandler(store *provider.ProviderStore, selector Scheduler, benchmode in
 		} else {
 			if results.GetError() != "" {
 				// set an error code, if there's an error; we don't want a "200 Failed Successfully"
+				w.Header().Set("content-type", mt)
 				w.WriteHeader(http.StatusFailedDependency)
 			}
 			err = MarshalJobResponse(w, mt, results)
@@ -123,108 +189,45 @@ func ExecHandler(store *provider.ProviderStore, selector Scheduler, benchmode in
 
 // DispatchTasks takes a run configuration, generates individual tasks from it,
 // schedules them in the queue and eventually returns with the results of all
-// those tasks.
-// TODO: accept a Context, so pending tasks can be cancelled from ExecHandler
+// those tasks in the response type of the job's format. Pending tasks are
+// cancelled with the context.
 // MARK: Dispat.
 func DispatchTasks(
 	ctx context.Context,
 	store *provider.ProviderStore,
 	job *OffloadingJob,
 	queue chan *provider.AsyncTask,
-) *wasimoff.Client_Job_Wasip1Response {
+) resultMessage {
 
-	// go through all the *pb.Files in parent and tasks to resolve names from storage
-	errs := []error{}
-	if job.JobSpec.Parent != nil {
-		errs = append(errs, store.Storage.ResolvePbFile(job.JobSpec.Parent.Binary))
-		errs = append(errs, store.Storage.ResolvePbFile(job.JobSpec.Parent.Rootfs))
-	}
-	for _, task := range job.JobSpec.Tasks {
-		errs = append(errs, store.Storage.ResolvePbFile(task.Binary))
-		errs = append(errs, store.Storage.ResolvePbFile(task.Rootfs))
-	}
-	if err := errors.Join(errs...); err != nil {
-		return &wasimoff.Client_Job_Wasip1Response{
-			Error: proto.String(err.Error()),
-		}
-	}
-
-	// create slice for queued tasks and a sufficiently large channel for done signals
-	pending := make([]*provider.AsyncTask, len(job.JobSpec.Tasks))
-	doneChan := make(chan *provider.AsyncTask, len(pending)+10)
-
-	// dispatch each task from slice
-	for i, spec := range job.JobSpec.Tasks {
-
-		// create the request+response for remote procedure call
-		response := wasimoff.Task_Response{}
-		request := wasimoff.Task_Request{
-			// common task metadata with index counter
-			Info: &wasimoff.Task_Metadata{
-				Id:        proto.String(fmt.Sprintf("%s/%d", job.JobID, i)),
-				Requester: &job.ClientAddr,
-			},
-			// inherit empty parameters from the parent job
-			Parameters: &wasimoff.Task_Request_Wasip1{
-				Wasip1: spec.InheritNil(job.JobSpec.Parent),
-			},
-		}
-
-		// create the async task with the common done channel and queue it for dispatch
-		task := provider.NewAsyncTask(ctx, &request, &response, doneChan)
-		pending[i] = task
-		queue <- task
+	if err := job.Dispatch(ctx, store, queue); err != nil {
+		return jobResponse(job.JobSpec, err, nil)
 	}
 
 	// wait for all tasks to finish
-	done := 0
-	for t := range doneChan {
-		done++
-		if t.Error == nil {
-			// store.RateTick()
-		}
-		if done == len(pending) {
-			break
-		}
-	}
+	<-job.Done()
+	return job.Response()
+}
 
-	// collect the task responses
-	jobResponse := &wasimoff.Client_Job_Wasip1Response{
-		Tasks: make([]*wasimoff.Task_Wasip1_Result, len(pending)),
-	}
-	for i, task := range pending {
-		r := &wasimoff.Task_Wasip1_Result{}
-		// internal scheduling error
-		if task.Error != nil {
-			r.Result = &wasimoff.Task_Wasip1_Result_Error{
-				Error: task.Error.Error(),
-			}
-		}
-		// need to repack result type
-		switch result := task.Response.Result.(type) {
-		case *wasimoff.Task_Response_Error:
-			// error during task execution
-			r.Result = &wasimoff.Task_Wasip1_Result_Error{
-				Error: result.Error,
-			}
-		case *wasimoff.Task_Response_Wasip1:
-			// normal expected result
-			r.Result = result.Wasip1.Result
-		default:
-			// unexpected result type
-			log.Printf("DEBUG: unexpected result type: %s", protojson.Format(task.Response))
-			r.Result = &wasimoff.Task_Wasip1_Result_Error{
-				Error: "unexpected result type",
-			}
-		}
-		jobResponse.Tasks[i] = r
+// MARK: Marshal
+// limitBody caps the request body at the maximum size configured in the store
+func limitBody(w http.ResponseWriter, r *http.Request, store *provider.ProviderStore) {
+	if store.MaxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, store.MaxBodySize)
 	}
+}
 
-	return jobResponse
+// bodyError answers a failure to read the request body, which may be too large
+func bodyError(w http.ResponseWriter, err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+	} else {
+		http.Error(w, "reading body failed", http.StatusUnprocessableEntity)
+	}
+	return fmt.Errorf("reading body failed: %w", err)
 }
 
-// MARK: Marshal
-func UnmarshalJobArgs(body []byte, mt string, spec *wasimoff.Client_Job_Wasip1Request) (err error) {
+func UnmarshalJobArgs(body []byte, mt string, spec JobSpec) (err error) {
 
 	// try to decode the body to the expected job spec
 	switch mt {
@@ -240,13 +243,13 @@ func UnmarshalJobArgs(body []byte, mt string, spec *wasimoff.Client_Job_Wasip1Re
 	}
 
 	// check the basic job specification requirements
-	if len(spec.Tasks) == 0 {
+	if len(spec.TaskRequests()) == 0 {
 		err = errors.Join(err, fmt.Errorf("JobSpec: no tasks specified"))
 	}
 	return err
 }
 
-func MarshalJobResponse(w http.ResponseWriter, mt string, result *wasimoff.Client_Job_Wasip1Response) (err error) {
+func MarshalJobResponse(w http.ResponseWriter, mt string, result proto.Message) (err error) {
 
 	// marshal the response to desired format
 	var body []byte
@@ -298,29 +301,99 @@ func UploadHandler(store *provider.ProviderStore) http.HandlerFunc {
 			return
 		}
 
-		// read the entire body
-		body, err := io.ReadAll(r.Body)
-		if err != nil {
-			http.Error(w, "reading body failed", http.StatusUnprocessableEntity)
-			err = fmt.Errorf("reading body failed: %w", err)
-			return
-		}
-
 		// can have a friendly lookup-name as query parameter
 		name := r.URL.Query().Get("name")
 
-		// insert file in storage
-		file, err := store.Storage.Insert(name, ft, body)
-		if err != nil {
+		// stream the body into storage, within the quota of the uploader
+		limitBody(w, r, store)
+		file, evicted, err := store.Storage.Namespace(auth.Tenant(r)).Put(name, ft, requesterKey(auth.Requester(r)), r.Body)
+		var tooLarge *http.MaxBytesError
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			http.Error(w, err.Error(), http.StatusInsufficientStorage)
+			return
+		} else if errors.As(err, &tooLarge) {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		} else if err != nil {
 			http.Error(w, "inserting file in storage failed", http.StatusInternalServerError)
 			err = fmt.Errorf("inserting in storage failed: %w", err)
 			return
 		}
+		for _, ref := range evicted {
+			log.Printf("Storage: evicted %s", ref)
+			store.DropFile(ref)
+		}
 
 		// return the content address to client
 		w.WriteHeader(http.StatusOK)
 		w.Header().Add("content-type", "text/plain")
-		fmt.Fprintln(w, file.Ref())
+		fmt.Fprintln(w, file.Ref)
+
+	}
+}
+
+//
+// ----------> managing files
+
+// The DownloadHandler returns a HTTP handler, which serves a file by name or
+// content address from the requester's namespace.
+// MARK: Download
+func DownloadHandler(store *provider.ProviderStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		store.Storage.Namespace(auth.Tenant(r)).ServeHTTP(w, r)
+	}
+}
+
+// The FilesHandler returns a HTTP handler, which lists all files in storage
+// with their names, media types, sizes and upload times as JSON.
+// MARK: Files
+func FilesHandler(store *provider.ProviderStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if err := json.NewEncoder(w).Encode(store.Storage.Namespace(auth.Tenant(r)).List()); err != nil {
+			log.Printf("ERR: Files [%s]: %s", r.RemoteAddr, err)
+		}
+	}
+}
+
+// The AliasHandler returns a HTTP handler, which adds another lookup-name to
+// an existing file, given by name or content address. Returns the address.
+// MARK: Alias
+func AliasHandler(store *provider.ProviderStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		ref, err := store.Storage.Namespace(auth.Tenant(r)).Alias(query.Get("name"), query.Get("file"))
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("content-type", "text/plain")
+		fmt.Fprintln(w, ref)
+	}
+}
 
+// The DeleteHandler returns a HTTP handler, which removes a lookup-name or, when
+// given a content address, the file itself along with all of its names. Providers
+// are asked to drop removed files as well.
+// MARK: Delete
+func DeleteHandler(store *provider.ProviderStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		removed, err := store.Storage.Namespace(auth.Tenant(r)).Delete(r.PathValue("filename"))
+		if errors.Is(err, storage.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			log.Printf("ERR: Delete [%s]: %s", r.RemoteAddr, err)
+			http.Error(w, "deleting from storage failed", http.StatusInternalServerError)
+			return
+		}
+		if removed != "" {
+			log.Printf("Storage: deleted %s", removed)
+			store.DropFile(removed)
+		}
+		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"sync/atomic"
+	"wasimoff/broker/auth"
 	"wasimoff/broker/net/transport"
 	"wasimoff/broker/provider"
 	wasimoff "wasimoff/proto/v1"
@@ -16,10 +18,12 @@ import (
 // ClientSocketHandler returns a http.HandlerFunc to be used on a route that shall serve
 // as an endpoint for Clients to connect to. This particular handler uses WebSocket
 // transport with either Protobuf or JSON encoding, negotiated using subprotocol strings.
-func ClientSocketHandler(store *provider.ProviderStore) http.HandlerFunc {
+func ClientSocketHandler(store *provider.ProviderStore, admission *Admitter) http.HandlerFunc {
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		addr := transport.ProxiedAddr(r)
+		requester, tenant := auth.Requester(r), auth.Tenant(r)
+		files := store.Storage.Namespace(tenant)
 
 		// upgrade the transport
 		// using wildcard in Allowed-Origins because Client can be anywhere
@@ -33,11 +37,90 @@ func ClientSocketHandler(store *provider.ProviderStore) http.HandlerFunc {
 
 		// all tasks on this socket are counted as one "job"
 		job := fmt.Sprintf("websocket/%05d", jobSequence.Add(1))
-		requestSequence := uint64(0)
+		requestSequence := atomic.Uint64{}
 
-		// channel for finished requests
-		// TODO: limit task creation with an equally-sized ticket channel
-		done := make(chan *provider.AsyncTask, 32)
+		// dispatch received task requests, limiting the number of tasks in-flight
+		transport.HandleFunc(messenger, 32, func(ctx context.Context, taskrequest *wasimoff.Task_Request) (*wasimoff.Task_Response, error) {
+
+			// admit the task before resolving anything
+			if err := checkQoS(taskrequest.GetQos()); err != nil {
+				return nil, err
+			}
+			if err := admission.admit(requester, 1); err != nil {
+				return nil, err
+			}
+			defer admission.release(1)
+
+			// resolve any filenames to storage hashes
+			if err := files.ResolveTaskFiles(taskrequest); err != nil {
+				return nil, err
+			}
+			defer store.Storage.Pin(taskrequest)()
+
+			// assemble the task for internal dispatcher queue
+			taskrequest.Info = &wasimoff.Task_Metadata{
+				Id:        proto.String(fmt.Sprintf("%s/%d", job, requestSequence.Add(1))),
+				Requester: &requester,
+			}
+			done := make(chan *provider.AsyncTask, 1)
+			task := provider.NewAsyncTask(ctx, taskrequest, &wasimoff.Task_Response{}, done)
+			select {
+			case taskQueue <- task:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			// pass through both internal and response errors directly
+			<-done
+			store.History.RecordTask(taskRecord(job, task))
+			return task.Response, task.Error
+
+		})
+
+		// run whole jobs of any format, streaming each result as an event as soon as
+		// it finishes; the response is sent when the job is complete and summarizes it
+		runJob := func(ctx context.Context, spec JobSpec) (*wasimoff.Client_Job_Status, error) {
+			job := NewOffloadingJob(spec, requester, tenant)
+			if len(job.requests) == 0 {
+				return nil, fmt.Errorf("JobSpec: no tasks specified")
+			}
+			if err := job.admit(admission); err != nil {
+				return nil, err
+			}
+
+			// pending tasks are cancelled when the socket closes
+			if err := job.Dispatch(ctx, store, taskQueue); err != nil {
+				return job.Status(), nil
+			}
+			log.Printf("OffloadingJob [%s] from %q: %d tasks, websocket\n",
+				job.JobID, job.ClientAddr, len(job.requests))
+
+			for sent := 0; ; {
+				results, changed, complete := job.resultsSince(sent)
+				for _, result := range results {
+					if err := messenger.SendEvent(ctx, result); err != nil {
+						job.Cancel()
+						return nil, err
+					}
+				}
+				sent += len(results)
+				if complete {
+					return job.Status(), nil
+				}
+				select {
+				case <-changed:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+		}
+		transport.HandleFunc(messenger, 8, func(ctx context.Context, spec *wasimoff.Client_Job_Wasip1Request) (*wasimoff.Client_Job_Status, error) {
+			return runJob(ctx, spec)
+		})
+		transport.HandleFunc(messenger, 8, func(ctx context.Context, spec *wasimoff.Client_Job_PyodideRequest) (*wasimoff.Client_Job_Status, error) {
+			return runJob(ctx, spec)
+		})
+		go messenger.ServeRequests()
 
 		defer log.Printf("[%s] Client socket closed", addr)
 		for {
@@ -56,55 +139,8 @@ func ClientSocketHandler(store *provider.ProviderStore) http.HandlerFunc {
 				}
 				log.Printf("{client %s} %s", addr, prototext.Format(event))
 
-			// dispatch received requests
-			case request, ok := <-messenger.Requests():
-				if !ok { // messenger closing
-					return
-				}
-				switch taskrequest := request.Request.(type) {
-
-				case *wasimoff.Task_Request:
-					requestSequence++
-
-					// resolve any filenames to storage hashes
-					if ferr := store.Storage.ResolveTaskFiles(taskrequest); ferr != nil {
-						request.Respond(r.Context(), nil, ferr)
-						continue // handle next request
-					}
-
-					// assemble the task for internal dispatcher queue
-					taskrequest.Info = &wasimoff.Task_Metadata{
-						Id:        proto.String(fmt.Sprintf("%s/%d", job, requestSequence)),
-						Requester: &addr,
-					}
-					response := wasimoff.Task_Response{}
-					taskctx := context.WithValue(r.Context(), ctxkeyRequest{}, request)
-					taskQueue <- provider.NewAsyncTask(taskctx, taskrequest, &response, done)
-					// log.Printf("Task submit: %s :: %#v\n", wreq.Info.TaskID(), wreq.Task.Args)
-					continue
-
-				default: // unexpected message type
-					request.Respond(r.Context(), nil, fmt.Errorf("expecting only Task_Request messages on this socket"))
-					continue
-
-				}
-
-			// respond with finished results
-			case task := <-done:
-				request, ok := task.Context.Value(ctxkeyRequest{}).(transport.IncomingRequest)
-				if !ok {
-					log.Fatalf("ClientSocketHandler: couldn't get incoming request from context")
-				}
-
-				// pass through both internal and response errors directly
-				request.Respond(r.Context(), task.Response, task.Error)
-				// log.Printf("Task respond: %s :: %#v\n", task.Args.Info.TaskID(), task.Args.Task.Args)
-
 			}
 		}
 
 	}
 }
-
-// typed key to store original request in a context
-type ctxkeyRequest struct{}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"math"
+	"net"
+)
+
+// FairShare configures the weighted fair queueing between requesters in the
+// Dispatcher. Requesters are identified by their token name or, without
+// authentication, by their host without a port.
+type FairShare struct {
+	Weights     map[string]int // relative share of provider slots; default is 1
+	Inflight    map[string]int // caps on dispatched but unfinished tasks
+	MaxInflight int            // cap for requesters not in Inflight; 0 is unlimited
+}
+
+// requesterKey identifies the share of a requester. Remote addresses are reduced
+// to their host, so multiple connections from one client share the same slots.
+func requesterKey(requester string) string {
+	if host, _, err := net.SplitHostPort(requester); err == nil {
+		return host
+	}
+	return requester
+}
+
+// requesterShare is the state of an active requester, with tasks in the queue
+// or in-flight. The virtual time is the received service divided by weight.
+type requesterShare struct {
+	queued   int
+	inflight int
+	virtual  float64
+}
+
+// fairShares implements start-time fair queueing over the active requesters.
+type fairShares struct {
+	config FairShare
+	active map[string]*requesterShare
+}
+
+func newFairShares(config FairShare) *fairShares {
+	return &fairShares{config: config, active: make(map[string]*requesterShare)}
+}
+
+// get the share of a requester, activating it if necessary
+func (f *fairShares) get(requester string) *requesterShare {
+	share, ok := f.active[requester]
+	if !ok {
+		// start at the smallest virtual time of all active requesters,
+		// so newcomers can't claim the service they missed while idle
+		share = &requesterShare{}
+		if len(f.active) > 0 {
+			share.virtual = math.Inf(1)
+			for _, other := range f.active {
+				share.virtual = min(share.virtual, other.virtual)
+			}
+		}
+		f.active[requester] = share
+	}
+	return share
+}
+
+// forget requesters without any queued or in-flight tasks
+func (f *fairShares) release(requester string, share *requesterShare) {
+	if share.queued == 0 && share.inflight == 0 {
+		delete(f.active, requester)
+	}
+}
+
+// queued adjusts the number of waiting tasks of a requester
+func (f *fairShares) queued(requester string, n int) {
+	share := f.get(requester)
+	share.queued += n
+	f.release(requester, share)
+}
+
+// dispatched moves a task from waiting to in-flight and charges the requester
+func (f *fairShares) dispatched(requester string) {
+	share := f.get(requester)
+	share.queued--
+	share.inflight++
+	share.virtual += 1 / float64(f.weight(requester))
+}
+
+// finished releases an in-flight slot of the requester
+func (f *fairShares) finished(requester string) {
+	share := f.get(requester)
+	share.inflight--
+	f.release(requester, share)
+}
+
+// next selects the requester with the smallest virtual time among those with
+// tasks in the given class, which did not reach their in-flight cap yet
+func (f *fairShares) next(class map[string]*taskHeap) (requester string, ok bool) {
+	best := math.Inf(1)
+	for candidate := range class {
+		share := f.active[candidate]
+		if limit := f.limit(candidate); limit > 0 && share.inflight >= limit {
+			continue
+		}
+		// break ties by name, so the selection is deterministic
+		if share.virtual < best || share.virtual == best && candidate < requester {
+			requester, best, ok = candidate, share.virtual, true
+		}
+	}
+	return requester, ok
+}
+
+// weight of a requester, at least 1
+func (f *fairShares) weight(requester string) int {
+	if w, ok := f.config.Weights[requester]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// limit of in-flight tasks of a requester, zero is unlimited
+func (f *fairShares) limit(requester string) int {
+	if n, ok := f.config.Inflight[requester]; ok {
+		return n
+	}
+	return f.config.MaxInflight
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestRequesterKey(t *testing.T) {
+	for requester, expected := range map[string]string{
+		"alice":            "alice",
+		"10.0.0.1:5123":    "10.0.0.1",
+		"[2001:db8::1]:80": "2001:db8::1",
+		"10.0.0.1":         "10.0.0.1",
+	} {
+		if key := requesterKey(requester); key != expected {
+			t.Errorf("requesterKey(%q) = %q, expected %q", requester, key, expected)
+		}
+	}
+}
+
+// queueEach pushes n tasks of normal priority for each requester
+func queueEach(q *priorityQueue, n int, requesters ...string) {
+	tasks := []testTask{}
+	for i := range n {
+		for _, requester := range requesters {
+			tasks = append(tasks, testTask{id: fmt.Sprintf("%s-%d", requester, i), requester: requester})
+		}
+	}
+	pushAll(q, tasks)
+}
+
+func TestFairShareWeights(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		config   FairShare
+		pops     int
+		expected map[string]int
+	}{
+		{
+			name:     "equal shares",
+			config:   FairShare{},
+			pops:     6,
+			expected: map[string]int{"alice": 3, "bob": 3},
+		},
+		{
+			name:     "weighted shares",
+			config:   FairShare{Weights: map[string]int{"alice": 2}},
+			pops:     6,
+			expected: map[string]int{"alice": 4, "bob": 2},
+		},
+		{
+			name:     "invalid weights count as one",
+			config:   FairShare{Weights: map[string]int{"alice": 0, "bob": -3}},
+			pops:     4,
+			expected: map[string]int{"alice": 2, "bob": 2},
+		},
+		{
+			name:     "in-flight caps",
+			config:   FairShare{Inflight: map[string]int{"alice": 1}, MaxInflight: 3},
+			pops:     10,
+			expected: map[string]int{"alice": 1, "bob": 3},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newPriorityQueue(tt.config)
+			queueEach(q, 10, "alice", "bob")
+			dispatched := make(map[string]int)
+			for range tt.pops {
+				if !q.ready() {
+					break
+				}
+				dispatched[q.pop().requester]++
+			}
+			if !maps.Equal(dispatched, tt.expected) {
+				t.Errorf("dispatched %v, expected %v", dispatched, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFairShareFinished(t *testing.T) {
+	q := newPriorityQueue(FairShare{MaxInflight: 1})
+	queueEach(q, 2, "alice", "bob")
+
+	// both requesters are at their cap after one task each
+	first, second := q.pop(), q.pop()
+	if q.ready() {
+		t.Fatalf("a requester exceeded its in-flight cap")
+	}
+
+	// a finished task frees the slot of its requester only
+	q.finished(first)
+	if next := q.pop(); next == nil || next.requester != first.requester {
+		t.Fatalf("the freed slot was not used by %s", first.requester)
+	}
+	q.finished(second)
+	if ids := popAll(q); !slices.Equal(ids, []string{second.requester + "-1"}) {
+		t.Errorf("popped %v after the second task finished", ids)
+	}
+}
+
+func TestFairShareNewcomer(t *testing.T) {
+	q := newPriorityQueue(FairShare{})
+	queueEach(q, 10, "alice")
+	for range 5 {
+		q.pop()
+	}
+
+	// a newcomer starts at the current virtual time instead of claiming the
+	// service it missed, so both alternate from now on
+	queueEach(q, 2, "bob")
+	dispatched := make(map[string]int)
+	for range 4 {
+		dispatched[q.pop().requester]++
+	}
+	if expected := map[string]int{"alice": 2, "bob": 2}; !maps.Equal(dispatched, expected) {
+		t.Errorf("dispatched %v, expected %v", dispatched, expected)
+	}
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"time"
+	"wasimoff/broker/history"
+	"wasimoff/broker/provider"
+	wasimoff "wasimoff/proto/v1"
+)
+
+// jobRecord summarizes a finished job for the history
+func (job *OffloadingJob) jobRecord() *history.JobRecord {
+	status := job.Status()
+	return &history.JobRecord{
+		ID:        job.JobID,
+		Requester: job.ClientAddr,
+		Tasks:     int(status.GetTasks()),
+		Completed: int(status.GetCompleted()),
+		Failed:    int(status.GetFailed()),
+		Cancelled: status.GetCancelled(),
+		Error:     status.GetError(),
+		Submitted: job.created,
+		Finished:  time.Now(),
+	}
+}
+
+// taskRecord describes a finished task for the history, without any outputs
+func taskRecord(job string, task *provider.AsyncTask) *history.TaskRecord {
+	record := &history.TaskRecord{
+		ID:        task.Request.GetInfo().GetId(),
+		Job:       job,
+		Requester: task.Request.GetInfo().GetRequester(),
+		Provider:  task.Provider,
+		Created:   task.Created,
+		Finished:  time.Now(),
+	}
+	if !task.Started.IsZero() {
+		record.Started = &task.Started
+	}
+
+	switch p := task.Request.Parameters.(type) {
+	case *wasimoff.Task_Request_Wasip1:
+		record.Type = "wasip1"
+		record.Binary = p.Wasip1.GetBinary().GetRef()
+	case *wasimoff.Task_Request_Pyodide:
+		record.Type = "pyodide"
+	}
+
+	// internal scheduling error
+	if task.Error != nil {
+		record.Error = task.Error.Error()
+		return record
+	}
+
+	switch result := task.Response.Result.(type) {
+	case *wasimoff.Task_Response_Error:
+		record.Error = result.Error
+	case *wasimoff.Task_Response_Wasip1:
+		if output := result.Wasip1.GetOk(); output != nil {
+			record.Status = output.Status
+			record.Stdout = len(output.GetStdout())
+			record.Stderr = len(output.GetStderr())
+			record.Artifacts = len(output.GetArtifacts().GetBlob())
+		} else {
+			record.Error = result.Wasip1.GetError()
+		}
+	case *wasimoff.Task_Response_Pyodide:
+		if output := result.Pyodide.GetOk(); output != nil {
+			record.Stdout = len(output.GetStdout())
+			record.Stderr = len(output.GetStderr())
+			record.Pickle = len(output.GetPickle())
+			record.Artifacts = len(output.GetArtifacts().GetBlob())
+		} else {
+			record.Error = result.Pyodide.GetError()
+		}
+	}
+	return record
+}
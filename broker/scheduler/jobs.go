@@ -0,0 +1,580 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"wasimoff/broker/auth"
+	"wasimoff/broker/history"
+	"wasimoff/broker/provider"
+	wasimoff "wasimoff/proto/v1"
+
+	"github.com/puzpuzpuz/xsync"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+//
+// ----------> task progress
+
+// Dispatch resolves all files of the job, generates the individual tasks and
+// queues them in the background. It returns immediately; use Done() to wait for
+// all tasks to finish. Pending tasks are cancelled with the context or Cancel().
+func (job *OffloadingJob) Dispatch(ctx context.Context, store *provider.ProviderStore, queue chan *provider.AsyncTask) error {
+
+	// go through all the *pb.Files in parent and tasks to resolve names from storage
+	if err := job.resolveFiles(store); err != nil {
+		if job.admission != nil {
+			job.admission.release(len(job.requests))
+		}
+		job.mutex.Lock()
+		job.err = err
+		job.mutex.Unlock()
+		close(job.done)
+		store.History.RecordJob(job.jobRecord())
+		return err
+	}
+
+	// keep the files in storage while the job is in flight
+	unpin := store.Storage.Pin(job.requests...)
+
+	// the context can be cancelled by the client later
+	ctx, job.cancel = context.WithCancel(ctx)
+
+	// create slice for queued tasks and a sufficiently large channel for done signals
+	job.results = make([]resultMessage, len(job.requests))
+	pending := make([]*provider.AsyncTask, len(job.requests))
+	doneChan := make(chan *provider.AsyncTask, len(pending)+10)
+
+	for i, request := range job.requests {
+
+		// common task metadata with index counter
+		request.Info = &wasimoff.Task_Metadata{
+			Id:        proto.String(fmt.Sprintf("%s/%d", job.JobID, i)),
+			Requester: &job.ClientAddr,
+		}
+		// QoS parameters are common to all tasks of a job
+		request.Qos = job.JobSpec.GetQos()
+
+		// create the async task with the common done channel
+		pending[i] = provider.NewAsyncTask(ctx, request, &wasimoff.Task_Response{}, doneChan)
+	}
+
+	// queue all tasks for dispatch, unless the job is cancelled before
+	go func() {
+		for _, task := range pending {
+			select {
+			case queue <- task:
+			case <-ctx.Done():
+				task.Error = ctx.Err()
+				task.Done()
+			}
+		}
+	}()
+
+	go func() {
+		job.collect(pending, doneChan, store.History)
+		unpin()
+	}()
+	return nil
+}
+
+// admit all tasks of the job before dispatching it or return a *RejectedError;
+// invalid QoS parameters are rejected with another error
+func (job *OffloadingJob) admit(admission *Admitter) error {
+	if err := checkQoS(job.JobSpec.GetQos()); err != nil {
+		return err
+	}
+	if err := admission.admit(job.ClientAddr, len(job.requests)); err != nil {
+		return err
+	}
+	job.admission = admission
+	return nil
+}
+
+// resolve names of all the *pb.Files in the tasks from storage; files inherited
+// from the parent are shared, so only the first error is reported
+func (job *OffloadingJob) resolveFiles(store *provider.ProviderStore) error {
+	for i, request := range job.requests {
+		if err := store.Storage.Namespace(job.Tenant).ResolveTaskFiles(request); err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// collect the results of all pending tasks as they finish and record them
+func (job *OffloadingJob) collect(pending []*provider.AsyncTask, doneChan chan *provider.AsyncTask, h *history.History) {
+
+	index := make(map[*provider.AsyncTask]int, len(pending))
+	for i, task := range pending {
+		index[task] = i
+	}
+
+	for range pending {
+		task := <-doneChan
+		if job.admission != nil {
+			job.admission.release(1)
+		}
+		h.RecordTask(taskRecord(job.JobID, task))
+		result := job.result(task)
+		job.mutex.Lock()
+		job.results[index[task]] = result
+		job.finished = append(job.finished, index[task])
+		if result.GetError() != "" {
+			job.failed++
+		}
+		close(job.changed)
+		job.changed = make(chan struct{})
+		job.mutex.Unlock()
+	}
+
+	// release the context and notify any waiters
+	job.cancel()
+	close(job.done)
+	h.RecordJob(job.jobRecord())
+}
+
+// result repacks the response of a finished task in the result type of the job's format
+func (job *OffloadingJob) result(task *provider.AsyncTask) resultMessage {
+	switch job.JobSpec.(type) {
+	case *wasimoff.Client_Job_PyodideRequest:
+		return pyodideResult(task)
+	default:
+		return wasip1Result(task)
+	}
+}
+
+// repackResult handles what is common to the result types of all formats: an
+// internal scheduling error, an error during task execution or an unexpected
+// result type are reported with failed; expected repacks the result of the
+// format and returns false for any other type
+func repackResult[R resultMessage](
+	task *provider.AsyncTask,
+	failed func(message string, runtime *durationpb.Duration) R,
+	expected func(response *wasimoff.Task_Response) (R, bool),
+) R {
+
+	// internal scheduling error, e.g. a timeout
+	if task.Error != nil {
+		var runtime *durationpb.Duration
+		if !task.Started.IsZero() {
+			runtime = durationpb.New(task.Runtime)
+		}
+		return failed(task.Error.Error(), runtime)
+	}
+
+	// error during task execution
+	if result, ok := task.Response.Result.(*wasimoff.Task_Response_Error); ok {
+		return failed(result.Error, durationpb.New(task.Runtime))
+	}
+
+	// normal expected result
+	if result, ok := expected(task.Response); ok {
+		return result
+	}
+
+	// unexpected result type
+	log.Printf("DEBUG: unexpected result type: %s", protojson.Format(task.Response))
+	return failed("unexpected result type", nil)
+}
+
+// wasip1Result repacks the response of a finished task as a *pb.Task_Wasip1_Result
+func wasip1Result(task *provider.AsyncTask) *wasimoff.Task_Wasip1_Result {
+	return repackResult(task,
+		func(message string, runtime *durationpb.Duration) *wasimoff.Task_Wasip1_Result {
+			return &wasimoff.Task_Wasip1_Result{Result: &wasimoff.Task_Wasip1_Result_Error{
+				Error: message,
+			}, Runtime: runtime}
+		},
+		func(response *wasimoff.Task_Response) (*wasimoff.Task_Wasip1_Result, bool) {
+			switch result := response.Result.(type) {
+			case *wasimoff.Task_Response_Wasip1:
+				return &wasimoff.Task_Wasip1_Result{Result: result.Wasip1.Result, Runtime: result.Wasip1.Runtime}, true
+			}
+			return nil, false
+		})
+}
+
+// pyodideResult repacks the response of a finished task as a *pb.Task_Pyodide_Result
+func pyodideResult(task *provider.AsyncTask) *wasimoff.Task_Pyodide_Result {
+	return repackResult(task,
+		func(message string, runtime *durationpb.Duration) *wasimoff.Task_Pyodide_Result {
+			return &wasimoff.Task_Pyodide_Result{Result: &wasimoff.Task_Pyodide_Result_Error{
+				Error: message,
+			}, Runtime: runtime}
+		},
+		func(response *wasimoff.Task_Response) (*wasimoff.Task_Pyodide_Result, bool) {
+			switch result := response.Result.(type) {
+			case *wasimoff.Task_Response_Pyodide:
+				return &wasimoff.Task_Pyodide_Result{Result: result.Pyodide.Result, Runtime: result.Pyodide.Runtime}, true
+			}
+			return nil, false
+		})
+}
+
+// jobResponse assembles the response type for the format of the job spec
+func jobResponse(spec JobSpec, err error, results []resultMessage) resultMessage {
+	var message *string
+	if err != nil {
+		message = proto.String(err.Error())
+	}
+	switch spec.(type) {
+	case *wasimoff.Client_Job_PyodideRequest:
+		response := &wasimoff.Client_Job_PyodideResponse{Error: message}
+		for _, result := range results {
+			response.Tasks = append(response.Tasks, result.(*wasimoff.Task_Pyodide_Result))
+		}
+		return response
+	default:
+		response := &wasimoff.Client_Job_Wasip1Response{Error: message}
+		for _, result := range results {
+			response.Tasks = append(response.Tasks, result.(*wasimoff.Task_Wasip1_Result))
+		}
+		return response
+	}
+}
+
+// taskResult wraps a single result with its index for streaming
+func (job *OffloadingJob) taskResult(index int) proto.Message {
+	switch result := job.results[index].(type) {
+	case *wasimoff.Task_Pyodide_Result:
+		return &wasimoff.Client_Job_PyodideTaskResult{
+			Index:  proto.Uint32(uint32(index)),
+			Result: result,
+			Job:    &job.JobID,
+		}
+	default:
+		return &wasimoff.Client_Job_Wasip1TaskResult{
+			Index:  proto.Uint32(uint32(index)),
+			Result: result.(*wasimoff.Task_Wasip1_Result),
+			Job:    &job.JobID,
+		}
+	}
+}
+
+// Done is closed when all tasks of the job have finished.
+func (job *OffloadingJob) Done() <-chan struct{} {
+	return job.done
+}
+
+// Cancel all pending tasks of the job. Results of finished tasks are retained.
+func (job *OffloadingJob) Cancel() {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	if job.cancel == nil || len(job.finished) == len(job.results) {
+		return // nothing to cancel
+	}
+	job.cancelled = true
+	job.cancel()
+}
+
+// Response collects the results of all tasks; only use this after Done().
+func (job *OffloadingJob) Response() resultMessage {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	if job.err != nil {
+		return jobResponse(job.JobSpec, job.err, nil)
+	}
+	return jobResponse(job.JobSpec, nil, job.results)
+}
+
+// Status returns the current progress counts of the job.
+func (job *OffloadingJob) Status() *wasimoff.Client_Job_Status {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	tasks := len(job.requests)
+	status := &wasimoff.Client_Job_Status{
+		Id:        &job.JobID,
+		Tasks:     proto.Uint32(uint32(tasks)),
+		Pending:   proto.Uint32(uint32(tasks - len(job.finished))),
+		Completed: proto.Uint32(uint32(len(job.finished) - job.failed)),
+		Failed:    proto.Uint32(uint32(job.failed)),
+		Cancelled: proto.Bool(job.cancelled),
+	}
+	if job.err != nil {
+		status.Error = proto.String(job.err.Error())
+		status.Pending = proto.Uint32(0)
+	}
+	return status
+}
+
+// resultsSince returns all results which finished after the first `n` results,
+// a channel to wait for further results and whether the job is complete. The
+// results are a *pb.Client_Job_Wasip1TaskResult or *pb.Client_Job_PyodideTaskResult.
+func (job *OffloadingJob) resultsSince(n int) (results []proto.Message, changed <-chan struct{}, complete bool) {
+	job.mutex.Lock()
+	defer job.mutex.Unlock()
+	for _, i := range job.finished[n:] {
+		results = append(results, job.taskResult(i))
+	}
+	complete = job.err != nil || len(job.finished) == len(job.results)
+	return results, job.changed, complete
+}
+
+//
+// ----------> asynchronous job api
+
+// Finished jobs are kept in memory for this long, so results can be retrieved.
+const jobRetention = time.Hour
+
+// JobStore keeps asynchronously submitted OffloadingJobs, so clients can poll
+// their progress and retrieve the results later, instead of holding a request
+// open for the entire duration of a job.
+type JobStore struct {
+	store     *provider.ProviderStore
+	admission *Admitter
+	jobs      *xsync.MapOf[string, *OffloadingJob]
+}
+
+// NewJobStore creates an empty JobStore, which dispatches to the taskQueue.
+func NewJobStore(store *provider.ProviderStore, admission *Admitter) *JobStore {
+	return &JobStore{
+		store:     store,
+		admission: admission,
+		jobs:      xsync.NewMapOf[*OffloadingJob](),
+	}
+}
+
+// The SubmitHandler accepts the same job specifications as the ExecHandler but
+// returns a Client_Job_Status with the job's ID immediately after queueing.
+// MARK: Submit
+func (s *JobStore) SubmitHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		// if there's something in err upon return, we should log that
+		var err error
+		defer func() {
+			if err != nil {
+				log.Printf("ERR: Client [%s]: %s", r.RemoteAddr, err)
+			}
+		}()
+
+		// check the content-type of the request
+		mt, _, _ := mime.ParseMediaType(r.Header.Get("content-type"))
+		if mt != "application/json" && mt != "application/protobuf" {
+			http.Error(w, "unsupported request content-type", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		// read the entire body, up to the maximum size
+		limitBody(w, r, s.store)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			err = bodyError(w, err)
+			return
+		}
+
+		// read the job specification of the requested format from the request body
+		spec, err := NewJobSpec(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			err = nil // don't log this
+			return
+		}
+		err = UnmarshalJobArgs(body, mt, spec)
+		if err != nil {
+			w.Header().Set("content-type", mt)
+			w.WriteHeader(http.StatusBadRequest)
+			MarshalJobResponse(w, mt, &wasimoff.Client_Job_Status{
+				Error: proto.String(err.Error()),
+			})
+			err = nil // don't log this
+			return
+		}
+
+		// dispatch the job independently of this request's context
+		job := NewOffloadingJob(spec, auth.Requester(r), auth.Tenant(r))
+		if err := job.admit(s.admission); err != nil {
+			rejectHTTP(w, err)
+			return
+		}
+		w.Header().Set("content-type", mt)
+		if err = job.Dispatch(context.Background(), s.store, taskQueue); err != nil {
+			w.WriteHeader(http.StatusFailedDependency)
+			err = MarshalJobResponse(w, mt, job.Status())
+			return
+		}
+		log.Printf("OffloadingJob [%s] from %q: %d tasks, asynchronous\n",
+			job.JobID, job.ClientAddr, len(job.requests))
+
+		// keep the job until some time after it finished
+		s.jobs.Store(job.JobID, job)
+		go func() {
+			<-job.Done()
+			time.AfterFunc(jobRetention, func() { s.jobs.Delete(job.JobID) })
+		}()
+
+		// return the status with job ID to the client
+		w.Header().Set("location", "/api/jobs/"+job.JobID)
+		w.WriteHeader(http.StatusAccepted)
+		err = MarshalJobResponse(w, mt, job.Status())
+
+	}
+}
+
+// The StatusHandler returns the progress counts of a job.
+func (s *JobStore) StatusHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.lookup(w, r)
+		if !ok {
+			return
+		}
+		if err := MarshalJobResponse(w, acceptedMediaType(r), job.Status()); err != nil {
+			log.Printf("ERR: Client [%s]: %s", r.RemoteAddr, err)
+		}
+	}
+}
+
+// The CancelHandler cancels all pending tasks of a job and returns its status.
+func (s *JobStore) CancelHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.lookup(w, r)
+		if !ok {
+			return
+		}
+		job.Cancel()
+		log.Printf("OffloadingJob [%s] from %q: cancelled by %q", job.JobID, job.ClientAddr, r.RemoteAddr)
+		if err := MarshalJobResponse(w, acceptedMediaType(r), job.Status()); err != nil {
+			log.Printf("ERR: Client [%s]: %s", r.RemoteAddr, err)
+		}
+	}
+}
+
+// The ResultsHandler streams the results of a job as Client_Job_Wasip1TaskResult
+// or Client_Job_PyodideTaskResult messages in order of completion. Already finished results are sent immediately
+// and the response ends when the last task finished. JSON results are written
+// as newline-delimited messages; Protobuf results are varint size-delimited.
+// MARK: Results
+func (s *JobStore) ResultsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.lookup(w, r)
+		if !ok {
+			return
+		}
+
+		// if there's something in err upon return, we should log that
+		var err error
+		defer func() {
+			if err != nil {
+				log.Printf("ERR: Client [%s]: %s", r.RemoteAddr, err)
+			}
+		}()
+
+		mt := acceptedMediaType(r)
+		switch mt {
+		case "application/json":
+			w.Header().Set("content-type", "application/x-ndjson")
+		case "application/protobuf":
+			w.Header().Set("content-type", "application/protobuf; delimited=true")
+		}
+		flusher := http.NewResponseController(w)
+
+		for sent := 0; ; {
+			results, changed, complete := job.resultsSince(sent)
+			for _, result := range results {
+				if err = writeDelimited(w, mt, result); err != nil {
+					return
+				}
+			}
+			sent += len(results)
+			if err = flusher.Flush(); err != nil {
+				err = fmt.Errorf("flushing results failed: %w", err)
+				return
+			}
+			if complete {
+				return
+			}
+			select {
+			case <-changed:
+			case <-r.Context().Done():
+				return // client went away
+			}
+		}
+
+	}
+}
+
+// The ListHandler streams the status of all jobs in the requester's namespace,
+// oldest first, in the same delimited format as the ResultsHandler.
+// MARK: List
+func (s *JobStore) ListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenant := auth.Tenant(r)
+		jobs := []*OffloadingJob{}
+		s.jobs.Range(func(_ string, job *OffloadingJob) bool {
+			if tenant == "" || job.Tenant == tenant {
+				jobs = append(jobs, job)
+			}
+			return true
+		})
+		slices.SortFunc(jobs, func(a, b *OffloadingJob) int {
+			return strings.Compare(a.JobID, b.JobID)
+		})
+
+		mt := acceptedMediaType(r)
+		switch mt {
+		case "application/json":
+			w.Header().Set("content-type", "application/x-ndjson")
+		case "application/protobuf":
+			w.Header().Set("content-type", "application/protobuf; delimited=true")
+		}
+		for _, job := range jobs {
+			if err := writeDelimited(w, mt, job.Status()); err != nil {
+				log.Printf("ERR: Client [%s]: %s", r.RemoteAddr, err)
+				return
+			}
+		}
+	}
+}
+
+// lookup the job from the request path or respond with an error; jobs of other
+// tenants are not found
+func (s *JobStore) lookup(w http.ResponseWriter, r *http.Request) (*OffloadingJob, bool) {
+	job, ok := s.jobs.Load(r.PathValue("id"))
+	if ok {
+		tenant := auth.Tenant(r)
+		ok = tenant == "" || job.Tenant == tenant
+	}
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+	}
+	return job, ok
+}
+
+// negotiate the response format from the accept header, defaulting to JSON
+func acceptedMediaType(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("accept"), ",") {
+		if mt, _, _ := mime.ParseMediaType(accept); mt == "application/protobuf" {
+			return mt
+		}
+	}
+	return "application/json"
+}
+
+// write a single message of a stream in the desired format
+func writeDelimited(w io.Writer, mt string, message proto.Message) (err error) {
+	switch mt {
+	case "application/json":
+		var body []byte
+		if body, err = protojson.Marshal(message); err == nil {
+			_, err = fmt.Fprintf(w, "%s\n", body)
+		}
+	case "application/protobuf":
+		_, err = protodelim.MarshalTo(w, message)
+	default:
+		panic("oops, unsupported content-type")
+	}
+	if err != nil {
+		return fmt.Errorf("writing result failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	wasimoff "wasimoff/proto/v1"
+)
+
+// ErrInvalidQoS is returned for submissions with QoS parameters, which can't be
+// honored; they are rejected before any of their tasks are queued.
+var ErrInvalidQoS = errors.New("invalid QoS parameters")
+
+// checkQoS validates the QoS parameters of a task or job; a deadline must leave
+// enough time for at least one attempt with the requested timeout
+func checkQoS(qos *wasimoff.Task_QoS) error {
+	timeout := qos.GetTimeout().AsDuration()
+	if qos.GetTimeout() != nil && timeout <= 0 {
+		return fmt.Errorf("%w: timeout must be positive", ErrInvalidQoS)
+	}
+	if d := qos.GetDeadline(); d != nil {
+		remaining := time.Until(d.AsTime())
+		if remaining <= 0 {
+			return fmt.Errorf("%w: deadline already passed", ErrInvalidQoS)
+		}
+		if remaining < timeout {
+			return fmt.Errorf("%w: deadline is sooner than the timeout", ErrInvalidQoS)
+		}
+	}
+	return nil
+}
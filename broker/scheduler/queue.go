@@ -0,0 +1,274 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"wasimoff/broker/metrics"
+	"wasimoff/broker/provider"
+)
+
+// ErrDeadlineExceeded is the result of tasks which could not finish before
+// the deadline in their QoS parameters.
+var ErrDeadlineExceeded = errors.New("deadline exceeded")
+
+// Tasks are sorted into priority classes by their QoS parameters; the
+// priority is clamped to this range.
+const (
+	minPriority     = -2
+	maxPriority     = 2
+	priorityClasses = maxPriority - minPriority + 1
+)
+
+// Tasks waiting in a class for this long are promoted to the next higher
+// class, so low-priority tasks can't starve.
+const agingInterval = 10 * time.Second
+
+// number of tasks waiting in each priority class, for introspection
+var queueDepth [priorityClasses]atomic.Int64
+
+// queuedTask is an AsyncTask waiting in the Dispatcher for a free ticket.
+type queuedTask struct {
+	task      *provider.AsyncTask
+	requester string             // share of the requester, see requesterKey
+	deadline  time.Time          // zero if there is none
+	sequence  uint64             // arrival order to break ties
+	class     int                // current priority class, may be raised by aging
+	since     time.Time          // waiting in the current class since
+	cancel    context.CancelFunc // release the task's derived context
+	stop      func() bool        // stop the expiry notification
+}
+
+// priorityQueue holds the waiting tasks of each requester in priority classes,
+// where each class is a taskHeap. Classes are strictly ordered; within a class
+// the requesters get weighted fair shares. It is only used from the Dispatcher
+// loop, so it is not safe for concurrent access.
+type priorityQueue struct {
+	classes [priorityClasses]map[string]*taskHeap
+	shares  *fairShares
+}
+
+func newPriorityQueue(fairshare FairShare) *priorityQueue {
+	q := &priorityQueue{shares: newFairShares(fairshare)}
+	for class := range q.classes {
+		q.classes[class] = make(map[string]*taskHeap)
+	}
+	return q
+}
+
+// push a task into the class given by its priority
+func (q *priorityQueue) push(item *queuedTask) {
+	priority := int(item.task.Request.GetQos().GetPriority())
+	item.class = min(max(priority, minPriority), maxPriority) - minPriority
+	item.requester = requesterKey(item.task.Request.GetInfo().GetRequester())
+	item.since = time.Now()
+	q.insert(item)
+	q.shares.queued(item.requester, +1)
+	q.publish()
+}
+
+// insert a task into the heap of its requester in its class
+func (q *priorityQueue) insert(item *queuedTask) {
+	h, ok := q.classes[item.class][item.requester]
+	if !ok {
+		h = &taskHeap{}
+		q.classes[item.class][item.requester] = h
+	}
+	heap.Push(h, item)
+}
+
+// pop the most urgent task of the requester with the smallest share from the
+// highest class, skipping requesters which reached their in-flight cap
+func (q *priorityQueue) pop() *queuedTask {
+	for class := priorityClasses - 1; class >= 0; class-- {
+		if requester, ok := q.shares.next(q.classes[class]); ok {
+			h := q.classes[class][requester]
+			item := heap.Pop(h).(*queuedTask)
+			if h.Len() == 0 {
+				delete(q.classes[class], requester)
+			}
+			q.shares.dispatched(requester)
+			q.publish()
+			return item
+		}
+	}
+	return nil
+}
+
+// ready checks if any task can be dispatched right now
+func (q *priorityQueue) ready() bool {
+	for class := range q.classes {
+		if _, ok := q.shares.next(q.classes[class]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// finished must be called when a dispatched task is done, to release its slot
+func (q *priorityQueue) finished(item *queuedTask) {
+	q.shares.finished(item.requester)
+}
+
+// removeDone takes all tasks whose context is done out of the queue
+func (q *priorityQueue) removeDone() (done []*queuedTask) {
+	for class := range q.classes {
+		done = append(done, q.remove(class, func(item *queuedTask) bool {
+			return item.task.Context.Err() != nil
+		})...)
+	}
+	for _, item := range done {
+		q.shares.queued(item.requester, -1)
+	}
+	q.publish()
+	return done
+}
+
+// age promotes tasks which waited too long in their class by one class
+func (q *priorityQueue) age(now time.Time) {
+	// go from the top, so tasks are promoted at most once per call
+	for class := priorityClasses - 2; class >= 0; class-- {
+		promoted := q.remove(class, func(item *queuedTask) bool {
+			return now.Sub(item.since) >= agingInterval
+		})
+		for _, item := range promoted {
+			item.class, item.since = class+1, now
+			q.insert(item)
+		}
+	}
+	q.publish()
+}
+
+// remove all matching tasks of all requesters in a class
+func (q *priorityQueue) remove(class int, match func(*queuedTask) bool) (removed []*queuedTask) {
+	for requester, h := range q.classes[class] {
+		removed = append(removed, h.remove(match)...)
+		if h.Len() == 0 {
+			delete(q.classes[class], requester)
+		}
+	}
+	return removed
+}
+
+// depth is the number of tasks in a class
+func (q *priorityQueue) depth(class int) (n int) {
+	for _, h := range q.classes[class] {
+		n += h.Len()
+	}
+	return n
+}
+
+// len is the number of tasks in all classes
+func (q *priorityQueue) len() (n int) {
+	for class := range q.classes {
+		n += q.depth(class)
+	}
+	return n
+}
+
+// publish the current depths for introspection
+func (q *priorityQueue) publish() {
+	for class := range q.classes {
+		depth := q.depth(class)
+		queueDepth[class].Store(int64(depth))
+		metrics.QueueDepth.WithLabelValues(strconv.Itoa(class + minPriority)).Set(float64(depth))
+	}
+}
+
+// taskHeap orders queued tasks earliest-deadline-first. Tasks without
+// a deadline come last, in the order they arrived.
+type taskHeap []*queuedTask
+
+func (h taskHeap) Len() int { return len(h) }
+func (h taskHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	switch {
+	case a.deadline.IsZero() != b.deadline.IsZero():
+		return !a.deadline.IsZero()
+	case !a.deadline.Equal(b.deadline):
+		return a.deadline.Before(b.deadline)
+	default:
+		return a.sequence < b.sequence
+	}
+}
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *taskHeap) Push(x any)   { *h = append(*h, x.(*queuedTask)) }
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// remove all tasks matching the predicate from the heap
+func (h *taskHeap) remove(match func(*queuedTask) bool) (removed []*queuedTask) {
+	remaining := (*h)[:0]
+	for _, item := range *h {
+		if match(item) {
+			removed = append(removed, item)
+		} else {
+			remaining = append(remaining, item)
+		}
+	}
+	clear((*h)[len(remaining):])
+	*h = remaining
+	heap.Init(h)
+	return removed
+}
+
+// newQueuedTask derives the task's context from the deadline in its QoS
+// parameters and notifies on `expired` when that context is done
+func newQueuedTask(task *provider.AsyncTask, sequence uint64, expired chan<- struct{}) *queuedTask {
+	item := &queuedTask{task: task, sequence: sequence, cancel: func() {}}
+	if deadline := task.Request.GetQos().GetDeadline(); deadline != nil {
+		item.deadline = deadline.AsTime()
+		task.Context, item.cancel = context.WithDeadlineCause(task.Context, item.deadline, ErrDeadlineExceeded)
+	}
+	item.stop = context.AfterFunc(task.Context, func() {
+		select {
+		case expired <- struct{}{}:
+		default: // a sweep is already pending
+		}
+	})
+	return item
+}
+
+// taskError returns ErrDeadlineExceeded if the task's deadline has passed
+// or the given error otherwise
+func taskError(task *provider.AsyncTask, err error) error {
+	if errors.Is(context.Cause(task.Context), ErrDeadlineExceeded) {
+		return ErrDeadlineExceeded
+	}
+	return err
+}
+
+// The QueueHandler returns a HTTP handler, which reports the number of tasks
+// waiting in the Dispatcher per priority class as JSON.
+func QueueHandler(admission *Admitter) http.HandlerFunc {
+	type class struct {
+		Priority int   `json:"priority"`
+		Pending  int64 `json:"pending"`
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := struct {
+			Admitted int     `json:"admitted"` // unfinished tasks, see Admitter
+			Incoming int     `json:"incoming"` // not yet sorted into a class
+			Classes  []class `json:"classes"`  // highest priority first
+		}{Admitted: admission.unfinished(), Incoming: len(taskQueue)}
+		for c := priorityClasses - 1; c >= 0; c-- {
+			status.Classes = append(status.Classes, class{c + minPriority, queueDepth[c].Load()})
+		}
+		w.Header().Set("content-type", "application/json")
+		if err := json.NewEncoder(w).Encode(status); err != nil {
+			log.Printf("ERR: Queue [%s]: %s", r.RemoteAddr, err)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+	"wasimoff/broker/provider"
+	wasimoff "wasimoff/proto/v1"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testTask describes a queued task with an id, a requester, a priority and an
+// optional deadline
+type testTask struct {
+	id        string
+	requester string
+	priority  int32
+	deadline  time.Duration // from now, zero is none
+}
+
+func (tt testTask) queued(ctx context.Context, sequence uint64) *queuedTask {
+	request := &wasimoff.Task_Request{
+		Info: &wasimoff.Task_Metadata{Id: proto.String(tt.id), Requester: proto.String(tt.requester)},
+		Qos:  &wasimoff.Task_QoS{Priority: proto.Int32(tt.priority)},
+	}
+	if tt.deadline != 0 {
+		request.Qos.Deadline = timestamppb.New(time.Now().Add(tt.deadline))
+	}
+	return newQueuedTask(provider.NewAsyncTask(ctx, request, nil, nil), sequence, make(chan struct{}, 1))
+}
+
+// pushAll queues the tasks in order
+func pushAll(q *priorityQueue, tasks []testTask) {
+	for i, tt := range tasks {
+		q.push(tt.queued(context.Background(), uint64(i)))
+	}
+}
+
+// popAll dispatches tasks until none is ready and returns their ids
+func popAll(q *priorityQueue) (ids []string) {
+	for q.ready() {
+		ids = append(ids, q.pop().task.Request.GetInfo().GetId())
+	}
+	return ids
+}
+
+func TestQueueOrder(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		tasks    []testTask
+		expected []string
+	}{
+		{
+			name:     "arrival order",
+			tasks:    []testTask{{id: "a"}, {id: "b"}, {id: "c"}},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "higher priority first",
+			tasks:    []testTask{{id: "low", priority: -1}, {id: "normal"}, {id: "high", priority: 2}},
+			expected: []string{"high", "normal", "low"},
+		},
+		{
+			name:     "priorities are clamped",
+			tasks:    []testTask{{id: "a", priority: 100}, {id: "b", priority: 2}, {id: "c", priority: -100}},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name: "earliest deadline first, then without deadline",
+			tasks: []testTask{
+				{id: "none"}, {id: "late", deadline: time.Hour}, {id: "early", deadline: time.Minute},
+			},
+			expected: []string{"early", "late", "none"},
+		},
+		{
+			name: "priority before deadline",
+			tasks: []testTask{
+				{id: "urgent", deadline: time.Minute}, {id: "important", priority: 1},
+			},
+			expected: []string{"important", "urgent"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newPriorityQueue(FairShare{})
+			pushAll(q, tt.tasks)
+			if ids := popAll(q); !slices.Equal(ids, tt.expected) {
+				t.Errorf("popped %v, expected %v", ids, tt.expected)
+			}
+			if q.len() != 0 {
+				t.Errorf("%d tasks left in the queue", q.len())
+			}
+		})
+	}
+}
+
+func TestQueueAging(t *testing.T) {
+	q := newPriorityQueue(FairShare{})
+	pushAll(q, []testTask{{id: "low", priority: minPriority}})
+	now := time.Now()
+
+	// promoted by one class per interval, up to the highest class
+	for class := 1; class < priorityClasses+2; class++ {
+		now = now.Add(agingInterval)
+		q.age(now)
+		expected := min(class, priorityClasses-1)
+		if q.depth(expected) != 1 {
+			t.Fatalf("after %d intervals, the task is not in class %d", class, expected)
+		}
+	}
+
+	// tasks which arrived recently are not promoted
+	pushAll(q, []testTask{{id: "new"}})
+	q.age(time.Now())
+	if q.depth(-minPriority) != 1 {
+		t.Errorf("a new task was promoted")
+	}
+}
+
+func TestQueueRemoveDone(t *testing.T) {
+	q := newPriorityQueue(FairShare{})
+	ctx, cancel := context.WithCancel(context.Background())
+	q.push(testTask{id: "cancelled", requester: "alice"}.queued(ctx, 0))
+	q.push(testTask{id: "waiting", requester: "alice"}.queued(context.Background(), 1))
+	cancel()
+
+	done := q.removeDone()
+	if len(done) != 1 || done[0].task.Request.GetInfo().GetId() != "cancelled" {
+		t.Fatalf("removeDone returned %d tasks", len(done))
+	}
+	if ids := popAll(q); !slices.Equal(ids, []string{"waiting"}) {
+		t.Errorf("popped %v after removing the cancelled task", ids)
+	}
+}
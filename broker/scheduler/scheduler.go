@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"reflect"
+	"time"
 	"wasimoff/broker/provider"
 )
 
@@ -17,9 +18,16 @@ type Scheduler interface {
 	RateTick()
 }
 
+// maxPending is the number of tasks the Dispatcher sorts at most; further
+// tasks wait in the incoming channel until some of them are dispatched.
+const maxPending = 1000
+
 // The Dispatcher takes a task queue and a provider selector strategy and then
-// decides which task to send to which provider for computation.
-func Dispatcher(selector Scheduler, queue chan *provider.AsyncTask) {
+// decides which task to send to which provider for computation. Tasks are taken
+// from the queue until maxPending tasks wait and sorted into priority classes.
+// Within a class, the requesters get weighted fair shares and their tasks are
+// dispatched earliest-deadline-first.
+func Dispatcher(selector Scheduler, queue chan *provider.AsyncTask, fairshare FairShare) {
 
 	// use ticketing to limit simultaneous schedules
 	tickets := make(chan struct{}, 8)
@@ -27,65 +35,151 @@ func Dispatcher(selector Scheduler, queue chan *provider.AsyncTask) {
 		tickets <- struct{}{}
 	}
 
-	for task := range queue {
-		<-tickets // get a ticket
-
-		// each task is handled in a separate goroutine
-		go func(task *provider.AsyncTask) {
-			interceptingChannel := make(chan *provider.AsyncTask, 1)
-			interceptedChannel := task.Intercept(interceptingChannel)
-
-			retries := 10
-			var err error
-			for i := 0; i < retries; i++ {
-
-				// when retrying, we need to reacquire a ticket
-				if i > 0 {
-					<-tickets
-				}
-
-				// schedule the task with a provider and release a ticket
-				err = selector.Schedule(context.TODO(), task)
-				tickets <- struct{}{}
-
-				// oops, scheduling error
-				if err != nil {
-					log.Printf("RETRY: selector.Schedule %s failed (%d)", task.Request.GetInfo().GetId(), i)
-					task.Error = nil
-					continue // retry
-				}
-
-				result := <-interceptingChannel
-
-				// oops, instantiation error or similar
-				if result.Error != nil {
-					// don't retry, if the context was cancelled
-					if errors.Is(result.Error, context.Canceled) {
-						break
-					}
-					log.Printf("RETRY: task %s failed (%d): %v", task.Request.GetInfo().GetId(), i, task.Error)
-					task.Error = nil
-					continue // retry
-				}
-
-				// application errors should not be retried, as they are probably client's fault
-				if result.Response.GetError() != "" || result.Response.OK() {
-					break
-				}
+	// tasks waiting for a ticket and a signal when any of them expires
+	pending := newPriorityQueue(fairshare)
+	expired := make(chan struct{}, 1)
+	sequence := uint64(0)
+
+	// dispatched tasks report back when they're done
+	finished := make(chan *queuedTask)
+
+	// regularly promote tasks that waited too long
+	aging := time.NewTicker(agingInterval / 10)
+	defer aging.Stop()
+
+	for {
+
+		// only wait for tickets when there is anything to dispatch
+		var free chan struct{}
+		if pending.ready() {
+			free = tickets
+		}
+
+		// only take new tasks while there is room, so a full queue blocks submitters
+		incoming := queue
+		if pending.len() >= maxPending {
+			incoming = nil
+		}
+
+		select {
+
+		// add new tasks to the queue, unless their deadline already passed
+		case task, ok := <-incoming:
+			if !ok {
+				return
+			}
+			sequence++
+			item := newQueuedTask(task, sequence, expired)
+			if err := task.Context.Err(); err != nil {
+				finishQueued(item, taskError(task, err))
+				continue
+			}
+			pending.push(item)
+
+		// drop expired or cancelled tasks from the queue
+		case <-expired:
+			for _, item := range pending.removeDone() {
+				finishQueued(item, taskError(item.task, item.task.Context.Err()))
+			}
+
+		// raise the priority of long waiting tasks
+		case now := <-aging.C:
+			pending.age(now)
+
+		// got a ticket, dispatch the most urgent task
+		case <-free:
+			item := pending.pop()
+			item.stop()
+			go func() {
+				dispatch(selector, tickets, item)
+				finished <- item
+			}()
+
+		// release the requester's slot
+		case item := <-finished:
+			pending.finished(item)
+
+		}
+	}
+}
+
+// finishQueued signals completion of a task that never left the queue
+func finishQueued(item *queuedTask, err error) {
+	item.stop()
+	item.cancel()
+	item.task.Error = err
+	item.task.Done()
+}
+
+// dispatch schedules a task with a provider and retries on errors until the task
+// succeeds, the retries are exhausted or its context is done. It is called with a
+// ticket and returns it after scheduling.
+func dispatch(selector Scheduler, tickets chan struct{}, item *queuedTask) {
+	defer item.cancel()
+	task := item.task
+	interceptingChannel := make(chan *provider.AsyncTask, 1)
+	interceptedChannel := task.Intercept(interceptingChannel)
+
+	retries := 10
+	var err error
+retry:
+	for i := 0; i < retries; i++ {
+
+		// when retrying, we need to reacquire a ticket
+		if i > 0 {
+			select {
+			case <-tickets:
+			case <-task.Context.Done():
+				err = task.Context.Err()
+				break retry
+			}
+		}
+
+		// schedule the task with a provider and release a ticket
+		err = selector.Schedule(task.Context, task)
+		tickets <- struct{}{}
 
+		// oops, scheduling error
+		if err != nil {
+			// don't retry, if the task was cancelled or expired while waiting
+			if task.Context.Err() != nil {
+				break
 			}
+			log.Printf("RETRY: selector.Schedule %s failed (%d)", task.Request.GetInfo().GetId(), i)
+			task.Error = nil
+			continue // retry
+		}
+
+		result := <-interceptingChannel
 
-			// still erroneous after retries, give up
-			if err != nil {
-				task.Error = err
-			} else {
-				// otherwise signal completion to measure throughput
-				selector.RateTick()
+		// oops, instantiation error or similar
+		if err = result.Error; err != nil {
+			// don't retry, if the context was cancelled, the deadline passed or the
+			// task timed out; it would most likely time out on any other Provider, too
+			if task.Context.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, provider.ErrTimeout) {
+				break
 			}
-			interceptedChannel <- task
+			log.Printf("RETRY: task %s failed (%d): %v", task.Request.GetInfo().GetId(), i, err)
+			task.Error = nil
+			continue // retry
+		}
 
-		}(task)
+		// application errors should not be retried, as they are probably client's fault
+		if result.Response.GetError() != "" || result.Response.OK() {
+			break
+		}
+
+	}
+
+	// still erroneous after retries, give up
+	if err != nil {
+		task.Error = taskError(task, err)
+	} else {
+		// otherwise signal completion to measure throughput
+		selector.RateTick()
 	}
+	interceptedChannel <- task
+
 }
 
 // dynamicSubmit uses `reflect.Select` to dynamically select a Provider to submit a task to.
@@ -7,7 +7,8 @@ import (
 )
 
 // The SimpleMatchSelector is another simple implementation of a ProviderSelector,
-// which simply yields the first available provider with the required files in its store.
+// which prefers available providers that already have the required files in their
+// store or can receive the missing files the quickest.
 type SimpleMatchSelector struct {
 	store *provider.ProviderStore
 }
@@ -22,25 +23,45 @@ func (s *SimpleMatchSelector) selectCandidates(task *provider.AsyncTask) (candid
 	// create a list of needed files to check with the providers
 	requiredFiles := task.Request.GetRequiredFiles()
 
-	// find suitable candidates with free slots
+	// sizes of the required files, looked up once when needed
+	sizes := make(map[string]int64, len(requiredFiles))
+	size := func(ref string) int64 {
+		if n, ok := sizes[ref]; ok {
+			return n
+		}
+		if info := s.store.Storage.Stat(ref); info != nil {
+			sizes[ref] = info.Size
+		}
+		return sizes[ref]
+	}
+
+	// find candidates with free slots which are ready the soonest
+	var best time.Duration
 	candidates = make([]*provider.Provider, 0, s.store.Size())
 	s.store.Range(func(addr string, p *provider.Provider) bool {
-		// check for files
+		// check for availability
+		if p.CurrentTasks() >= p.CurrentLimit() && !p.Waiting() {
+			return true
+		}
+		// estimate the time to transfer missing files
+		var missing int64
 		for _, file := range requiredFiles {
 			if !p.Has(file) {
-				// missing requirement, continue
-				return true
+				missing += size(file)
 			}
 		}
-		// check for availability
-		if p.CurrentTasks() < p.CurrentLimit() || p.Waiting() {
-			// append candidates with free capacity for tasks
+		cost := p.TransferTime(missing)
+		switch {
+		case len(candidates) == 0 || cost < best:
+			best = cost
+			candidates = append(candidates[:0], p)
+		case cost == best:
 			candidates = append(candidates, p)
 		}
 		return true
 	})
 
-	// no perfect candidates found? just fallback to the full list
+	// no available candidates found? just fallback to the full list
 	if len(candidates) == 0 {
 		candidates = s.store.Values()
 	}
@@ -4,6 +4,8 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"mime"
 	"regexp"
 	"slices"
@@ -45,6 +47,30 @@ func sha256Ref(bytes []byte) string {
 	return fmt.Sprintf("sha256:%x", digest)
 }
 
+// hashingReader computes the sha256: reference and the size of everything
+// that is read through it, so files can be hashed while streaming.
+type hashingReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	size   int64
+}
+
+func newHashingReader(r io.Reader) *hashingReader {
+	return &hashingReader{reader: r, hash: sha256.New()}
+}
+
+func (h *hashingReader) Read(p []byte) (n int, err error) {
+	n, err = h.reader.Read(p)
+	h.hash.Write(p[:n])
+	h.size += int64(n)
+	return
+}
+
+// Ref returns the reference of the contents read so far.
+func (h *hashingReader) Ref() string {
+	return fmt.Sprintf("sha256:%x", h.hash.Sum(nil))
+}
+
 var reSha256Addr = regexp.MustCompile("^sha256:[0-9a-f]{64}$")
 
 // IsRef uses a regular expression to check if the string is a SHA256 content address.
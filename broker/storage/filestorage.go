@@ -1,112 +1,139 @@
 package storage
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"iter"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 	wasimoff "wasimoff/proto/v1"
 )
 
 type AbstractFileStorage interface {
-	Insert(name, media string, blob []byte) (file *File, err error)
-	Get(nameOrRef string) *File
-	All() iter.Seq2[string, *File]
+	// Insert reads a new file, hashing it on the way. The optional name will be
+	// inserted into the lookup table and can be used to resolve the file later.
+	Insert(name, media, uploader string, r io.Reader) (info *FileInfo, err error)
+	// Stat describes a file by name or ref without reading it, or returns nil.
+	Stat(nameOrRef string) *FileInfo
+	// Open a file by name or ref for reading; it must be closed when done.
+	Open(nameOrRef string) (file io.ReadSeekCloser, info *FileInfo, err error)
+
+	// List describes all files with their names, without reading the contents.
+	List() []FileInfo
+	// Alias adds another name for an existing file and returns its ref.
+	Alias(name, nameOrRef string) (ref string, err error)
+	// Delete removes a name from the lookup table or, when given a ref, the
+	// file itself along with all its names. Returns the ref of a removed file.
+	Delete(nameOrRef string) (removed string, err error)
 }
 
-type FileStorage struct {
-	AbstractFileStorage
+// Presigner is implemented by backends, whose files can be downloaded directly
+// with a temporary URL instead of passing them through the Broker.
+type Presigner interface {
+	Presign(ref string, expiry time.Duration) (*url.URL, error)
 }
 
-// ResolvePbFile checks if this file is usable as an argument in offloading
-// requests, i.e. if it either contains a blob or is a known file in the
-// storage. If so, set the resolved Ref on the file.
-func (fs *FileStorage) ResolvePbFile(pbf *wasimoff.File) error {
+// ErrNotFound is returned when neither a file nor a name matches.
+var ErrNotFound = errors.New("file not found in storage")
+
+// FileInfo describes a stored file for listings.
+type FileInfo struct {
+	Ref      string     `json:"ref"`
+	Names    []string   `json:"names,omitempty"`
+	Media    string     `json:"media"`
+	Size     int64      `json:"size"`
+	Uploaded time.Time  `json:"uploaded"`
+	Uploader string     `json:"uploader,omitempty"`
+	Used     *time.Time `json:"used,omitempty"` // last use by a task, since startup
+}
 
-	// argument is nil, no need to do anything
-	if pbf == nil {
-		return nil
-	}
+// sortFileInfos orders a listing by upload time, oldest first
+func sortFileInfos(list []FileInfo) {
+	slices.SortFunc(list, func(a, b FileInfo) int {
+		if c := a.Uploaded.Compare(b.Uploaded); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Ref, b.Ref)
+	})
+}
 
-	// trivial errors when both are nil or both are given
-	if pbf.Blob == nil && pbf.Ref == nil {
-		return fmt.Errorf("both Blob and Ref are nil")
+// checkAlias makes sure that names can't be confused with content addresses
+func checkAlias(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
 	}
-	if pbf.Blob != nil && pbf.Ref != nil {
-		return fmt.Errorf("don't use both Blob and Ref together")
+	if IsRef(name) {
+		return fmt.Errorf("name cannot be a content address")
 	}
+	return nil
+}
 
-	// Blob is given directly, ok ...
-	if pbf.Blob != nil {
-		// check the media type, if given
-		if mt := pbf.GetMedia(); mt != "" {
-			mt, err := CheckMediaType(mt)
-			if err != nil {
-				return fmt.Errorf("invalid Media type")
-			}
-			pbf.Media = &mt
-		}
-		return nil
+// FileStorage wraps a storage backend with quotas and garbage collection, which
+// need to know when files are used by tasks. See gc.go.
+type FileStorage struct {
+	AbstractFileStorage
+
+	// Quota limits the size of stored files
+	Quota Quota
+	// TTL is the time after which unreferenced files are collected when they
+	// weren't used by any task; zero keeps them forever
+	TTL time.Duration
+	// Redirect downloads to presigned URLs valid for this duration, if the
+	// backend is a Presigner; zero serves all files through the Broker
+	Redirect time.Duration
+
+	mutex     sync.Mutex
+	used      map[string]time.Time // last use by a task, since startup
+	pinned    map[string]int       // number of jobs in flight per file
+	shared    bool                 // other Brokers use the backend, too
+	started   time.Time            // uses before are unknown
+	uploading int                  // uploads in flight
+	uploaded  map[string]bool      // files of uploads in flight, whether any was accepted
+}
+
+func newFileStorage(backend AbstractFileStorage) *FileStorage {
+	return &FileStorage{
+		AbstractFileStorage: backend,
+		used:                make(map[string]time.Time),
+		pinned:              make(map[string]int),
+		uploaded:            make(map[string]bool),
+		started:             time.Now(),
 	}
+}
 
-	// Ref is given, look it up in Storage
-	if file := fs.Get(*pbf.Ref); file != nil {
-		pbf.Media = &file.Media
-		pbf.Ref = &file.ref
+// Get reads an entire file from Storage, by name or ref, or returns nil.
+func (fs *FileStorage) Get(nameOrRef string) *File {
+	file, info, err := fs.Open(nameOrRef)
+	if err != nil {
 		return nil
 	}
+	defer file.Close()
+	blob, err := io.ReadAll(file)
+	if err != nil {
+		return nil
+	}
+	return &File{Media: info.Media, Bytes: blob, ref: info.Ref}
+}
 
-	// couldn't resolve the file
-	return fmt.Errorf("Ref not found in storage")
-
+// ResolvePbFile resolves a file in the global namespace, see Namespace.
+func (fs *FileStorage) ResolvePbFile(pbf *wasimoff.File) error {
+	return fs.Namespace("").ResolvePbFile(pbf)
 }
 
+// ResolveTaskFiles resolves the files of a task in the global namespace, see Namespace.
 func (fs *FileStorage) ResolveTaskFiles(request *wasimoff.Task_Request) error {
-	// collect errors for all tried files
-	errs := []error{}
-
-	switch p := request.Parameters.(type) {
-
-	case *wasimoff.Task_Request_Wasip1:
-		errs = append(errs, fs.ResolvePbFile(p.Wasip1.Binary))
-		errs = append(errs, fs.ResolvePbFile(p.Wasip1.Rootfs))
-
-	case *wasimoff.Task_Request_Pyodide:
-		// ok
-		// log.Fatalln("ResolveTaskFiles is not implemented for Pyodide yet")
-
-	}
-
-	// will be nil if there are no errs
-	return errors.Join(errs...)
+	return fs.Namespace("").ResolveTaskFiles(request)
 }
 
-// TODO: should store the upload time as modtime
+// modtime for files without a known upload time
 var zerotime = time.UnixMilli(0)
 
-// Make the FileStorage a http.Handler, so it can serve files on web requests.
-// Expects a path value '{filename}' to retrieve the correct file.
+// Make the FileStorage a http.Handler, so it can serve files on web requests
+// from the global namespace. Expects a path value '{filename}', see Namespace.
 func (fs *FileStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-
-	// get the filename from path pattern
-	filename := r.PathValue("filename")
-	if filename == "" {
-		http.Error(w, "path pattern not found", http.StatusInternalServerError)
-		return
-	}
-
-	// retrieve the file from storage
-	file := fs.Get(filename)
-	if file == nil {
-		http.Error(w, "File not Found in storage", http.StatusNotFound)
-		return
-	}
-
-	// put known content-type in a header and serve the file
-	w.Header().Add("content-type", file.Media)
-	w.Header().Add("x-wasimoff-ref", file.Ref())
-	http.ServeContent(w, r, "", zerotime, bytes.NewReader(file.Bytes))
-
+	fs.Namespace("").ServeHTTP(w, r)
 }
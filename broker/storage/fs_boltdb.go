@@ -1,12 +1,15 @@
 package storage
 
 import (
+	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
-	"iter"
+	"io"
 	"log"
 	"time"
 
+	"github.com/rs/xid"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -18,8 +21,17 @@ var (
 	fileBucket      = []byte("files")
 	mediaTypeBucket = []byte("mediatypes")
 	lookupBucket    = []byte("lookup")
+	uploadedBucket  = []byte("uploaded")
+	uploaderBucket  = []byte("uploader")
+	chunkBucket     = []byte("chunks")  // a nested bucket of chunks per upload
+	chunkedBucket   = []byte("chunked") // the upload id of chunked files by ref
 )
 
+// boltChunkSize is the size of the values that files are split into, so
+// neither a file needs to be buffered whole nor a transaction grows too large.
+// Files stored before are kept in a single value in the fileBucket.
+const boltChunkSize = 1 << 20
+
 func NewBoltFileStorage(path string) *FileStorage {
 
 	// open the boltdb file
@@ -32,13 +44,25 @@ func NewBoltFileStorage(path string) *FileStorage {
 	// ensure that all buckets exist
 	err = db.Update(func(tx *bolt.Tx) (err error) {
 		if _, e := tx.CreateBucketIfNotExists(fileBucket); e != nil {
-			errors.Join(err, e)
+			err = errors.Join(err, e)
 		}
 		if _, e := tx.CreateBucketIfNotExists(mediaTypeBucket); e != nil {
-			errors.Join(err, e)
+			err = errors.Join(err, e)
 		}
 		if _, e := tx.CreateBucketIfNotExists(lookupBucket); e != nil {
-			errors.Join(err, e)
+			err = errors.Join(err, e)
+		}
+		if _, e := tx.CreateBucketIfNotExists(uploadedBucket); e != nil {
+			err = errors.Join(err, e)
+		}
+		if _, e := tx.CreateBucketIfNotExists(uploaderBucket); e != nil {
+			err = errors.Join(err, e)
+		}
+		if _, e := tx.CreateBucketIfNotExists(chunkBucket); e != nil {
+			err = errors.Join(err, e)
+		}
+		if _, e := tx.CreateBucketIfNotExists(chunkedBucket); e != nil {
+			err = errors.Join(err, e)
 		}
 		return
 	})
@@ -46,93 +70,376 @@ func NewBoltFileStorage(path string) *FileStorage {
 		log.Fatalf("boltfs: cannot create buckets: %s", err)
 	}
 
-	return &FileStorage{
-		AbstractFileStorage: &BoltFileStorage{db},
+	// remove the chunks of uploads that were interrupted
+	err = db.Update(func(tx *bolt.Tx) error {
+		finished := make(map[string]bool)
+		tx.Bucket(chunkedBucket).ForEach(func(_, id []byte) error {
+			finished[string(id)] = true
+			return nil
+		})
+		interrupted := [][]byte{}
+		tx.Bucket(chunkBucket).ForEach(func(id, _ []byte) error {
+			if !finished[string(id)] {
+				interrupted = append(interrupted, bytes.Clone(id))
+			}
+			return nil
+		})
+		for _, id := range interrupted {
+			if err := tx.Bucket(chunkBucket).DeleteBucket(id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("boltfs: cannot remove interrupted uploads: %s", err)
 	}
+
+	return newFileStorage(&BoltFileStorage{db})
 }
 
 // Insert a new file into the Storage. The optional `name` will be inserted
-// into the lookup table and can be used to resolve the file later.
-func (fs *BoltFileStorage) Insert(name, media string, blob []byte) (file *File, err error) {
+// into the lookup table and can be used to resolve the file later. The file is
+// streamed into chunks in separate transactions while hashing it and only
+// committed under its ref at the end.
+func (fs *BoltFileStorage) Insert(name, media, uploader string, r io.Reader) (info *FileInfo, err error) {
 
 	// check the media type first because that's cheapest
 	media, err = CheckMediaType(media)
 	if err != nil {
 		return nil, fmt.Errorf("media: %w", err)
 	}
+	if name != "" {
+		if err := checkAlias(name); err != nil {
+			return nil, err
+		}
+	}
 
-	// use a *File struct to obtain the content hash
-	file = NewFile(media, blob)
-	ref := file.Ref()
+	// write the chunks, hashing the file on the way
+	hr := newHashingReader(r)
+	id, err := fs.writeChunks(hr)
+	if err != nil {
+		return nil, err
+	}
+	ref := hr.Ref()
+	uploaded, err := time.Now().MarshalBinary()
+	if err != nil {
+		fs.dropChunks(id)
+		return nil, fmt.Errorf("uploaded: %w", err)
+	}
 
 	err = fs.db.Update(func(tx *bolt.Tx) error {
-		// insert blob and mediatype into buckets
-		if err := tx.Bucket(fileBucket).Put([]byte(ref), blob); err != nil {
+		// keep the chunks under the ref, unless the file exists already
+		if _, exists := size(tx, []byte(ref)); exists {
+			if err := tx.Bucket(chunkBucket).DeleteBucket(id); err != nil {
+				return err
+			}
+		} else if err := tx.Bucket(chunkedBucket).Put([]byte(ref), id); err != nil {
 			return err
 		}
+		// insert mediatype and upload metadata into buckets
 		if err := tx.Bucket(mediaTypeBucket).Put([]byte(ref), []byte(media)); err != nil {
 			return err
 		}
+		if err := tx.Bucket(uploadedBucket).Put([]byte(ref), uploaded); err != nil {
+			return err
+		}
+		if err := tx.Bucket(uploaderBucket).Put([]byte(ref), []byte(uploader)); err != nil {
+			return err
+		}
 		// insert name in lookup, if given
 		if name != "" {
 			if err := tx.Bucket(lookupBucket).Put([]byte(name), []byte(ref)); err != nil {
 				return err
 			}
 		}
+		info = stat(tx, ref)
 		return nil
 	})
-	return file, err
+	if err != nil {
+		fs.dropChunks(id)
+	}
+	return info, err
 
 }
 
-// Get a File from Storage, either by Ref or a friendly name in lookup map.
-func (fs *BoltFileStorage) Get(nameOrRef string) (f *File) {
-	// attempt to fetch by ref directly
-	f = fs.get(nameOrRef)
-	if f == nil {
-		// try to lookup a friendly name
-		fs.db.View(func(tx *bolt.Tx) error {
-			ref := tx.Bucket(lookupBucket).Get([]byte(nameOrRef))
-			f = fs.get(string(ref))
-			return nil
+// writeChunks stores the contents of a reader in a new bucket of chunks, keyed
+// by their offset, and returns its id
+func (fs *BoltFileStorage) writeChunks(r io.Reader) (id []byte, err error) {
+	id = xid.New().Bytes()
+	err = fs.db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.Bucket(chunkBucket).CreateBucket(id)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating chunks: %w", err)
+	}
+	// the buffer can be reused because each transaction is committed before
+	buf := make([]byte, boltChunkSize)
+	for offset := uint64(0); ; {
+		n, err := io.ReadFull(r, buf)
+		if err == io.EOF {
+			return id, nil
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			fs.dropChunks(id)
+			return nil, fmt.Errorf("reading file: %w", err)
+		}
+		e := fs.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(chunkBucket).Bucket(id).Put(binary.BigEndian.AppendUint64(nil, offset), buf[:n])
 		})
+		if e != nil {
+			fs.dropChunks(id)
+			return nil, fmt.Errorf("writing chunk: %w", e)
+		}
+		if err == io.ErrUnexpectedEOF {
+			return id, nil
+		}
+		offset += uint64(n)
 	}
-	// at this point f is either nil or successfully resolved ...
+}
+
+// dropChunks removes the chunks of a failed upload
+func (fs *BoltFileStorage) dropChunks(id []byte) {
+	fs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(chunkBucket).DeleteBucket(id)
+	})
+}
+
+// Stat describes a File in Storage, either by Ref or a friendly name in lookup map.
+func (fs *BoltFileStorage) Stat(nameOrRef string) (info *FileInfo) {
+	fs.db.View(func(tx *bolt.Tx) error {
+		info = stat(tx, resolve(tx, nameOrRef))
+		return nil
+	})
 	return
 }
 
-func (fs *BoltFileStorage) get(ref string) (f *File) {
+// Open a File in Storage, either by Ref or a friendly name in lookup map. The
+// contents are read lazily and each read copies only the requested range out of
+// the database, so neither a transaction is kept open nor the file copied whole.
+func (fs *BoltFileStorage) Open(nameOrRef string) (io.ReadSeekCloser, *FileInfo, error) {
+	var info *FileInfo
 	fs.db.View(func(tx *bolt.Tx) error {
-		// try to get the file value and mediatype
-		value := tx.Bucket(fileBucket).Get([]byte(ref))
-		media := tx.Bucket(mediaTypeBucket).Get([]byte(ref))
-		if value == nil || media == nil {
-			return nil // no such file, f is nil
-		}
-		// copy the blob and return a *File
-		blob := make([]byte, len(value))
-		copy(blob, value)
-		f = NewFile(string(media), blob)
-		return nil // ok
+		info = stat(tx, resolve(tx, nameOrRef))
+		return nil
+	})
+	if info == nil {
+		return nil, nil, ErrNotFound
+	}
+	return &boltReader{db: fs.db, ref: []byte(info.Ref), size: info.Size}, info, nil
+}
+
+// boltReader reads a stored file in short read transactions
+type boltReader struct {
+	db     *bolt.DB
+	ref    []byte
+	size   int64
+	offset int64
+}
+
+func (r *boltReader) Read(p []byte) (n int, err error) {
+	if r.offset >= r.size {
+		return 0, io.EOF
+	}
+	err = r.db.View(func(tx *bolt.Tx) error {
+		if size, exists := size(tx, r.ref); !exists || size != r.size {
+			return ErrNotFound // deleted meanwhile
+		}
+		n = readAt(tx, r.ref, p, r.offset)
+		return nil
+	})
+	r.offset += int64(n)
+	return n, err
+}
+
+func (r *boltReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.size
+	}
+	if offset < 0 {
+		return 0, fmt.Errorf("seek before the start")
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+func (r *boltReader) Close() error {
+	return nil
+}
+
+// readAt copies the contents of an existing file at an offset below its size
+func readAt(tx *bolt.Tx, ref, p []byte, offset int64) (n int) {
+	if value := tx.Bucket(fileBucket).Get(ref); value != nil {
+		return copy(p, value[offset:])
+	}
+	c := chunks(tx, ref).Cursor()
+	// find the chunk containing the offset, which starts at or before it
+	k, v := c.Seek(binary.BigEndian.AppendUint64(nil, uint64(offset)))
+	if k == nil {
+		k, v = c.Last()
+	} else if int64(binary.BigEndian.Uint64(k)) > offset {
+		k, v = c.Prev()
+	}
+	for ; k != nil && n < len(p); k, v = c.Next() {
+		n += copy(p[n:], v[offset+int64(n)-int64(binary.BigEndian.Uint64(k)):])
+	}
+	return n
+}
+
+// chunks returns the bucket of chunks of a file or nil
+func chunks(tx *bolt.Tx, ref []byte) *bolt.Bucket {
+	if id := tx.Bucket(chunkedBucket).Get(ref); id != nil {
+		return tx.Bucket(chunkBucket).Bucket(id)
+	}
+	return nil
+}
+
+// size of a file in either layout and whether it exists at all
+func size(tx *bolt.Tx, ref []byte) (int64, bool) {
+	if value := tx.Bucket(fileBucket).Get(ref); value != nil {
+		return int64(len(value)), true
+	}
+	if chunks := chunks(tx, ref); chunks != nil {
+		k, v := chunks.Cursor().Last()
+		if k == nil {
+			return 0, true // empty file
+		}
+		return int64(binary.BigEndian.Uint64(k)) + int64(len(v)), true
+	}
+	return 0, false
+}
+
+// resolve a name or ref to a ref of an existing file, or an empty string
+func resolve(tx *bolt.Tx, nameOrRef string) string {
+	if _, exists := size(tx, []byte(nameOrRef)); exists {
+		return nameOrRef
+	}
+	if ref := tx.Bucket(lookupBucket).Get([]byte(nameOrRef)); ref != nil {
+		if _, exists := size(tx, ref); exists {
+			return string(ref)
+		}
+	}
+	return ""
+}
+
+// stat describes a file including its names or returns nil
+func stat(tx *bolt.Tx, ref string) *FileInfo {
+	key := []byte(ref)
+	size, exists := size(tx, key)
+	media := tx.Bucket(mediaTypeBucket).Get(key)
+	if !exists || media == nil {
+		return nil // no such file
+	}
+	info := &FileInfo{
+		Ref:      ref,
+		Media:    string(media),
+		Size:     size,
+		Uploaded: uploadTime(tx, ref),
+		Uploader: string(tx.Bucket(uploaderBucket).Get(key)),
+	}
+	tx.Bucket(lookupBucket).ForEach(func(name, r []byte) error {
+		if string(r) == ref {
+			info.Names = append(info.Names, string(name))
+		}
+		return nil
 	})
+	return info
+}
+
+// uploadTime reads the upload time of a file, which is zero for files
+// inserted before the times were recorded
+func uploadTime(tx *bolt.Tx, ref string) (t time.Time) {
+	if value := tx.Bucket(uploadedBucket).Get([]byte(ref)); value != nil {
+		t.UnmarshalBinary(value)
+	}
 	return
 }
 
-// Iterator over all Files in the storage.
-func (fs *BoltFileStorage) All() iter.Seq2[string, *File] {
-	return func(yield func(string, *File) bool) {
-		fs.db.View(func(tx *bolt.Tx) error {
-			return tx.Bucket(fileBucket).ForEach(func(k, _ []byte) error {
-				ref := string(k)
-				file := fs.get(ref)
-				if file == nil {
-					panic("boltfs: got a nil *File while iterating in All()")
-				}
-				if !yield(ref, file) {
-					return errors.New("end iteration")
-				}
-				return nil
+// List describes all files in the storage, sorted by upload time.
+func (fs *BoltFileStorage) List() (list []FileInfo) {
+	list = []FileInfo{}
+	fs.db.View(func(tx *bolt.Tx) error {
+		names := make(map[string][]string)
+		tx.Bucket(lookupBucket).ForEach(func(name, ref []byte) error {
+			names[string(ref)] = append(names[string(ref)], string(name))
+			return nil
+		})
+		uploaders := tx.Bucket(uploaderBucket)
+		return tx.Bucket(mediaTypeBucket).ForEach(func(k, media []byte) error {
+			ref := string(k)
+			size, _ := size(tx, k)
+			list = append(list, FileInfo{
+				Ref:      ref,
+				Names:    names[ref], // ForEach is sorted already
+				Media:    string(media),
+				Size:     size,
+				Uploaded: uploadTime(tx, ref),
+				Uploader: string(uploaders.Get(k)),
 			})
+			return nil
 		})
+	})
+	sortFileInfos(list)
+	return
+}
+
+// Alias adds another name in the lookup table for an existing file.
+func (fs *BoltFileStorage) Alias(name, nameOrRef string) (ref string, err error) {
+	if err := checkAlias(name); err != nil {
+		return "", err
 	}
+	err = fs.db.Update(func(tx *bolt.Tx) error {
+		if ref = resolve(tx, nameOrRef); ref == "" {
+			return ErrNotFound
+		}
+		return tx.Bucket(lookupBucket).Put([]byte(name), []byte(ref))
+	})
+	return
+}
+
+// Delete a name from the lookup table or a file with all of its names.
+func (fs *BoltFileStorage) Delete(nameOrRef string) (removed string, err error) {
+	err = fs.db.Update(func(tx *bolt.Tx) error {
+		key := []byte(nameOrRef)
+		lookup := tx.Bucket(lookupBucket)
+
+		// only remove the name if this is not a file
+		if _, exists := size(tx, key); !exists {
+			if lookup.Get(key) == nil {
+				return ErrNotFound
+			}
+			return lookup.Delete(key)
+		}
+
+		// remove the file and all names pointing to it; can't delete while iterating
+		if id := tx.Bucket(chunkedBucket).Get(key); id != nil {
+			if err := tx.Bucket(chunkBucket).DeleteBucket(id); err != nil {
+				return err
+			}
+		}
+		for _, bucket := range [][]byte{fileBucket, chunkedBucket, mediaTypeBucket, uploadedBucket, uploaderBucket} {
+			if err := tx.Bucket(bucket).Delete(key); err != nil {
+				return err
+			}
+		}
+		names := []string{}
+		lookup.ForEach(func(name, ref []byte) error {
+			if string(ref) == nameOrRef {
+				names = append(names, string(name))
+			}
+			return nil
+		})
+		for _, name := range names {
+			if err := lookup.Delete([]byte(name)); err != nil {
+				return err
+			}
+		}
+		removed = nameOrRef
+		return nil
+	})
+	return
 }
@@ -0,0 +1,363 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DirectoryFileStorage keeps every blob as a plain file in a directory, so it
+// can be inspected, backed up and synced with normal tools:
+//
+//	<root>/index.json          names, media types and upload metadata
+//	<root>/sha256/ab/ab12...   blobs by digest, sharded by the first byte
+//	<root>/tmp/                incomplete uploads
+//
+// Blobs are written to tmp/ first and renamed into place when complete; the
+// index is replaced the same way, so neither is ever seen half-written.
+type DirectoryFileStorage struct {
+	root string
+	// the index is replaced on every change, readers can keep the old one
+	mutex sync.RWMutex
+	index *directoryIndex
+}
+
+// directoryIndex is the contents of index.json
+type directoryIndex struct {
+	Files map[string]directoryEntry `json:"files"` // keyed by ref
+	Names map[string]string         `json:"names"` // name => ref
+}
+
+type directoryEntry struct {
+	Media    string    `json:"media"`
+	Size     int64     `json:"size"`
+	Uploaded time.Time `json:"uploaded"`
+	Uploader string    `json:"uploader,omitempty"`
+}
+
+func NewDirectoryFileStorage(root string) *FileStorage {
+	fs := &DirectoryFileStorage{root: root, index: &directoryIndex{
+		Files: make(map[string]directoryEntry),
+		Names: make(map[string]string),
+	}}
+
+	// to keep the API clean, we just abort in here since this happens only at startup
+	if err := os.MkdirAll(filepath.Join(root, "sha256"), 0o755); err != nil {
+		log.Fatalf("dirfs: cannot create directory: %s", err)
+	}
+	// incomplete uploads from a previous run are useless
+	if err := os.RemoveAll(fs.tmpdir()); err != nil {
+		log.Fatalf("dirfs: cannot clean up: %s", err)
+	}
+	if err := os.Mkdir(fs.tmpdir(), 0o755); err != nil {
+		log.Fatalf("dirfs: cannot create directory: %s", err)
+	}
+
+	// read an existing index
+	buf, err := os.ReadFile(fs.indexpath())
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatalf("dirfs: cannot read index: %s", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(buf, fs.index); err != nil {
+			log.Fatalf("dirfs: cannot parse index: %s", err)
+		}
+		if fs.index.Files == nil || fs.index.Names == nil {
+			log.Fatalf("dirfs: incomplete index: %s", fs.indexpath())
+		}
+	}
+	return newFileStorage(fs)
+}
+
+func (fs *DirectoryFileStorage) tmpdir() string {
+	return filepath.Join(fs.root, "tmp")
+}
+
+func (fs *DirectoryFileStorage) indexpath() string {
+	return filepath.Join(fs.root, "index.json")
+}
+
+// blobpath is the path of a blob, given a valid ref
+func (fs *DirectoryFileStorage) blobpath(ref string) string {
+	digest := strings.TrimPrefix(ref, "sha256:")
+	return filepath.Join(fs.root, "sha256", digest[:2], digest)
+}
+
+// Insert a new file into the Storage. The optional `name` will be inserted
+// into the lookup table and can be used to resolve the file later. The file is
+// streamed to disk without buffering it in memory.
+func (fs *DirectoryFileStorage) Insert(name, media, uploader string, r io.Reader) (info *FileInfo, err error) {
+
+	// check the media type first because that's cheapest
+	media, err = CheckMediaType(media)
+	if err != nil {
+		return nil, fmt.Errorf("media: %w", err)
+	}
+	if name != "" {
+		if err := checkAlias(name); err != nil {
+			return nil, err
+		}
+	}
+
+	// stream to a temporary file, hashing it on the way
+	hr := newHashingReader(r)
+	tmp, err := writeTemp(fs.tmpdir(), "upload-*", func(w io.Writer) error {
+		_, err := io.Copy(w, hr)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("writing file: %w", err)
+	}
+	defer os.Remove(tmp) // no-op when renamed successfully
+	ref := hr.Ref()
+
+	// move the blob into place and add it to the index
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	err = fs.update(func(index *directoryIndex) error {
+		path := fs.blobpath(ref)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return err
+		}
+		// persist the rename and a new shard directory before the index
+		if err := syncDir(filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := syncDir(filepath.Dir(filepath.Dir(path))); err != nil {
+			return err
+		}
+		index.Files[ref] = directoryEntry{
+			Media:    media,
+			Size:     hr.size,
+			Uploaded: time.Now(),
+			Uploader: uploader,
+		}
+		if name != "" {
+			index.Names[name] = ref
+		}
+		info = index.stat(ref)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Stat describes a File in Storage, either by Ref or a friendly name in lookup map.
+func (fs *DirectoryFileStorage) Stat(nameOrRef string) *FileInfo {
+	index := fs.current()
+	return index.stat(index.resolve(nameOrRef))
+}
+
+// Open a File in Storage, either by Ref or a friendly name in lookup map.
+func (fs *DirectoryFileStorage) Open(nameOrRef string) (io.ReadSeekCloser, *FileInfo, error) {
+	index := fs.current()
+	ref := index.resolve(nameOrRef)
+	info := index.stat(ref)
+	if info == nil {
+		return nil, nil, ErrNotFound
+	}
+	// an open file can still be read after it was deleted concurrently
+	file, err := os.Open(fs.blobpath(ref))
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, info, nil
+}
+
+// List describes all files in the storage, sorted by upload time.
+func (fs *DirectoryFileStorage) List() []FileInfo {
+	index := fs.current()
+	names := make(map[string][]string)
+	for name, ref := range index.Names {
+		names[ref] = append(names[ref], name)
+	}
+	list := make([]FileInfo, 0, len(index.Files))
+	for ref, entry := range index.Files {
+		slices.Sort(names[ref])
+		list = append(list, FileInfo{
+			Ref:      ref,
+			Names:    names[ref],
+			Media:    entry.Media,
+			Size:     entry.Size,
+			Uploaded: entry.Uploaded,
+			Uploader: entry.Uploader,
+		})
+	}
+	sortFileInfos(list)
+	return list
+}
+
+// Alias adds another name in the index for an existing file.
+func (fs *DirectoryFileStorage) Alias(name, nameOrRef string) (ref string, err error) {
+	if err := checkAlias(name); err != nil {
+		return "", err
+	}
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	err = fs.update(func(index *directoryIndex) error {
+		if ref = index.resolve(nameOrRef); ref == "" {
+			return ErrNotFound
+		}
+		index.Names[name] = ref
+		return nil
+	})
+	return
+}
+
+// Delete a name from the index or a file with all of its names.
+func (fs *DirectoryFileStorage) Delete(nameOrRef string) (removed string, err error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	err = fs.update(func(index *directoryIndex) error {
+		if _, ok := index.Files[nameOrRef]; ok {
+			delete(index.Files, nameOrRef)
+			for name, ref := range index.Names {
+				if ref == nameOrRef {
+					delete(index.Names, name)
+				}
+			}
+			removed = nameOrRef
+			return nil
+		}
+		if _, ok := index.Names[nameOrRef]; ok {
+			delete(index.Names, nameOrRef)
+			return nil
+		}
+		return ErrNotFound
+	})
+	if err != nil || removed == "" {
+		return "", err
+	}
+	// remove the blob after the index, so a crash leaves an orphan at worst
+	if err := os.Remove(fs.blobpath(removed)); err != nil && !os.IsNotExist(err) {
+		log.Printf("dirfs: removing %s: %s", removed, err)
+	}
+	return removed, nil
+}
+
+// current returns the index, which must not be modified
+func (fs *DirectoryFileStorage) current() *directoryIndex {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	return fs.index
+}
+
+// update applies a change to a copy of the index and persists it; the change
+// is discarded if it fails or the index can't be written;
+// must be called with the mutex held
+func (fs *DirectoryFileStorage) update(change func(index *directoryIndex) error) error {
+	index := &directoryIndex{
+		Files: maps.Clone(fs.index.Files),
+		Names: maps.Clone(fs.index.Names),
+	}
+	if err := change(index); err != nil {
+		return err
+	}
+	buf, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding index: %w", err)
+	}
+	tmp, err := writeTemp(fs.tmpdir(), "index-*", func(w io.Writer) error {
+		_, err := w.Write(buf)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+	if err := os.Rename(tmp, fs.indexpath()); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing index: %w", err)
+	}
+	if err := syncDir(fs.root); err != nil {
+		return fmt.Errorf("writing index: %w", err)
+	}
+	fs.index = index
+	return nil
+}
+
+// resolve a name or ref to a ref of an existing file, or an empty string
+func (index *directoryIndex) resolve(nameOrRef string) string {
+	if _, ok := index.Files[nameOrRef]; ok {
+		return nameOrRef
+	}
+	if ref, ok := index.Names[nameOrRef]; ok {
+		if _, ok := index.Files[ref]; ok {
+			return ref
+		}
+	}
+	return ""
+}
+
+// stat describes a file including its names or returns nil
+func (index *directoryIndex) stat(ref string) *FileInfo {
+	entry, ok := index.Files[ref]
+	if !ok {
+		return nil
+	}
+	info := &FileInfo{
+		Ref:      ref,
+		Media:    entry.Media,
+		Size:     entry.Size,
+		Uploaded: entry.Uploaded,
+		Uploader: entry.Uploader,
+	}
+	for name, r := range index.Names {
+		if r == ref {
+			info.Names = append(info.Names, name)
+		}
+	}
+	slices.Sort(info.Names)
+	return info
+}
+
+// writeTemp creates a temporary file in dir, fills it and syncs it to disk, so
+// it can be renamed into place atomically; it is removed again on errors
+func writeTemp(dir, pattern string, fill func(w io.Writer) error) (path string, err error) {
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	if err = fill(file); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err = file.Close(); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// syncDir syncs a directory to disk, so a rename into it survives a crash
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
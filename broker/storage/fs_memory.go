@@ -1,75 +1,189 @@
 package storage
 
 import (
+	"bytes"
 	"fmt"
-	"iter"
+	"io"
 	"log"
+	"slices"
+	"sync"
+	"time"
 )
 
-// TODO: this storage is probably not threadsafe
-
 type MemoryFileStorage struct {
+	// files are deleted over HTTP concurrently to the scheduler
+	mutex sync.RWMutex
 	// collection of files in storage, keyed by content address
-	files map[string]*File
+	files map[string]*memoryFile
 	// a lookup table of plain names to content addresses
 	lookup map[string]string
 }
 
+// memoryFile is a stored blob with its metadata; the names are in the lookup
+type memoryFile struct {
+	info  FileInfo
+	bytes []byte
+}
+
 func NewMemoryFileStorage() *FileStorage {
-	return &FileStorage{
-		AbstractFileStorage: &MemoryFileStorage{
-			files:  make(map[string]*File),
-			lookup: make(map[string]string),
-		},
-	}
+	return newFileStorage(&MemoryFileStorage{
+		files:  make(map[string]*memoryFile),
+		lookup: make(map[string]string),
+	})
 }
 
 // Insert a new file into the Storage. The optional `name` will be inserted
 // into the lookup table and can be used to resolve the file later.
-func (fs *MemoryFileStorage) Insert(name, media string, blob []byte) (file *File, err error) {
+func (fs *MemoryFileStorage) Insert(name, media, uploader string, r io.Reader) (info *FileInfo, err error) {
 
 	// check the media type first because that's cheapest
 	media, err = CheckMediaType(media)
 	if err != nil {
 		return nil, fmt.Errorf("media: %w", err)
 	}
+	if name != "" {
+		if err := checkAlias(name); err != nil {
+			return nil, err
+		}
+	}
+
+	// read the file once, hashing it on the way
+	hr := newHashingReader(r)
+	blob, err := io.ReadAll(hr)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	ref := hr.Ref()
 
 	// we could check if the file exists already here but since we operate on
 	// memory for now, we can just overwrite whatever is there cheaply
-	file = NewFile(media, blob)
-	ref := file.Ref()
-	fs.files[ref] = file
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	fs.files[ref] = &memoryFile{bytes: blob, info: FileInfo{
+		Ref:      ref,
+		Media:    media,
+		Size:     hr.size,
+		Uploaded: time.Now(),
+		Uploader: uploader,
+	}}
 
 	// maybe insert name in lookup map, if given
 	if name != "" {
 		fs.lookup[name] = ref
 	}
 	fs.debug()
-	return
+	return fs.stat(ref), nil
+}
+
+// Stat describes a File in Storage, either by Ref or a friendly name in lookup map.
+func (fs *MemoryFileStorage) Stat(nameOrRef string) *FileInfo {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	return fs.stat(fs.resolve(nameOrRef))
 }
 
-// Get a File from Storage, either by Ref or a friendly name in lookup map.
-func (fs *MemoryFileStorage) Get(nameOrRef string) *File {
+// Open a File in Storage, either by Ref or a friendly name in lookup map.
+func (fs *MemoryFileStorage) Open(nameOrRef string) (io.ReadSeekCloser, *FileInfo, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	ref := fs.resolve(nameOrRef)
+	file, ok := fs.files[ref]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	// blobs are never modified, so they can be read after unlocking
+	return nopCloser{bytes.NewReader(file.bytes)}, fs.stat(ref), nil
+}
+
+// nopCloser adds a no-op Close method to a bytes.Reader
+type nopCloser struct{ *bytes.Reader }
+
+func (nopCloser) Close() error { return nil }
+
+// resolve a name or ref to a ref of an existing file, or an empty string;
+// must be called with the mutex held
+func (fs *MemoryFileStorage) resolve(nameOrRef string) string {
 	// try from files directly first
-	if file := fs.files[nameOrRef]; file != nil {
-		return file
+	if _, ok := fs.files[nameOrRef]; ok {
+		return nameOrRef
 	}
 	// or lookup a friendly name
 	if ref, ok := fs.lookup[nameOrRef]; ok {
-		return fs.files[ref]
+		if _, ok := fs.files[ref]; ok {
+			return ref
+		}
+	}
+	return ""
+}
+
+// stat copies the info of a file including its names or returns nil;
+// must be called with the mutex held
+func (fs *MemoryFileStorage) stat(ref string) *FileInfo {
+	file, ok := fs.files[ref]
+	if !ok {
+		return nil
+	}
+	info := file.info
+	for name, r := range fs.lookup {
+		if r == ref {
+			info.Names = append(info.Names, name)
+		}
+	}
+	slices.Sort(info.Names)
+	return &info
+}
+
+// List describes all files in the storage, sorted by upload time.
+func (fs *MemoryFileStorage) List() []FileInfo {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	names := make(map[string][]string)
+	for name, ref := range fs.lookup {
+		names[ref] = append(names[ref], name)
+	}
+	list := make([]FileInfo, 0, len(fs.files))
+	for ref, file := range fs.files {
+		info := file.info
+		info.Names = names[ref]
+		slices.Sort(info.Names)
+		list = append(list, info)
+	}
+	sortFileInfos(list)
+	return list
+}
+
+// Alias adds another name in the lookup map for an existing file.
+func (fs *MemoryFileStorage) Alias(name, nameOrRef string) (ref string, err error) {
+	if err := checkAlias(name); err != nil {
+		return "", err
 	}
-	return nil
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	if ref = fs.resolve(nameOrRef); ref == "" {
+		return "", ErrNotFound
+	}
+	fs.lookup[name] = ref
+	return ref, nil
 }
 
-// Iterator over all Files in the storage.
-func (fs *MemoryFileStorage) All() iter.Seq2[string, *File] {
-	return func(yield func(string, *File) bool) {
-		for ref, file := range fs.files {
-			if !yield(ref, file) {
-				return
+// Delete a name from the lookup map or a file with all of its names.
+func (fs *MemoryFileStorage) Delete(nameOrRef string) (removed string, err error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	if _, ok := fs.files[nameOrRef]; ok {
+		delete(fs.files, nameOrRef)
+		for name, ref := range fs.lookup {
+			if ref == nameOrRef {
+				delete(fs.lookup, name)
 			}
 		}
+		return nameOrRef, nil
+	}
+	if _, ok := fs.lookup[nameOrRef]; ok {
+		delete(fs.lookup, nameOrRef)
+		return "", nil
 	}
+	return "", ErrNotFound
 }
 
 func (fs *MemoryFileStorage) debug() {
@@ -78,6 +192,6 @@ func (fs *MemoryFileStorage) debug() {
 		fmt.Printf(" %s => %s\n", k, v)
 	}
 	for k, f := range fs.files {
-		fmt.Printf("+ %s => %s, %d bytes\n", k, f.Media, len(f.Bytes))
+		fmt.Printf("+ %s => %s, %d bytes\n", k, f.info.Media, f.info.Size)
 	}
 }
@@ -0,0 +1,394 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rs/xid"
+)
+
+// S3FileStorage keeps files in a bucket of an S3-compatible object storage, so
+// multiple Brokers can share them. Nothing is cached locally:
+//
+//	<prefix>sha256/<hex>         blobs with media type and uploader in metadata
+//	<prefix>names/<name>         empty objects with the ref in metadata
+//	<prefix>refs/<hex>/<name>    reverse lookup to list the names of a blob
+//	<prefix>uploads/<id>         incomplete uploads
+//
+// Uploads are streamed to a temporary object and copied to their content
+// address once the digest is known.
+type S3FileStorage struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// user metadata keys on objects
+const (
+	s3MetaMedia    = "Media"
+	s3MetaUploader = "Uploader"
+	s3MetaRef      = "Ref"
+)
+
+// size of the parts when streaming uploads of unknown length; files are
+// limited to 5 GiB anyway, because that's the most that can be copied at once
+const s3PartSize = 16 << 20
+
+// NewS3FileStorage connects to a bucket given as a URL like
+// https://minio.example.com:9000/bucket/prefix?region=us-east-1. Credentials
+// are taken from the usual AWS_* or MINIO_* environment variables. The location
+// ":memory:" starts an in-process fake instead, which keeps files in memory.
+func NewS3FileStorage(location string) *FileStorage {
+	if location == ":memory:" {
+		return newFakeS3FileStorage()
+	}
+
+	// to keep the API clean, we just abort in here since this happens only at startup
+	u, err := url.Parse(location)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		log.Fatalf("s3fs: invalid location, expected http(s)://host/bucket/prefix: %q", location)
+	}
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if bucket == "" {
+		log.Fatalf("s3fs: no bucket in location: %q", location)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	client, err := minio.New(u.Host, &minio.Options{
+		Creds: credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+		}),
+		Secure: u.Scheme == "https",
+		Region: u.Query().Get("region"),
+	})
+	if err != nil {
+		log.Fatalf("s3fs: cannot create client: %s", err)
+	}
+	return newS3FileStorage(client, bucket, prefix)
+}
+
+// newFakeS3FileStorage serves an in-memory bucket on a loopback port and
+// connects to it, so the S3 backend can be tried without an object storage
+func newFakeS3FileStorage() *FileStorage {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("s3fs: cannot listen for the fake bucket: %s", err)
+	}
+	go http.Serve(listener, newS3Fake("wasimoff"))
+	log.Printf("s3fs: serving a fake bucket on http://%s/wasimoff", listener.Addr())
+	client, err := minio.New(listener.Addr().String(), &minio.Options{
+		Creds:  credentials.NewStaticV4("fake", "fake", ""), // not checked, but needed to presign
+		Region: "us-east-1",
+	})
+	if err != nil {
+		log.Fatalf("s3fs: cannot create client: %s", err)
+	}
+	return newS3FileStorage(client, "wasimoff", "")
+}
+
+// newS3FileStorage checks that the bucket exists and wraps the backend; files
+// may be used by other Brokers, so they are never collected or evicted
+func newS3FileStorage(client *minio.Client, bucket, prefix string) *FileStorage {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if ok, err := client.BucketExists(ctx, bucket); err != nil || !ok {
+		log.Fatalf("s3fs: cannot access bucket %q: exists=%v, %v", bucket, ok, err)
+	}
+	fs := newFileStorage(&S3FileStorage{client, bucket, prefix})
+	fs.shared = true
+	return fs
+}
+
+// object keys for blobs, names and the reverse lookup
+func (fs *S3FileStorage) blobkey(ref string) string {
+	return fs.prefix + "sha256/" + strings.TrimPrefix(ref, "sha256:")
+}
+
+func (fs *S3FileStorage) namekey(name string) string {
+	return fs.prefix + "names/" + name
+}
+
+func (fs *S3FileStorage) refkey(ref, name string) string {
+	return fs.prefix + "refs/" + strings.TrimPrefix(ref, "sha256:") + "/" + name
+}
+
+// Insert a new file into the Storage. The optional `name` will be inserted
+// into the lookup table and can be used to resolve the file later. The file is
+// streamed to the bucket without buffering it entirely.
+func (fs *S3FileStorage) Insert(name, media, uploader string, r io.Reader) (info *FileInfo, err error) {
+	ctx := context.Background()
+
+	// check the media type first because that's cheapest
+	media, err = CheckMediaType(media)
+	if err != nil {
+		return nil, fmt.Errorf("media: %w", err)
+	}
+	if name != "" {
+		if err := checkAlias(name); err != nil {
+			return nil, err
+		}
+	}
+
+	// stream to a temporary object, hashing it on the way
+	hr := newHashingReader(r)
+	tmp := fs.prefix + "uploads/" + xid.New().String()
+	_, err = fs.client.PutObject(ctx, fs.bucket, tmp, hr, -1, minio.PutObjectOptions{
+		ContentType:  media,
+		UserMetadata: map[string]string{s3MetaMedia: media, s3MetaUploader: uploader},
+		PartSize:     s3PartSize,
+		// the contents are hashed already and chunked signatures are not
+		// supported by all implementations
+		DisableContentSha256: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("writing file: %w", err)
+	}
+	defer fs.client.RemoveObject(ctx, fs.bucket, tmp, minio.RemoveObjectOptions{})
+	ref := hr.Ref()
+
+	// copy it to its content address, including the metadata
+	_, err = fs.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: fs.bucket, Object: fs.blobkey(ref)},
+		minio.CopySrcOptions{Bucket: fs.bucket, Object: tmp},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("writing file: %w", err)
+	}
+
+	// maybe insert name in lookup, if given
+	if name != "" {
+		if err := fs.setName(ctx, name, ref); err != nil {
+			return nil, err
+		}
+	}
+	if info = fs.stat(ctx, ref); info == nil {
+		return nil, fmt.Errorf("file vanished after upload")
+	}
+	return info, nil
+}
+
+// Stat describes a File in Storage, either by Ref or a friendly name in lookup.
+func (fs *S3FileStorage) Stat(nameOrRef string) *FileInfo {
+	ctx := context.Background()
+	return fs.stat(ctx, fs.resolve(ctx, nameOrRef))
+}
+
+// Open a File in Storage, either by Ref or a friendly name in lookup. The object
+// is fetched lazily with ranged requests as it is read.
+func (fs *S3FileStorage) Open(nameOrRef string) (io.ReadSeekCloser, *FileInfo, error) {
+	ctx := context.Background()
+	info := fs.stat(ctx, fs.resolve(ctx, nameOrRef))
+	if info == nil {
+		return nil, nil, ErrNotFound
+	}
+	object, err := fs.client.GetObject(ctx, fs.bucket, fs.blobkey(info.Ref), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	return object, info, nil
+}
+
+// Presign returns a URL to download a file directly from the bucket.
+func (fs *S3FileStorage) Presign(ref string, expiry time.Duration) (*url.URL, error) {
+	return fs.client.PresignedGetObject(context.Background(), fs.bucket, fs.blobkey(ref), expiry, nil)
+}
+
+// resolve a name to a ref, which is not checked for existence; use stat for that
+func (fs *S3FileStorage) resolve(ctx context.Context, nameOrRef string) string {
+	if IsRef(nameOrRef) {
+		return nameOrRef
+	}
+	return fs.lookup(ctx, nameOrRef)
+}
+
+// lookup the ref of a name, or an empty string
+func (fs *S3FileStorage) lookup(ctx context.Context, name string) string {
+	object, err := fs.client.StatObject(ctx, fs.bucket, fs.namekey(name), minio.StatObjectOptions{})
+	if err != nil {
+		logS3Error("lookup", name, err)
+		return ""
+	}
+	return s3Meta(object, s3MetaRef)
+}
+
+// stat describes a file including its names or returns nil
+func (fs *S3FileStorage) stat(ctx context.Context, ref string) *FileInfo {
+	if ref == "" {
+		return nil
+	}
+	object, err := fs.client.StatObject(ctx, fs.bucket, fs.blobkey(ref), minio.StatObjectOptions{})
+	if err != nil {
+		logS3Error("stat", ref, err)
+		return nil
+	}
+	info := s3FileInfo(ref, object)
+	prefix := fs.refkey(ref, "")
+	for name := range fs.list(ctx, strings.TrimPrefix(prefix, fs.prefix), false) {
+		info.Names = append(info.Names, strings.TrimPrefix(name.Key, prefix))
+	}
+	return info
+}
+
+// List describes all files in the storage, sorted by upload time.
+func (fs *S3FileStorage) List() []FileInfo {
+	ctx := context.Background()
+	names := make(map[string][]string)
+	for object := range fs.list(ctx, "refs/", false) {
+		hex, name, _ := strings.Cut(strings.TrimPrefix(object.Key, fs.prefix+"refs/"), "/")
+		names["sha256:"+hex] = append(names["sha256:"+hex], name)
+	}
+	list := []FileInfo{}
+	for object := range fs.list(ctx, "sha256/", true) {
+		ref := "sha256:" + strings.TrimPrefix(object.Key, fs.prefix+"sha256/")
+		// listings only include metadata on some implementations, e.g. MinIO
+		if s3Meta(object, s3MetaMedia) == "" {
+			if stat, err := fs.client.StatObject(ctx, fs.bucket, object.Key, minio.StatObjectOptions{}); err == nil {
+				object = stat
+			}
+		}
+		info := s3FileInfo(ref, object)
+		info.Names = names[ref]
+		slices.Sort(info.Names)
+		list = append(list, *info)
+	}
+	sortFileInfos(list)
+	return list
+}
+
+// Alias adds another name in the lookup for an existing file.
+func (fs *S3FileStorage) Alias(name, nameOrRef string) (ref string, err error) {
+	if err := checkAlias(name); err != nil {
+		return "", err
+	}
+	ctx := context.Background()
+	info := fs.stat(ctx, fs.resolve(ctx, nameOrRef))
+	if info == nil {
+		return "", ErrNotFound
+	}
+	return info.Ref, fs.setName(ctx, name, info.Ref)
+}
+
+// setName points a name to a ref, replacing its previous target
+func (fs *S3FileStorage) setName(ctx context.Context, name, ref string) error {
+	previous := fs.lookup(ctx, name)
+	_, err := fs.client.PutObject(ctx, fs.bucket, fs.namekey(name), http.NoBody, 0, minio.PutObjectOptions{
+		UserMetadata:         map[string]string{s3MetaRef: ref},
+		DisableContentSha256: true,
+	})
+	if err != nil {
+		return fmt.Errorf("writing name: %w", err)
+	}
+	if _, err = fs.client.PutObject(ctx, fs.bucket, fs.refkey(ref, name), http.NoBody, 0, minio.PutObjectOptions{
+		DisableContentSha256: true,
+	}); err != nil {
+		return fmt.Errorf("writing name: %w", err)
+	}
+	if previous != "" && previous != ref {
+		return fs.client.RemoveObject(ctx, fs.bucket, fs.refkey(previous, name), minio.RemoveObjectOptions{})
+	}
+	return nil
+}
+
+// Delete a name from the lookup or a file with all of its names.
+func (fs *S3FileStorage) Delete(nameOrRef string) (removed string, err error) {
+	ctx := context.Background()
+	remove := func(key string) {
+		if e := fs.client.RemoveObject(ctx, fs.bucket, key, minio.RemoveObjectOptions{}); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	// only remove the name if this is not a file
+	if !IsRef(nameOrRef) {
+		ref := fs.lookup(ctx, nameOrRef)
+		if ref == "" {
+			return "", ErrNotFound
+		}
+		remove(fs.namekey(nameOrRef))
+		remove(fs.refkey(ref, nameOrRef))
+		return "", err
+	}
+
+	// remove all names pointing to the file, then the file itself
+	info := fs.stat(ctx, nameOrRef)
+	if info == nil {
+		return "", ErrNotFound
+	}
+	for _, name := range info.Names {
+		if fs.lookup(ctx, name) == nameOrRef {
+			remove(fs.namekey(name))
+		}
+		remove(fs.refkey(nameOrRef, name))
+	}
+	remove(fs.blobkey(nameOrRef))
+	if err != nil {
+		return "", err
+	}
+	return nameOrRef, nil
+}
+
+// list objects below a prefix in the storage, optionally with user metadata
+func (fs *S3FileStorage) list(ctx context.Context, prefix string, metadata bool) func(yield func(minio.ObjectInfo) bool) {
+	return func(yield func(minio.ObjectInfo) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel() // stops the listing when breaking early
+		for object := range fs.client.ListObjects(ctx, fs.bucket, minio.ListObjectsOptions{
+			Prefix:       fs.prefix + prefix,
+			Recursive:    true,
+			WithMetadata: metadata,
+		}) {
+			if object.Err != nil {
+				logS3Error("list", prefix, object.Err)
+				return
+			}
+			if !yield(object) {
+				return
+			}
+		}
+	}
+}
+
+// s3FileInfo describes a blob from its object info
+func s3FileInfo(ref string, object minio.ObjectInfo) *FileInfo {
+	media := s3Meta(object, s3MetaMedia)
+	if media == "" {
+		media = object.ContentType
+	}
+	return &FileInfo{
+		Ref:      ref,
+		Media:    media,
+		Size:     object.Size,
+		Uploaded: object.LastModified,
+		Uploader: s3Meta(object, s3MetaUploader),
+	}
+}
+
+// s3Meta gets a user metadata value, which is prefixed in some listings
+func s3Meta(object minio.ObjectInfo, key string) string {
+	for k, v := range object.UserMetadata {
+		if strings.EqualFold(k, key) || strings.EqualFold(k, "X-Amz-Meta-"+key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// logS3Error logs unexpected errors, which are not just missing objects
+func logS3Error(op, key string, err error) {
+	if minio.ToErrorResponse(err).StatusCode != http.StatusNotFound {
+		log.Printf("s3fs: %s %s: %s", op, key, err)
+	}
+}
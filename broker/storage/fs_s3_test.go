@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// newTestS3FileStorage connects the S3 backend to a fake bucket with a prefix
+func newTestS3FileStorage(t *testing.T) *FileStorage {
+	t.Helper()
+	server := httptest.NewServer(newS3Fake("bucket"))
+	t.Cleanup(server.Close)
+	client, err := minio.New(strings.TrimPrefix(server.URL, "http://"), &minio.Options{
+		Creds:  credentials.NewStaticV4("fake", "fake", ""), // not checked, but needed to presign
+		Region: "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("creating client: %s", err)
+	}
+	return newS3FileStorage(client, "bucket", "prefix/")
+}
+
+func testRef(blob []byte) string {
+	digest := sha256.Sum256(blob)
+	return "sha256:" + hex.EncodeToString(digest[:])
+}
+
+func TestS3InsertStatOpen(t *testing.T) {
+	fs := newTestS3FileStorage(t)
+	blob := []byte("\x00asm\x01\x00\x00\x00")
+	ref := testRef(blob)
+
+	info, err := fs.Insert("hello.wasm", "application/wasm", "alice", bytes.NewReader(blob))
+	if err != nil {
+		t.Fatalf("Insert: %s", err)
+	}
+	if info.Ref != ref || info.Size != int64(len(blob)) || info.Media != "application/wasm" || info.Uploader != "alice" {
+		t.Errorf("Insert: unexpected info %+v", info)
+	}
+	if !slices.Equal(info.Names, []string{"hello.wasm"}) {
+		t.Errorf("Insert: names = %v", info.Names)
+	}
+	if info.Uploaded.IsZero() {
+		t.Errorf("Insert: no upload time")
+	}
+
+	for _, nameOrRef := range []string{"hello.wasm", ref} {
+		if stat := fs.Stat(nameOrRef); stat == nil || stat.Ref != ref {
+			t.Errorf("Stat(%q) = %+v", nameOrRef, stat)
+		}
+		file, _, err := fs.Open(nameOrRef)
+		if err != nil {
+			t.Fatalf("Open(%q): %s", nameOrRef, err)
+		}
+		contents, err := io.ReadAll(file)
+		file.Close()
+		if err != nil || !bytes.Equal(contents, blob) {
+			t.Errorf("Open(%q): read %q, %v", nameOrRef, contents, err)
+		}
+	}
+	if stat := fs.Stat("missing.wasm"); stat != nil {
+		t.Errorf("Stat(missing) = %+v", stat)
+	}
+	if _, _, err := fs.Open("missing.wasm"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open(missing): %v", err)
+	}
+	if _, err := fs.Insert("", "text/plain", "alice", bytes.NewReader(blob)); err == nil {
+		t.Errorf("Insert with unexpected media type succeeded")
+	}
+}
+
+func TestS3AliasListDelete(t *testing.T) {
+	fs := newTestS3FileStorage(t)
+	first, second := []byte("first"), []byte("second")
+	if _, err := fs.Insert("a.wasm", "application/wasm", "", bytes.NewReader(first)); err != nil {
+		t.Fatalf("Insert: %s", err)
+	}
+	if _, err := fs.Insert("", "application/wasm", "", bytes.NewReader(second)); err != nil {
+		t.Fatalf("Insert: %s", err)
+	}
+
+	// alias a second name and move the first one to the other file
+	if ref, err := fs.Alias("b.wasm", "a.wasm"); err != nil || ref != testRef(first) {
+		t.Fatalf("Alias(b.wasm) = %q, %v", ref, err)
+	}
+	if _, err := fs.Alias("a.wasm", testRef(second)); err != nil {
+		t.Fatalf("Alias(a.wasm): %s", err)
+	}
+	if _, err := fs.Alias("c.wasm", "missing.wasm"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Alias to a missing file: %v", err)
+	}
+	if _, err := fs.Alias(testRef(first), "b.wasm"); err == nil {
+		t.Errorf("Alias with a content address as name succeeded")
+	}
+
+	names := func() map[string][]string {
+		names := make(map[string][]string)
+		for _, info := range fs.List() {
+			names[info.Ref] = info.Names
+		}
+		return names
+	}
+	listed := names()
+	if len(listed) != 2 ||
+		!slices.Equal(listed[testRef(first)], []string{"b.wasm"}) ||
+		!slices.Equal(listed[testRef(second)], []string{"a.wasm"}) {
+		t.Errorf("List: names = %v", listed)
+	}
+
+	// deleting a name keeps the file
+	if removed, err := fs.Delete("b.wasm"); err != nil || removed != "" {
+		t.Errorf("Delete(b.wasm) = %q, %v", removed, err)
+	}
+	if stat := fs.Stat(testRef(first)); stat == nil || len(stat.Names) != 0 {
+		t.Errorf("Stat after deleting the name = %+v", stat)
+	}
+
+	// deleting a ref removes the file and its names
+	if removed, err := fs.Delete(testRef(second)); err != nil || removed != testRef(second) {
+		t.Errorf("Delete(ref) = %q, %v", removed, err)
+	}
+	if stat := fs.Stat("a.wasm"); stat != nil {
+		t.Errorf("name still resolves after deleting the file: %+v", stat)
+	}
+	if listed := names(); len(listed) != 1 {
+		t.Errorf("List after Delete = %v", listed)
+	}
+	if _, err := fs.Delete("missing.wasm"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(missing): %v", err)
+	}
+}
+
+func TestS3Presign(t *testing.T) {
+	fs := newTestS3FileStorage(t)
+	blob := []byte("presigned")
+	info, err := fs.Insert("", "application/wasm", "", bytes.NewReader(blob))
+	if err != nil {
+		t.Fatalf("Insert: %s", err)
+	}
+	presigner, ok := fs.AbstractFileStorage.(Presigner)
+	if !ok {
+		t.Fatalf("S3 backend is not a Presigner")
+	}
+	url, err := presigner.Presign(info.Ref, time.Minute)
+	if err != nil {
+		t.Fatalf("Presign: %s", err)
+	}
+	response, err := http.Get(url.String())
+	if err != nil {
+		t.Fatalf("GET presigned URL: %s", err)
+	}
+	defer response.Body.Close()
+	contents, _ := io.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || !bytes.Equal(contents, blob) {
+		t.Errorf("GET presigned URL: %s, %q", response.Status, contents)
+	}
+}
+
+func TestS3NeverCollected(t *testing.T) {
+	fs := newTestS3FileStorage(t)
+	fs.TTL = time.Nanosecond
+	info, err := fs.Insert("", "application/wasm", "", bytes.NewReader([]byte("unreferenced")))
+	if err != nil {
+		t.Fatalf("Insert: %s", err)
+	}
+	if removed := fs.Collect(time.Now().Add(time.Hour)); len(removed) != 0 {
+		t.Errorf("Collect removed files of a shared bucket: %v", removed)
+	}
+	if fs.Stat(info.Ref) == nil {
+		t.Errorf("file was removed")
+	}
+}
@@ -0,0 +1,272 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+	wasimoff "wasimoff/proto/v1"
+)
+
+// ErrQuotaExceeded is returned when a file does not fit into the storage, even
+// after evicting all unreferenced files.
+var ErrQuotaExceeded = errors.New("storage quota exceeded")
+
+// Quota limits the total size of stored files in bytes, overall and for each
+// uploader. Zero is unlimited.
+type Quota struct {
+	Total    int64
+	Uploader int64
+}
+
+// Files are referenced by their names and by jobs in flight. Unreferenced files
+// are collected when they were not used for the TTL or evicted, least-recently
+// used first, to make room for new uploads when a quota would be exceeded.
+// Backends shared by several Brokers are never collected or evicted, because
+// the files may be in use by another Broker; the quota is still enforced.
+
+// touch marks a file as used by a task
+func (fs *FileStorage) touch(ref string) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	fs.used[ref] = time.Now()
+}
+
+// Pin marks the files required by the requests as in use, so they are not
+// collected until the returned function is called. Use it after ResolveTaskFiles.
+func (fs *FileStorage) Pin(requests ...*wasimoff.Task_Request) (unpin func()) {
+	refs := []string{}
+	for _, request := range requests {
+		refs = append(refs, request.GetRequiredFiles()...)
+	}
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	for _, ref := range refs {
+		fs.pinned[ref]++
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			fs.mutex.Lock()
+			defer fs.mutex.Unlock()
+			for _, ref := range refs {
+				if fs.pinned[ref]--; fs.pinned[ref] <= 0 {
+					delete(fs.pinned, ref)
+				}
+			}
+		})
+	}
+}
+
+// List describes all files in the storage, including their last use.
+func (fs *FileStorage) List() []FileInfo {
+	list := fs.AbstractFileStorage.List()
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	for i := range list {
+		if used, ok := fs.used[list[i].Ref]; ok {
+			list[i].Used = &used
+		}
+	}
+	return list
+}
+
+// Delete a name or a file like the backend, forgetting the file's last use.
+func (fs *FileStorage) Delete(nameOrRef string) (removed string, err error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	removed, err = fs.AbstractFileStorage.Delete(nameOrRef)
+	delete(fs.used, removed)
+	return removed, err
+}
+
+// Put inserts a file like Insert but enforces the quota afterwards, because the
+// size is only known after streaming. Unreferenced files are evicted to make
+// room; their refs are returned to notify the Providers. If the file still
+// doesn't fit, the upload is undone and ErrQuotaExceeded returned. Uploads of
+// files which are stored already take no room and are never rejected.
+func (fs *FileStorage) Put(name, media, uploader string, r io.Reader) (info *FileInfo, evicted []string, err error) {
+	if fs.Quota.Total <= 0 && fs.Quota.Uploader <= 0 {
+		info, err = fs.AbstractFileStorage.Insert(name, media, uploader, r)
+		return info, nil, err
+	}
+
+	// count the upload in flight before looking at the existing files, so a
+	// concurrent upload of the same file is settled before it is removed
+	fs.mutex.Lock()
+	fs.uploading++
+	fs.mutex.Unlock()
+
+	// remember the existing files and the name's target, to only undo this upload
+	existing := make(map[string]bool)
+	for _, info := range fs.AbstractFileStorage.List() {
+		existing[info.Ref] = true
+	}
+	previous := ""
+	if name != "" {
+		if stat := fs.AbstractFileStorage.Stat(name); stat != nil {
+			previous = stat.Ref
+		}
+	}
+
+	info, err = fs.AbstractFileStorage.Insert(name, media, uploader, r)
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	defer fs.settle()
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing[info.Ref] {
+		fs.uploaded[info.Ref] = true
+		return info, nil, nil
+	}
+	if fs.AbstractFileStorage.Stat(info.Ref) == nil {
+		// evicted for another upload before it was counted here
+		return nil, nil, ErrQuotaExceeded
+	}
+	if evicted, err = fs.reserve(info.Ref, uploader); err != nil {
+		fs.undo(name, previous, info.Ref)
+		return nil, nil, err
+	}
+	fs.uploaded[info.Ref] = true
+	return info, evicted, nil
+}
+
+// undo a rejected upload of a new file: the name points to its previous file
+// again and the file is removed once no other upload is in flight, unless any
+// accepted it or it was referenced meanwhile; must be called with the mutex held
+func (fs *FileStorage) undo(name, previous, ref string) {
+	if name != "" && previous != "" {
+		fs.AbstractFileStorage.Alias(name, previous)
+	} else if name != "" {
+		fs.AbstractFileStorage.Delete(name)
+	}
+	if _, ok := fs.uploaded[ref]; !ok {
+		fs.uploaded[ref] = false
+	}
+}
+
+// settle an upload and remove the rejected files when it was the last one in
+// flight; must be called with the mutex held
+func (fs *FileStorage) settle() {
+	if fs.uploading--; fs.uploading > 0 {
+		return
+	}
+	for ref, accepted := range fs.uploaded {
+		if stat := fs.AbstractFileStorage.Stat(ref); !accepted && stat != nil && len(stat.Names) == 0 && fs.pinned[ref] == 0 {
+			fs.AbstractFileStorage.Delete(ref)
+			delete(fs.used, ref)
+		}
+	}
+	clear(fs.uploaded)
+}
+
+// reserve room for a newly inserted file, evicting other unreferenced files
+// if necessary; nothing is evicted if it wouldn't fit anyway
+func (fs *FileStorage) reserve(ref, uploader string) (evicted []string, err error) {
+	if fs.Quota.Total <= 0 && fs.Quota.Uploader <= 0 {
+		return nil, nil
+	}
+
+	// sum up the current usage, including the new file
+	list := fs.AbstractFileStorage.List()
+	var total, own int64
+	for _, info := range list {
+		total += info.Size
+		if info.Uploader == uploader {
+			own += info.Size
+		}
+	}
+	overTotal := func() bool { return fs.Quota.Total > 0 && total > fs.Quota.Total }
+	overOwn := func() bool { return fs.Quota.Uploader > 0 && own > fs.Quota.Uploader }
+
+	// select victims until the new file fits
+	victims, candidates := []string{}, fs.unreferenced(list)
+	if fs.shared {
+		candidates = nil
+	}
+	for _, info := range candidates {
+		if !overTotal() && !overOwn() {
+			break
+		}
+		// another uploader's files don't help with the own quota
+		if info.Ref == ref || !overTotal() && info.Uploader != uploader {
+			continue
+		}
+		victims = append(victims, info.Ref)
+		total -= info.Size
+		if info.Uploader == uploader {
+			own -= info.Size
+		}
+	}
+	if overTotal() || overOwn() {
+		return nil, ErrQuotaExceeded
+	}
+
+	for _, victim := range victims {
+		if removed, err := fs.AbstractFileStorage.Delete(victim); err == nil && removed != "" {
+			delete(fs.used, victim)
+			evicted = append(evicted, victim)
+		}
+	}
+	return evicted, nil
+}
+
+// Collect removes unreferenced files, which were not used within the TTL, and
+// returns their refs to notify the Providers. Files without an upload time,
+// which were inserted before those were recorded, are kept.
+func (fs *FileStorage) Collect(now time.Time) (removed []string) {
+	if fs.TTL <= 0 || fs.shared {
+		return nil
+	}
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	for _, info := range fs.unreferenced(fs.AbstractFileStorage.List()) {
+		if now.Sub(fs.lastUsed(info)) < fs.TTL {
+			break // sorted, so all others are more recent
+		}
+		if info.Uploaded.IsZero() {
+			continue
+		}
+		if ref, err := fs.AbstractFileStorage.Delete(info.Ref); err == nil && ref != "" {
+			delete(fs.used, ref)
+			removed = append(removed, ref)
+		}
+	}
+	return removed
+}
+
+// unreferenced files without names, jobs or uploads in flight, least-recently
+// used first; must be called with the mutex held
+func (fs *FileStorage) unreferenced(list []FileInfo) (files []FileInfo) {
+	for _, info := range list {
+		if _, settling := fs.uploaded[info.Ref]; settling {
+			continue // an upload in flight may still report it
+		}
+		if len(info.Names) == 0 && fs.pinned[info.Ref] == 0 {
+			files = append(files, info)
+		}
+	}
+	slices.SortFunc(files, func(a, b FileInfo) int {
+		if c := fs.lastUsed(a).Compare(fs.lastUsed(b)); c != 0 {
+			return c
+		}
+		return a.Uploaded.Compare(b.Uploaded)
+	})
+	return files
+}
+
+// lastUsed is the last use by a task or the upload time, whichever is later.
+// Uses are only known since startup, so files count as used at startup at
+// least; must be called with the mutex held
+func (fs *FileStorage) lastUsed(info FileInfo) time.Time {
+	last := fs.started
+	if info.Uploaded.After(last) {
+		last = info.Uploaded
+	}
+	if used, ok := fs.used[info.Ref]; ok && used.After(last) {
+		last = used
+	}
+	return last
+}
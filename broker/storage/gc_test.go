@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// insert a file directly into the backend of a test storage
+func testInsert(t *testing.T, fs *FileStorage, name, uploader, contents string) string {
+	t.Helper()
+	info, err := fs.AbstractFileStorage.Insert(name, "application/wasm", uploader, bytes.NewReader([]byte(contents)))
+	if err != nil {
+		t.Fatalf("Insert: %s", err)
+	}
+	return info.Ref
+}
+
+func TestPut(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		quota    Quota
+		setup    func(t *testing.T, fs *FileStorage) []string // returns refs for the checks
+		put      [3]string                                    // name, uploader and contents
+		err      error
+		evicted  []int  // indexes into the refs of setup
+		resolves string // the name resolves to this content afterwards, if not empty
+	}{
+		{
+			name:  "fits without quota",
+			quota: Quota{},
+			setup: func(t *testing.T, fs *FileStorage) []string { return nil },
+			put:   [3]string{"a", "alice", "123456"},
+		},
+		{
+			name:  "evicts unreferenced files",
+			quota: Quota{Total: 10},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				return []string{testInsert(t, fs, "", "bob", "old456")}
+			},
+			put:     [3]string{"a", "alice", "new456"},
+			evicted: []int{0},
+		},
+		{
+			name:  "never evicts named files",
+			quota: Quota{Total: 10},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				return []string{testInsert(t, fs, "old", "bob", "old456")}
+			},
+			put: [3]string{"b", "alice", "new456"},
+			err: ErrQuotaExceeded,
+		},
+		{
+			name:  "never evicts pinned files",
+			quota: Quota{Total: 10},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				ref := testInsert(t, fs, "", "bob", "old456")
+				fs.pinned[ref]++
+				return []string{ref}
+			},
+			put: [3]string{"", "alice", "new456"},
+			err: ErrQuotaExceeded,
+		},
+		{
+			name:  "restores the name of a rejected upload",
+			quota: Quota{Total: 10},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				ref := testInsert(t, fs, "a", "bob", "old456")
+				fs.pinned[ref]++
+				return []string{ref}
+			},
+			put:      [3]string{"a", "alice", "new456"},
+			err:      ErrQuotaExceeded,
+			resolves: "old456",
+		},
+		{
+			name:  "keeps files which are stored already",
+			quota: Quota{Total: 10},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				testInsert(t, fs, "x", "bob", "same56")
+				return []string{testInsert(t, fs, "y", "bob", "other6")}
+			},
+			put:      [3]string{"a", "alice", "same56"},
+			resolves: "same56",
+		},
+		{
+			name:  "evicts own files for the uploader quota",
+			quota: Quota{Uploader: 10},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				return []string{
+					testInsert(t, fs, "", "bob", "bob456"),
+					testInsert(t, fs, "", "alice", "alice6"),
+				}
+			},
+			put:     [3]string{"a", "alice", "new456"},
+			evicted: []int{1},
+		},
+		{
+			name:  "rejects files larger than the uploader quota",
+			quota: Quota{Uploader: 4},
+			setup: func(t *testing.T, fs *FileStorage) []string {
+				return []string{testInsert(t, fs, "", "bob", "bob456")}
+			},
+			put: [3]string{"a", "alice", "new456"},
+			err: ErrQuotaExceeded,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewMemoryFileStorage()
+			refs := tt.setup(t, fs)
+			fs.Quota = tt.quota
+
+			name, uploader, contents := tt.put[0], tt.put[1], tt.put[2]
+			info, evicted, err := fs.Put(name, "application/wasm", uploader, bytes.NewReader([]byte(contents)))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Put: err = %v, expected %v", err, tt.err)
+			}
+			expected := []string{}
+			for _, i := range tt.evicted {
+				expected = append(expected, refs[i])
+			}
+			if !slices.Equal(evicted, expected) && len(evicted)+len(expected) > 0 {
+				t.Errorf("Put: evicted %v, expected %v", evicted, expected)
+			}
+			for _, ref := range evicted {
+				if fs.Stat(ref) != nil {
+					t.Errorf("evicted file %s still exists", ref)
+				}
+			}
+
+			if err == nil {
+				if fs.Stat(info.Ref) == nil {
+					t.Errorf("accepted file %s does not exist", info.Ref)
+				}
+			} else if stat := fs.Stat(testRef([]byte(contents))); stat != nil && !slices.Contains(refs, stat.Ref) {
+				t.Errorf("rejected file %s still exists", stat.Ref)
+			}
+			if tt.resolves != "" {
+				if stat := fs.Stat(name); stat == nil || stat.Ref != testRef([]byte(tt.resolves)) {
+					t.Errorf("name %q resolves to %+v, expected %q", name, stat, tt.resolves)
+				}
+			} else if err != nil && name != "" && fs.Stat(name) != nil {
+				t.Errorf("name %q of a rejected upload resolves", name)
+			}
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	fs := NewMemoryFileStorage()
+	fs.TTL = time.Hour
+	now := fs.started.Add(2 * time.Hour)
+
+	unreferenced := testInsert(t, fs, "", "", "unreferenced")
+	named := testInsert(t, fs, "named", "", "named")
+	pinned := testInsert(t, fs, "", "", "pinned")
+	fs.pinned[pinned]++
+	used := testInsert(t, fs, "", "", "used")
+	fs.used[used] = now.Add(-time.Minute)
+
+	removed := fs.Collect(now)
+	if !slices.Equal(removed, []string{unreferenced}) {
+		t.Errorf("Collect removed %v, expected only %s", removed, unreferenced)
+	}
+	for _, ref := range []string{named, pinned, used} {
+		if fs.Stat(ref) == nil {
+			t.Errorf("Collect removed %s", ref)
+		}
+	}
+
+	// files are kept before the TTL passed since startup and with a zero TTL
+	fs.pinned[pinned]--
+	if removed := fs.Collect(fs.started.Add(time.Minute)); len(removed) != 0 {
+		t.Errorf("Collect before the TTL removed %v", removed)
+	}
+	fs.TTL = 0
+	if removed := fs.Collect(now.Add(time.Hour)); len(removed) != 0 {
+		t.Errorf("Collect without a TTL removed %v", removed)
+	}
+}
@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	wasimoff "wasimoff/proto/v1"
+)
+
+// Tenants have their own namespace of names, while the files are shared by all of
+// them and deduplicated by their content address. The names of a tenant are kept
+// in the backend with a prefix, e.g. `alice/tsp.wasm`. Names without a prefix are
+// in the global namespace, which is visible to all tenants unless they shadow it.
+
+// Namespace is the view of the FileStorage for a single tenant.
+type Namespace struct {
+	fs     *FileStorage
+	tenant string // empty for the global namespace
+}
+
+// Namespace returns the view of a tenant; an empty tenant is the global namespace.
+func (fs *FileStorage) Namespace(tenant string) *Namespace {
+	return &Namespace{fs: fs, tenant: tenant}
+}
+
+// key returns the name in the backend for a name given by the tenant
+func (ns *Namespace) key(name string) (string, error) {
+	if strings.Contains(name, "/") {
+		return "", fmt.Errorf("name cannot contain a slash")
+	}
+	if ns.tenant == "" {
+		return name, nil
+	}
+	return ns.tenant + "/" + name, nil
+}
+
+// resolve a name or ref to the key in the backend, preferring the tenant's own
+// names over global ones; returns an empty string for names of other tenants
+func (ns *Namespace) resolve(nameOrRef string) string {
+	if ns.tenant == "" || IsRef(nameOrRef) {
+		return nameOrRef
+	}
+	if strings.Contains(nameOrRef, "/") {
+		return ""
+	}
+	if key := ns.tenant + "/" + nameOrRef; ns.fs.Stat(key) != nil {
+		return key
+	}
+	return nameOrRef
+}
+
+// visible reduces the names of a file to those the tenant can resolve, without
+// their prefix; returns nil if the info is nil
+func (ns *Namespace) visible(info *FileInfo) *FileInfo {
+	if info == nil || ns.tenant == "" {
+		return info
+	}
+	names := []string{}
+	for _, name := range info.Names {
+		if own, ok := strings.CutPrefix(name, ns.tenant+"/"); ok {
+			names = append(names, own)
+		} else if !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	info.Names = slices.Compact(names)
+	return info
+}
+
+// Stat describes a file by name or ref, or returns nil.
+func (ns *Namespace) Stat(nameOrRef string) *FileInfo {
+	key := ns.resolve(nameOrRef)
+	if key == "" {
+		return nil
+	}
+	return ns.visible(ns.fs.Stat(key))
+}
+
+// Open a file by name or ref for reading; it must be closed when done.
+func (ns *Namespace) Open(nameOrRef string) (io.ReadSeekCloser, *FileInfo, error) {
+	key := ns.resolve(nameOrRef)
+	if key == "" {
+		return nil, nil, ErrNotFound
+	}
+	file, info, err := ns.fs.Open(key)
+	return file, ns.visible(info), err
+}
+
+// List describes the files which the tenant uploaded or can resolve by name.
+func (ns *Namespace) List() []FileInfo {
+	list := ns.fs.List()
+	if ns.tenant == "" {
+		return list
+	}
+	visible := []FileInfo{}
+	for _, info := range list {
+		if len(ns.visible(&info).Names) > 0 || info.Uploader == ns.tenant {
+			visible = append(visible, info)
+		}
+	}
+	return visible
+}
+
+// Put inserts a file with an optional name in the tenant's namespace, see
+// FileStorage.Put for the quota.
+func (ns *Namespace) Put(name, media, uploader string, r io.Reader) (info *FileInfo, evicted []string, err error) {
+	if name != "" {
+		if name, err = ns.key(name); err != nil {
+			return nil, nil, err
+		}
+	}
+	info, evicted, err = ns.fs.Put(name, media, uploader, r)
+	return ns.visible(info), evicted, err
+}
+
+// Alias adds a name in the tenant's namespace for a file it can resolve.
+func (ns *Namespace) Alias(name, nameOrRef string) (ref string, err error) {
+	if name, err = ns.key(name); err != nil {
+		return "", err
+	}
+	target := ns.resolve(nameOrRef)
+	if target == "" {
+		return "", ErrNotFound
+	}
+	return ns.fs.Alias(name, target)
+}
+
+// Delete removes a name from the tenant's namespace or, when given a ref, all of
+// the tenant's names for that file. The file itself is only removed if no other
+// names remain and the tenant uploaded it. Returns the ref of a removed file.
+func (ns *Namespace) Delete(nameOrRef string) (removed string, err error) {
+	if ns.tenant == "" {
+		return ns.fs.Delete(nameOrRef)
+	}
+	if !IsRef(nameOrRef) {
+		key, err := ns.key(nameOrRef)
+		if err != nil {
+			return "", ErrNotFound
+		}
+		return ns.fs.Delete(key)
+	}
+	info := ns.fs.Stat(nameOrRef)
+	if info == nil {
+		return "", ErrNotFound
+	}
+	remaining, found := 0, false
+	for _, name := range info.Names {
+		if !strings.HasPrefix(name, ns.tenant+"/") {
+			remaining++
+			continue
+		}
+		if _, err := ns.fs.Delete(name); err != nil && !errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+		found = true
+	}
+	if remaining == 0 && info.Uploader == ns.tenant {
+		return ns.fs.Delete(nameOrRef)
+	}
+	if !found {
+		return "", ErrNotFound
+	}
+	return "", nil
+}
+
+// ResolvePbFile checks if this file is usable as an argument in offloading
+// requests, i.e. if it either contains a blob or is a known file in the
+// storage. If so, set the resolved Ref on the file.
+func (ns *Namespace) ResolvePbFile(pbf *wasimoff.File) error {
+
+	// argument is nil, no need to do anything
+	if pbf == nil {
+		return nil
+	}
+
+	// trivial errors when both are nil or both are given
+	if pbf.Blob == nil && pbf.Ref == nil {
+		return fmt.Errorf("both Blob and Ref are nil")
+	}
+	if pbf.Blob != nil && pbf.Ref != nil {
+		return fmt.Errorf("don't use both Blob and Ref together")
+	}
+
+	// Blob is given directly, ok ...
+	if pbf.Blob != nil {
+		// check the media type, if given
+		if mt := pbf.GetMedia(); mt != "" {
+			mt, err := CheckMediaType(mt)
+			if err != nil {
+				return fmt.Errorf("invalid Media type")
+			}
+			pbf.Media = &mt
+		}
+		return nil
+	}
+
+	// Ref is given, look it up in Storage
+	if info := ns.Stat(*pbf.Ref); info != nil {
+		pbf.Media = &info.Media
+		pbf.Ref = &info.Ref
+		ns.fs.touch(info.Ref)
+		return nil
+	}
+
+	// couldn't resolve the file
+	return fmt.Errorf("Ref not found in storage")
+
+}
+
+// ResolveTaskFiles resolves all the files of a task request, see ResolvePbFile.
+func (ns *Namespace) ResolveTaskFiles(request *wasimoff.Task_Request) error {
+	// collect errors for all tried files
+	errs := []error{}
+
+	switch p := request.Parameters.(type) {
+
+	case *wasimoff.Task_Request_Wasip1:
+		errs = append(errs, ns.ResolvePbFile(p.Wasip1.Binary))
+		errs = append(errs, ns.ResolvePbFile(p.Wasip1.Rootfs))
+
+	case *wasimoff.Task_Request_Pyodide:
+		errs = append(errs, ns.ResolvePbFile(p.Pyodide.Rootfs))
+
+	}
+
+	// will be nil if there are no errs
+	return errors.Join(errs...)
+}
+
+// Make the Namespace a http.Handler, so it can serve files on web requests.
+// Expects a path value '{filename}' to retrieve the correct file.
+func (ns *Namespace) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	// get the filename from path pattern
+	filename := r.PathValue("filename")
+	if filename == "" {
+		http.Error(w, "path pattern not found", http.StatusInternalServerError)
+		return
+	}
+
+	// redirect to the backend directly, if possible
+	if presigner, ok := ns.fs.AbstractFileStorage.(Presigner); ok && ns.fs.Redirect > 0 {
+		info := ns.Stat(filename)
+		if info == nil {
+			http.Error(w, "File not Found in storage", http.StatusNotFound)
+			return
+		}
+		location, err := presigner.Presign(info.Ref, ns.fs.Redirect)
+		if err != nil {
+			http.Error(w, "presigning url failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("x-wasimoff-ref", info.Ref)
+		http.Redirect(w, r, location.String(), http.StatusTemporaryRedirect)
+		return
+	}
+
+	// open the file from storage
+	file, info, err := ns.Open(filename)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "File not Found in storage", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "opening file failed", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	// put known content-type in a header and serve the file
+	w.Header().Add("content-type", info.Media)
+	w.Header().Add("x-wasimoff-ref", info.Ref)
+	modtime := info.Uploaded
+	if modtime.IsZero() {
+		modtime = zerotime
+	}
+	http.ServeContent(w, r, "", modtime, file)
+
+}
@@ -0,0 +1,260 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/xid"
+)
+
+// s3Fake is a minimal in-memory S3 server with a single bucket, so the S3 backend
+// can be tried without an object storage. It implements just what the backend
+// needs, with path-style addressing and without authentication:
+//
+//	HEAD   /bucket                      bucket exists
+//	GET    /bucket?list-type=2&prefix=  list objects
+//	HEAD   /bucket/key                  stat object
+//	GET    /bucket/key                  get object, also with a range
+//	PUT    /bucket/key                  put or copy object
+//	DELETE /bucket/key                  remove object
+//	POST   /bucket/key?uploads          and the other multipart upload requests
+type s3Fake struct {
+	bucket  string
+	mutex   sync.Mutex
+	objects map[string]*s3FakeObject
+	uploads map[string]map[int][]byte // multipart uploads by id, parts by number
+	pending map[string]*s3FakeObject  // metadata of multipart uploads by id
+}
+
+type s3FakeObject struct {
+	blob     []byte
+	media    string
+	meta     http.Header // only X-Amz-Meta-* headers
+	modified time.Time
+	etag     string
+}
+
+func newS3Fake(bucket string) *s3Fake {
+	return &s3Fake{
+		bucket:  bucket,
+		objects: make(map[string]*s3FakeObject),
+		uploads: make(map[string]map[int][]byte),
+		pending: make(map[string]*s3FakeObject),
+	}
+}
+
+func (s *s3Fake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		s3FakeError(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	query := r.URL.Query()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	switch {
+	case key == "" && r.Method == http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+	case key == "" && r.Method == http.MethodGet:
+		s.list(w, query.Get("prefix"))
+	case key == "":
+		s3FakeError(w, http.StatusNotImplemented, "NotImplemented")
+
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		id := xid.New().String()
+		s.uploads[id] = make(map[int][]byte)
+		s.pending[id] = &s3FakeObject{media: r.Header.Get("Content-Type"), meta: s3FakeMeta(r.Header)}
+		s3FakeXML(w, struct {
+			XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+			Bucket   string
+			Key      string
+			UploadId string
+		}{Bucket: bucket, Key: key, UploadId: id})
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		s.putPart(w, r, query.Get("uploadId"), query.Get("partNumber"))
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		s.completeUpload(w, key, query.Get("uploadId"))
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		delete(s.uploads, query.Get("uploadId"))
+		delete(s.pending, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		s.copy(w, key, r.Header.Get("X-Amz-Copy-Source"))
+	case r.Method == http.MethodPut:
+		blob, err := io.ReadAll(r.Body)
+		if err != nil {
+			s3FakeError(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		object := s.put(key, &s3FakeObject{blob: blob, media: r.Header.Get("Content-Type"), meta: s3FakeMeta(r.Header)})
+		w.Header().Set("ETag", object.etag)
+		w.WriteHeader(http.StatusOK)
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		object, ok := s.objects[key]
+		if !ok {
+			s3FakeError(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		for k, v := range object.meta {
+			w.Header()[k] = v
+		}
+		w.Header().Set("Content-Type", object.media)
+		w.Header().Set("ETag", object.etag)
+		http.ServeContent(w, r, "", object.modified, bytes.NewReader(object.blob))
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		s3FakeError(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+// put an object with a new modification time and etag
+func (s *s3Fake) put(key string, object *s3FakeObject) *s3FakeObject {
+	sum := md5.Sum(object.blob)
+	object.etag = `"` + hex.EncodeToString(sum[:]) + `"`
+	object.modified = time.Now().UTC().Truncate(time.Second)
+	if object.media == "" {
+		object.media = "application/octet-stream"
+	}
+	s.objects[key] = object
+	return object
+}
+
+// copy an object including its metadata
+func (s *s3Fake) copy(w http.ResponseWriter, key, source string) {
+	_, sourceKey, _ := strings.Cut(strings.TrimPrefix(source, "/"), "/")
+	if unescaped, err := url.PathUnescape(sourceKey); err == nil {
+		sourceKey = unescaped
+	}
+	original, ok := s.objects[sourceKey]
+	if !ok {
+		s3FakeError(w, http.StatusNotFound, "NoSuchKey")
+		return
+	}
+	object := s.put(key, &s3FakeObject{blob: original.blob, media: original.media, meta: original.meta})
+	s3FakeXML(w, struct {
+		XMLName      xml.Name `xml:"CopyObjectResult"`
+		LastModified string
+		ETag         string
+	}{LastModified: object.modified.Format(time.RFC3339), ETag: object.etag})
+}
+
+// putPart stores a part of a multipart upload
+func (s *s3Fake) putPart(w http.ResponseWriter, r *http.Request, id, number string) {
+	parts, ok := s.uploads[id]
+	n, err := strconv.Atoi(number)
+	if !ok || err != nil {
+		s3FakeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	blob, err := io.ReadAll(r.Body)
+	if err != nil {
+		s3FakeError(w, http.StatusBadRequest, "IncompleteBody")
+		return
+	}
+	parts[n] = blob
+	sum := md5.Sum(blob)
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// completeUpload concatenates all parts of a multipart upload in order
+func (s *s3Fake) completeUpload(w http.ResponseWriter, key, id string) {
+	parts, ok := s.uploads[id]
+	if !ok {
+		s3FakeError(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	object := s.pending[id]
+	for _, n := range slices.Sorted(maps.Keys(parts)) {
+		object.blob = append(object.blob, parts[n]...)
+	}
+	delete(s.uploads, id)
+	delete(s.pending, id)
+	s.put(key, object)
+	s3FakeXML(w, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string
+		Key     string
+		ETag    string
+	}{Bucket: s.bucket, Key: key, ETag: object.etag})
+}
+
+// list all objects below a prefix in a single page
+func (s *s3Fake) list(w http.ResponseWriter, prefix string) {
+	type content struct {
+		Key          string
+		LastModified string
+		ETag         string
+		Size         int64
+		StorageClass string
+	}
+	result := struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		KeyCount    int
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []content
+	}{Name: s.bucket, Prefix: prefix, MaxKeys: 1000}
+	for key, object := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, content{
+				Key:          key,
+				LastModified: object.modified.Format(time.RFC3339),
+				ETag:         object.etag,
+				Size:         int64(len(object.blob)),
+				StorageClass: "STANDARD",
+			})
+		}
+	}
+	slices.SortFunc(result.Contents, func(a, b content) int { return strings.Compare(a.Key, b.Key) })
+	result.KeyCount = len(result.Contents)
+	s3FakeXML(w, result)
+}
+
+// s3FakeMeta copies the user metadata headers of a request
+func s3FakeMeta(header http.Header) http.Header {
+	meta := make(http.Header)
+	for k, v := range header {
+		if k = textproto.CanonicalMIMEHeaderKey(k); strings.HasPrefix(k, "X-Amz-Meta-") {
+			meta[k] = v
+		}
+	}
+	return meta
+}
+
+func s3FakeXML(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, xml.Header)
+	xml.NewEncoder(w).Encode(body)
+}
+
+func s3FakeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	fmt.Fprint(w, xml.Header)
+	xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+	}{Code: code})
+}
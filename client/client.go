@@ -9,6 +9,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -25,6 +26,7 @@ var (
 	verbose   = false                   // be more verbose
 	readstdin = false                   // read stdin for exec
 	websock   = false                   // use websocket to send tasks
+	token     = ""                      // api token for authentication
 )
 
 func init() {
@@ -32,6 +34,10 @@ func init() {
 	if url, ok := os.LookupEnv("BROKER"); ok {
 		brokerUrl = strings.TrimRight(url, "/")
 	}
+	// get the API token from env
+	if t, ok := os.LookupEnv("TOKEN"); ok {
+		token = t
+	}
 }
 
 func main() {
@@ -45,8 +51,18 @@ func main() {
 	flag.BoolVar(&verbose, "verbose", verbose, "Be more verbose and print raw messages for -exec")
 	flag.BoolVar(&readstdin, "stdin", readstdin, "Read and send stdin when using -exec (not streamed)")
 	flag.BoolVar(&websock, "ws", websock, "Use a WebSocket to send tasks")
+	flag.StringVar(&token, "token", token, "API token to authenticate with the Broker")
 	flag.Parse()
 
+	// authenticate all requests to the Broker, including websockets
+	if token != "" {
+		u, err := url.Parse(brokerUrl)
+		if err != nil {
+			log.Fatalf("-broker is invalid: %s", err)
+		}
+		http.DefaultClient.Transport = &transport.BearerTransport{Token: token, Host: u.Host}
+	}
+
 	switch true {
 
 	// upload a file, optionally take another argument as name alias
@@ -80,14 +96,19 @@ func main() {
 // upload a local file to the Broker
 func UploadFile(filename, name string) {
 
-	// read the file
-	buf, err := os.ReadFile(filename)
+	// open the file and detect the mediatype from its header
+	file, err := os.Open(filename)
 	if err != nil {
 		log.Fatal("reading file: ", err)
 	}
-
-	// detect the mediatype from buf
-	mt := mimetype.Detect(buf)
+	defer file.Close()
+	mt, err := mimetype.DetectReader(file)
+	if err != nil {
+		log.Fatal("reading file: ", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		log.Fatal("reading file: ", err)
+	}
 
 	// reuse basename as name if it's empty
 	if name == "" {
@@ -96,7 +117,7 @@ func UploadFile(filename, name string) {
 
 	// upload to the broker
 	resp, err := http.Post(
-		brokerUrl+"/api/storage/upload?name="+name, mt.String(), bytes.NewBuffer(buf))
+		brokerUrl+"/api/storage/upload?name="+name, mt.String(), file)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -180,34 +201,42 @@ func RunJsonFile(config string) {
 		log.Fatal("unmarshal job: ", err)
 	}
 
-	// run the job
-	results := RunJob(job)
+	// on a websocket, results are printed as soon as they arrive
+	if websock {
+		RunJobOnWebSocket(job, PrintTaskResult)
+		return
+	}
 
-	// print all task results
-	for i, task := range results {
-		if task.GetError() != "" {
-			fmt.Fprintf(os.Stderr, "[task %d FAIL] %s\n", i, task.GetError())
-		} else {
-			r := task.GetOk()
-			fmt.Fprintf(os.Stderr, "[task %d => exit:%d]\n", i, *r.Status)
-			if r.Artifacts != nil {
-				fmt.Fprintf(os.Stderr, "artifact: %s\n", base64.StdEncoding.EncodeToString(r.Artifacts.GetBlob()))
-			}
-			if len(r.GetStderr()) != 0 {
-				fmt.Fprintf(os.Stderr, "\033[31m%s\033[0m\n", string(r.GetStderr()))
-			}
-			fmt.Fprintln(os.Stdout, string(r.GetStdout()))
-		}
+	// run the job and print all task results
+	for i, task := range RunJob(job) {
+		PrintTaskResult(i, task)
 	}
 
 }
 
+// print the result of a single task of a job
+func PrintTaskResult(i int, task *wasimoff.Task_Wasip1_Result) {
+	if task.GetError() != "" {
+		fmt.Fprintf(os.Stderr, "[task %d FAIL] %s\n", i, task.GetError())
+	} else {
+		r := task.GetOk()
+		fmt.Fprintf(os.Stderr, "[task %d => exit:%d]\n", i, r.GetStatus())
+		if r.Artifacts != nil {
+			fmt.Fprintf(os.Stderr, "artifact: %s\n", base64.StdEncoding.EncodeToString(r.Artifacts.GetBlob()))
+		}
+		if len(r.GetStderr()) != 0 {
+			fmt.Fprintf(os.Stderr, "\033[31m%s\033[0m\n", string(r.GetStderr()))
+		}
+		fmt.Fprintln(os.Stdout, string(r.GetStdout()))
+	}
+}
+
 // run a prepared job configuration from proto message
 func RunJob(job *wasimoff.Client_Job_Wasip1Request) []*wasimoff.Task_Wasip1_Result {
 
 	// short-circuit to alternative function, when we should be using websocket
 	if websock {
-		return RunJobOnWebSocket(job)
+		return RunJobOnWebSocket(job, nil)
 	}
 
 	// (re)marshal as binary
@@ -248,67 +277,82 @@ func RunJob(job *wasimoff.Client_Job_Wasip1Request) []*wasimoff.Task_Wasip1_Resu
 	return response.GetTasks()
 }
 
-// alternatively, run a job by sending each task over websocket
-func RunJobOnWebSocket(job *wasimoff.Client_Job_Wasip1Request) []*wasimoff.Task_Wasip1_Result {
+// alternatively, run a job over websocket, where the Broker streams each result
+// as an event as soon as it finishes; progress is called for each of them
+func RunJobOnWebSocket(job *wasimoff.Client_Job_Wasip1Request, progress func(int, *wasimoff.Task_Wasip1_Result)) []*wasimoff.Task_Wasip1_Result {
 
 	// open a websocket to the broker
 	socket, err := transport.DialWebSocketTransport(context.TODO(), brokerUrl+"/api/client/ws")
 	if err != nil {
-		log.Printf("ERR: opening websocket: %s", err)
+		log.Fatalf("opening websocket: %s", err)
 	}
-	// wrap it in a messenger for RPC
+	// wrap it in a messenger for RPC, which must not drop any result
 	messenger := transport.NewMessengerInterface(socket)
+	messenger.WaitForEvents()
 	defer messenger.Close(nil)
 
-	// chan and list to collect responses
-	ntasks := len(job.GetTasks())
-	done := make(chan *transport.PendingCall, ntasks)
-	responses := make([]*wasimoff.Task_Wasip1_Result, ntasks)
+	// submit the whole job, the response is a summary after all results
+	status := &wasimoff.Client_Job_Status{}
+	call := messenger.SendRequest(context.TODO(), job, status, nil)
+	results := make([]*wasimoff.Task_Wasip1_Result, len(job.GetTasks()))
 
-	// submit all tasks
-	for i, task := range job.GetTasks() {
-		task.InheritNil(job.Parent)
+	// store a streamed result at its index
+	receive := func(event proto.Message) {
+		result, ok := event.(*wasimoff.Client_Job_Wasip1TaskResult)
+		if !ok || int(result.GetIndex()) >= len(results) {
+			return
+		}
+		i := int(result.GetIndex())
 		if verbose {
-			log.Printf("websocket: submit task %d", i)
+			log.Printf("websocket: job %s: received result %d", result.GetJob(), i)
 		}
-
-		// store index in context
-		ctx := context.WithValue(context.TODO(), ctxJobIndex{}, i)
-
-		// assemble wrapped task and fire it off
-		tr := &wasimoff.Task_Request{
-			Parameters: &wasimoff.Task_Request_Wasip1{
-				Wasip1: task,
-			},
+		results[i] = result.GetResult()
+		if progress != nil {
+			progress(i, results[i])
 		}
-		messenger.SendRequest(ctx, tr, &wasimoff.Task_Response{}, done)
 	}
 
-	// wait for all responses
-	for ntasks > 0 {
-		call := <-done
-		ntasks -= 1
-		i := call.Context.Value(ctxJobIndex{}).(int)
-		if verbose {
-			log.Printf("websocket: received result %d: err=%v", i, call.Error)
-		}
+	for {
+		select {
+
+		case event, ok := <-messenger.Events():
+			if !ok {
+				log.Fatalf("websocket closed: %s", messenger.Err())
+			}
+			receive(event)
 
-		if call.Error != nil {
-			responses[i].Result = &wasimoff.Task_Wasip1_Result_Error{
-				Error: call.Error.Error(),
+		case <-call.Done:
+			if call.Error != nil {
+				log.Fatal("job failed: ", call.Error)
+			}
+			if status.GetError() != "" {
+				log.Fatal("job failed: ", status.GetError())
+			}
+			// all events were queued before the response, since we waited for them
+		drain:
+			for {
+				select {
+				case event := <-messenger.Events():
+					receive(event)
+				default:
+					break drain
+				}
 			}
-		} else {
-			if resp, ok := call.Response.(*wasimoff.Task_Response); ok {
-				responses[i].Result = resp.GetWasip1().Result
-			} else {
-				responses[i].Result = &wasimoff.Task_Wasip1_Result_Error{
-					Error: "failed to parse the response as pb.Task_Response",
+			for i := range results {
+				if results[i] == nil {
+					results[i] = &wasimoff.Task_Wasip1_Result{Result: &wasimoff.Task_Wasip1_Result_Error{
+						Error: "result was not received",
+					}}
 				}
 			}
+			if progress != nil {
+				fmt.Fprintf(os.Stderr, "[job %s: %d completed, %d failed]\n",
+					status.GetId(), status.GetCompleted(), status.GetFailed())
+			}
+			return results
+
 		}
 	}
-
-	return responses
 }
 
 // run a python script from file
@@ -360,9 +404,9 @@ func RunPythonScript(script string) {
 		if r.Pickle != nil {
 			fmt.Fprintf(os.Stderr, "\nresult pickle: %s\n", base64.StdEncoding.EncodeToString(r.GetPickle()))
 		}
+		if r.Artifacts != nil {
+			fmt.Fprintf(os.Stderr, "artifact: %s\n", base64.StdEncoding.EncodeToString(r.Artifacts.GetBlob()))
+		}
 	}
 
 }
-
-// typed key to store index in context
-type ctxJobIndex struct{}
@@ -19,15 +19,22 @@ set -eu
 #   }, { ... }]
 # }
 
+# Python jobs use the same structure with Pyodide parameters, e.g.
+# { "parent": { "packages": [ "numpy" ] }, "tasks": [{ "script": "..." }] }
+# and need to be run with FORMAT=pyodide.
+
 # set the URL to the broker here
 BROKER="${BROKER:-http://localhost:4080}"
 
+# optional api token for authentication
+TOKEN="${TOKEN:-}"
+
 # run a json configuration
 runjson() { # $1: run configuration
   # you can convert your old configs with:
   # $ jq '. as $t | { parent: { binary: { ref: $t.bin } }, tasks: $t.exec | map({ args: ([$t.bin] + .args), stdin: .stdin | @base64 }) }' config.json
   # upload run configuration and show the result 
-  curl --fail-with-body -kX POST -H "content-type: application/json" "$BROKER/api/client/run" --data-binary "@$1"
+  curl --fail-with-body ${TOKEN:+-H "authorization: Bearer $TOKEN"} -kX POST -H "content-type: application/json" "$BROKER/api/client/run?format=${FORMAT:-wasip1}" --data-binary "@$1"
 }
 
 # create a run config from arguments
@@ -58,7 +65,22 @@ upload() { # $1: the file to upload, $2: the filename
   # get the mime-type
   mime=$(file -bL --mime-type "$1")
   # upload the file, giving name in query parameter
-  curl --fail-with-body -kX POST -H "content-type: $mime" "$BROKER/api/storage/upload?name=$name" --data-binary "@$1"
+  curl --fail-with-body ${TOKEN:+-H "authorization: Bearer $TOKEN"} -kX POST -H "content-type: $mime" "$BROKER/api/storage/upload?name=$name" --data-binary "@$1"
+}
+
+# list all files in the broker's storage
+files() {
+  curl --fail-with-body ${TOKEN:+-H "authorization: Bearer $TOKEN"} -k "$BROKER/api/storage"
+}
+
+# add another name for a file, given by name or ref
+addalias() { # $1: existing name or ref, $2: new name
+  curl --fail-with-body ${TOKEN:+-H "authorization: Bearer $TOKEN"} -kX POST "$BROKER/api/storage/alias?file=$1&name=$2"
+}
+
+# delete a name or, given a ref, the file with all its names
+delete() { # $1: name or ref
+  curl --fail-with-body ${TOKEN:+-H "authorization: Bearer $TOKEN"} -kX DELETE "$BROKER/api/storage/$1"
 }
 
 
@@ -66,12 +88,15 @@ upload() { # $1: the file to upload, $2: the filename
 case "${1:-}" in
 
   upload) upload "${2:?filename required}" "${3:-}" ;;
+  files) files ;;
+  alias) addalias "${2:?file required}" "${3:?name required}" ;;
+  delete) delete "${2:?name or ref required}" ;;
   run) runjson "${2:?run configuration required}" ;;
   parse) parseresponse ;;
   exec) shift 1; execute "$@" ;;
 
   *)
-    echo >&2 "ERR: unknown command! { run <json>, exec <args>, parse, upload <file> [<name>] }"
+    echo >&2 "ERR: unknown command! { run <json>, exec <args>, parse, upload <file> [<name>], files, alias <file> <name>, delete <file> }"
     exit 1
   ;;
 
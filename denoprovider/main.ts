@@ -5,17 +5,18 @@ import { WasimoffProvider } from "@wasimoff/worker/provider.ts";
 
 // parse commandline arguments
 const help = (fatal: boolean = false) => {
-  console.log("$", import.meta.filename?.replace(/.*\//, ""), "[--workers n] [--url <Broker URL>]");
+  console.log("$", import.meta.filename?.replace(/.*\//, ""), "[--workers n] [--url <Broker URL>] [--token <API token>]");
   Deno.exit(fatal ? 1 : 0);
 };
 const args = parseArgs(Deno.args, {
-  alias: { "workers": "w", "url": "u", "help": "h" },
+  alias: { "workers": "w", "url": "u", "token": "t", "help": "h" },
   default: {
     "workers": navigator.hardwareConcurrency,
     "url": "http://localhost:4080",
+    "token": Deno.env.get("TOKEN") ?? "",
   },
   boolean: [ "help" ],
-  string: [ "url" ],
+  string: [ "url", "token" ],
   unknown: (arg) => { console.warn("Unknown argument:", arg); help(true); }
 });
 
@@ -23,28 +24,44 @@ const args = parseArgs(Deno.args, {
 if (args.help) help();
 
 // validate the values
-const brokerurl = args.url;
-if (!/^https?:\/\//.test(brokerurl)) throw "--url must be a HTTP(S) origin (http?://)";
+const brokerurl = new URL(args.url);
+if (!/^https?:$/.test(brokerurl.protocol)) throw "--url must be a HTTP(S) origin (http?://)";
+if (args.token) brokerurl.searchParams.set("token", args.token);
 const nproc = Math.floor(Number(args.workers));
 if (Number.isNaN(nproc) || nproc < 1) throw "--workers must be a positive number";
 
 // initialize the provider
 console.log("%c[Wasimoff]", "color: red;", "starting Provider in Deno ...");
-const provider = await WasimoffProvider.init(nproc, brokerurl, ":memory:");
+const provider = await WasimoffProvider.init(nproc, brokerurl.href, ":memory:");
 const workers = await provider.pool.scale();
 await provider.sendInfo(workers, "deno", `${navigator.userAgent} (${Deno.build.target})`);
 
-// log received messages
-(async () => {
-  for await (const event of provider.messenger!.events) {
-    const typename = event.$typeName;
-    delete event.$typeName;
-    console.log(`%c[${typename}]`, "color: green;", JSON.stringify(event));
-  };
-})();
+// handle requests until the connection is lost, then reconnect to resume the session
+while (true) {
+
+  // log received messages
+  (async () => {
+    for await (const event of provider.messenger!.events) {
+      const typename = event.$typeName;
+      delete event.$typeName;
+      console.log(`%c[${typename}]`, "color: green;", JSON.stringify(event));
+    };
+  })();
 
-// start handling requests
-await provider.handlerequests();
+  // start handling requests
+  await provider.handlerequests().catch(err => console.error("ERROR:", err));
+  console.error("ERROR: rpc loop exited, connection lost? reconnecting ...");
 
-console.error("ERROR: rpc loop exited, connection lost?");
-Deno.exit(1);
+  // retry until the broker is reachable again
+  while (true) {
+    await new Promise(r => setTimeout(r, 1000));
+    try {
+      await provider.connect(brokerurl.href);
+      await provider.sendInfo(workers, "deno", `${navigator.userAgent} (${Deno.build.target})`);
+      break;
+    } catch (err) {
+      console.error("ERROR: reconnecting failed:", err);
+    };
+  };
+
+};
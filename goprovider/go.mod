@@ -0,0 +1,8 @@
+module wasimoff/goprovider
+
+go 1.23
+
+require (
+	github.com/tetratelabs/wazero v1.9.0
+	google.golang.org/protobuf v1.36.4
+)
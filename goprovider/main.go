@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+	"wasimoff/broker/net/transport"
+)
+
+var (
+	brokerUrl = "http://localhost:4080" // default broker base URL
+	workers   = runtime.NumCPU()        // number of concurrent tasks
+	name      = "go"                    // logging-friendly name sent in hello
+	token     = ""                      // api token for authentication
+	certfile  = ""                      // client certificate for authentication
+	keyfile   = ""                      // key of the client certificate
+	cafile    = ""                      // ca to verify the broker's certificate
+	id        = ""                      // persistent id to resume the session
+)
+
+// wait this long before trying to reconnect
+const reconnectDelay = time.Second
+
+func init() {
+	// get the Broker URL from env
+	if url, ok := os.LookupEnv("BROKER"); ok {
+		brokerUrl = strings.TrimRight(url, "/")
+	}
+	// get the API token from env
+	if t, ok := os.LookupEnv("TOKEN"); ok {
+		token = t
+	}
+	// get a persistent id from env
+	if v, ok := os.LookupEnv("PROVIDER_ID"); ok {
+		id = v
+	}
+}
+
+func main() {
+
+	// commandline parser
+	flag.StringVar(&brokerUrl, "url", brokerUrl, "URL to the Broker to connect to")
+	flag.IntVar(&workers, "workers", workers, "Maximum number of concurrent tasks")
+	flag.StringVar(&name, "name", name, "Name of this Provider for logging purposes")
+	flag.StringVar(&token, "token", token, "API token to authenticate with the Broker")
+	flag.StringVar(&certfile, "cert", certfile, "Client certificate to authenticate with the Broker")
+	flag.StringVar(&keyfile, "key", keyfile, "Key of the client certificate")
+	flag.StringVar(&cafile, "ca", cafile, "CA certificates to verify the Broker with")
+	flag.StringVar(&id, "id", id, "Persistent id to resume the session after reconnects (default random)")
+	flag.Parse()
+
+	// validate the values
+	if !strings.HasPrefix(brokerUrl, "http://") && !strings.HasPrefix(brokerUrl, "https://") {
+		log.Fatal("-url must be a HTTP(S) origin (http?://)")
+	}
+	if workers < 1 {
+		log.Fatal("-workers must be a positive number")
+	}
+	brokerUrl = strings.TrimRight(brokerUrl, "/")
+	if id == "" {
+		id = randomID()
+	}
+
+	// present a client certificate and maybe trust a private ca
+	if certfile != "" || keyfile != "" || cafile != "" {
+		config, err := tlsConfig(certfile, keyfile, cafile)
+		if err != nil {
+			log.Fatalf("loading tls config: %s", err)
+		}
+		http.DefaultTransport.(*http.Transport).TLSClientConfig = config
+	}
+
+	// authenticate all requests to the Broker, including websockets
+	if token != "" {
+		u, err := url.Parse(brokerUrl)
+		if err != nil {
+			log.Fatalf("-url is invalid: %s", err)
+		}
+		http.DefaultClient.Transport = &transport.BearerTransport{Token: token, Host: u.Host}
+	}
+
+	// cancel everything on CTRL-C
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	// initialize the provider and connect to the broker
+	log.Printf("[Wasimoff] starting Provider in Go with %d workers ...", workers)
+	provider := NewProvider(ctx, brokerUrl, workers, id)
+	if err := provider.Connect(ctx); err != nil {
+		log.Fatalf("connecting to Broker: %s", err)
+	}
+	defer provider.Close(nil)
+
+	// send our resources and platform information
+	useragent := fmt.Sprintf("wazero (%s; %s/%s)", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if err := provider.SendInfo(ctx, name, useragent); err != nil {
+		log.Fatalf("sending info to Broker: %s", err)
+	}
+
+	for {
+		// start handling requests, this loops until the connection is lost
+		err := provider.HandleRequests(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("ERROR: rpc loop exited, connection lost? %s", err)
+
+		// reconnect to resume the session, running tasks continue meanwhile
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+			if err = provider.Connect(ctx); err == nil {
+				err = provider.SendInfo(ctx, name, useragent)
+			}
+			if err == nil {
+				log.Printf("reconnected to Broker")
+				break
+			}
+			log.Printf("ERROR: reconnecting failed: %s", err)
+		}
+	}
+
+}
+
+// randomID returns a random hex string to identify this process
+func randomID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalf("generating an id: %s", err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// tlsConfig loads an optional client keypair and an optional pool of CAs, which
+// replaces the system roots to verify the Broker
+func tlsConfig(certfile, keyfile, cafile string) (*tls.Config, error) {
+	config := &tls.Config{}
+	if certfile != "" || keyfile != "" {
+		keypair, err := tls.LoadX509KeyPair(certfile, keyfile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{keypair}
+	}
+	if cafile != "" {
+		pem, err := os.ReadFile(cafile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates in %q", cafile)
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"wasimoff/broker/net/transport"
+	wasimoff "wasimoff/proto/v1"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Provider is a native counterpart to the WasimoffProvider in the webprovider. It
+// connects to a Broker, handles incoming RPC requests and executes WASI preview1
+// modules in-process with wazero.
+type Provider struct {
+	messenger *transport.Messenger // connection to the broker
+	storage   *ProviderStorage     // files uploaded by or fetched from the broker
+	pool      *WorkerPool          // limited pool to execute tasks
+	id        string               // persistent id to resume the session
+
+	// results of tasks which are running or were not delivered yet, see runTaskOnce
+	results      map[string]*taskResult
+	resultsMutex sync.Mutex
+}
+
+// Setup a new Provider with an empty storage and a pool of workers.
+func NewProvider(ctx context.Context, broker string, workers int, id string) *Provider {
+	p := &Provider{
+		pool:    NewWorkerPool(ctx, workers),
+		id:      id,
+		results: make(map[string]*taskResult),
+	}
+	p.storage = NewProviderStorage(broker, p.downloadFile, p.sendFileSystemUpdate)
+	return p
+}
+
+// -------------------- connection -------------------- >>
+
+// Connect to the Broker's provider socket.
+func (p *Provider) Connect(ctx context.Context) error {
+
+	// close previous connections
+	if p.messenger != nil && p.messenger.Err() == nil {
+		p.messenger.Close(fmt.Errorf("reconnecting"))
+	}
+
+	// only the websocket transport is implemented so far
+	socket, err := transport.DialWebSocketTransport(ctx, p.storage.broker+"/api/provider/ws")
+	if err != nil {
+		return fmt.Errorf("opening websocket: %w", err)
+	}
+	p.messenger = transport.NewMessengerInterface(socket)
+	return nil
+
+}
+
+// Close the connection to the Broker.
+func (p *Provider) Close(reason error) {
+	if p.messenger != nil {
+		p.messenger.Close(reason)
+	}
+}
+
+// -------------------- events -------------------- >>
+
+// SendInfo sends the current concurrency along with a name and useragent.
+func (p *Provider) SendInfo(ctx context.Context, name, useragent string) error {
+	if p.messenger == nil {
+		return fmt.Errorf("not connected yet")
+	}
+	if err := p.messenger.SendEvent(ctx, &wasimoff.Event_ProviderResources{
+		Concurrency: proto.Uint32(uint32(p.pool.Capacity())),
+	}); err != nil {
+		return err
+	}
+	return p.messenger.SendEvent(ctx, &wasimoff.Event_ProviderHello{
+		Name:      &name,
+		Useragent: &useragent,
+		Id:        &p.id,
+	})
+}
+
+// notify the broker about files that were fetched on-demand
+func (p *Provider) sendFileSystemUpdate(added []string) {
+	if p.messenger == nil {
+		return
+	}
+	err := p.messenger.SendEvent(context.Background(), &wasimoff.Event_FileSystemUpdate{Added: added})
+	if err != nil {
+		log.Printf("WARN: failed to send FileSystemUpdate: %s", err)
+	}
+}
+
+// downloadChunkSize is the length of chunks to request; the Broker may send less
+const downloadChunkSize = 1 << 20
+
+// downloadFile requests a file from the Broker's storage over the messenger, in
+// chunks if the Broker supports it. Chunks are assembled by ref in the storage,
+// so a download that was interrupted by a reconnect continues where it stopped.
+func (p *Provider) downloadFile(name string) (*wasimoff.File, error) {
+	if p.messenger == nil {
+		return nil, fmt.Errorf("need to connect to a broker first")
+	}
+	file, offset := name, uint64(0)
+	for {
+		response := wasimoff.FileChunkDownloadResponse{}
+		request := wasimoff.FileChunkDownloadRequest{File: &file, Offset: &offset, Length: proto.Uint64(downloadChunkSize)}
+		if err := p.messenger.RequestSync(context.Background(), &request, &response); err != nil {
+			var remote transport.RemoteError
+			if errors.As(err, &remote) && offset == 0 {
+				return p.downloadWhole(name) // broker doesn't serve chunks
+			}
+			return nil, err
+		}
+		if response.GetErr() != "" {
+			return nil, fmt.Errorf("%w: %s", errNotInStorage, response.GetErr())
+		}
+		received, complete, err := p.storage.Assemble(response.GetChunk())
+		if err != nil {
+			return nil, err
+		}
+		if complete != nil {
+			return &wasimoff.File{Ref: proto.String(complete.Ref()), Media: &complete.Media, Blob: complete.Bytes}, nil
+		}
+		if received == offset && len(response.GetChunk().GetBlob()) == 0 {
+			return nil, fmt.Errorf("download stalled at offset %d", offset)
+		}
+		// continue by ref, in case the name is changed meanwhile
+		file, offset = response.GetChunk().GetRef(), received
+	}
+}
+
+// downloadWhole requests a file from the Broker's storage in a single message.
+func (p *Provider) downloadWhole(name string) (*wasimoff.File, error) {
+	response := wasimoff.FileDownloadResponse{}
+	request := wasimoff.FileDownloadRequest{File: &name}
+	if err := p.messenger.RequestSync(context.Background(), &request, &response); err != nil {
+		return nil, err
+	}
+	if response.GetErr() != "" {
+		return nil, fmt.Errorf("%w: %s", errNotInStorage, response.GetErr())
+	}
+	return response.GetDownload(), nil
+}
+
+// -------------------- requests -------------------- >>
+
+// HandleRequests starts handling RPC requests from the messenger. This will loop
+// until the messenger is closed, so you will be notified when the connection breaks.
+func (p *Provider) HandleRequests(ctx context.Context) error {
+	if p.messenger == nil {
+		return fmt.Errorf("need to connect to a broker first")
+	}
+
+	// dispatch requests to the registered handlers
+	p.registerHandlers()
+	go p.messenger.ServeRequests()
+
+	for {
+		select {
+
+		// connection closing
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.messenger.Closing():
+			return p.messenger.Err()
+
+		// log any received events, except the frequent throughput updates
+		case event, ok := <-p.messenger.Events():
+			if !ok { // messenger closing
+				return p.messenger.Err()
+			}
+			if _, ok := event.(*wasimoff.Event_Throughput); ok {
+				continue
+			}
+			js, _ := protojson.Marshal(event)
+			log.Printf("[%s] %s", event.ProtoReflect().Descriptor().Name(), js)
+
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+	"wasimoff/broker/net/transport"
+	"wasimoff/broker/storage"
+	wasimoff "wasimoff/proto/v1"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Register handlers for incoming RemoteProcedureCalls on the Messenger. Moved into a
+// separate file for better readability, just like the rpchandler.ts in the webprovider.
+func (p *Provider) registerHandlers() {
+
+	// execute a task; the pool limits concurrency itself
+	transport.HandleFunc(p.messenger, 0, p.runTaskOnce)
+
+	// cancel a running task
+	transport.HandleFunc(p.messenger, 0, func(ctx context.Context, r *wasimoff.Task_Cancel) (*wasimoff.Task_Cancel, error) {
+		if r.Id == nil {
+			return nil, fmt.Errorf("missing the task id to cancel")
+		}
+		log.Printf("cancelling task %q: %s", r.GetId(), r.GetReason())
+		p.pool.Cancel(r.GetId(), r.GetReason())
+		return r, nil // echo back
+	})
+
+	// list files in storage
+	transport.HandleFunc(p.messenger, 0, func(ctx context.Context, r *wasimoff.FileListingRequest) (*wasimoff.FileListingResponse, error) {
+		return &wasimoff.FileListingResponse{Files: p.storage.List()}, nil
+	})
+
+	// probe for a specific file in storage
+	transport.HandleFunc(p.messenger, 0, func(ctx context.Context, r *wasimoff.FileProbeRequest) (*wasimoff.FileProbeResponse, error) {
+		return &wasimoff.FileProbeResponse{Ok: proto.Bool(p.storage.Has(r.GetFile()))}, nil
+	})
+
+	// binaries uploaded from the broker inside an rpc
+	transport.HandleFunc(p.messenger, 0, func(ctx context.Context, r *wasimoff.FileUploadRequest) (*wasimoff.FileUploadResponse, error) {
+		if r.Upload == nil {
+			return nil, fmt.Errorf("empty upload")
+		}
+		// overwrite name with computed digest
+		file := storage.NewFile(r.Upload.GetMedia(), r.Upload.GetBlob())
+		if ref := r.Upload.GetRef(); storage.IsRef(ref) && ref != file.Ref() {
+			return &wasimoff.FileUploadResponse{Err: proto.String("digest does not match ref")}, nil
+		}
+		p.storage.Put(file)
+		return &wasimoff.FileUploadResponse{}, nil
+	})
+
+	// large files uploaded from the broker in chunks
+	transport.HandleFunc(p.messenger, 0, func(ctx context.Context, r *wasimoff.FileChunkUploadRequest) (*wasimoff.FileChunkUploadResponse, error) {
+		if r.Chunk == nil {
+			return nil, fmt.Errorf("empty chunk")
+		}
+		received, file, err := p.storage.Assemble(r.Chunk)
+		if err != nil {
+			return &wasimoff.FileChunkUploadResponse{Err: proto.String(err.Error())}, nil
+		}
+		if file != nil {
+			p.storage.Put(file)
+		}
+		return &wasimoff.FileChunkUploadResponse{Received: &received}, nil
+	})
+
+	// files deleted on the broker
+	transport.HandleFunc(p.messenger, 0, func(ctx context.Context, r *wasimoff.FileDeleteRequest) (*wasimoff.FileDeleteResponse, error) {
+		p.storage.Delete(r.GetFile())
+		p.pool.Evict(r.GetFile())
+		return &wasimoff.FileDeleteResponse{}, nil
+	})
+
+}
+
+// keep results which could not be delivered this long, so the broker can resume them
+const resultRetention = 5 * time.Minute
+
+// taskResult is the outcome of a task, which may be requested again after a reconnect
+type taskResult struct {
+	done     chan struct{}
+	response *wasimoff.Task_Response
+	err      error
+
+	// guarded by the resultsMutex
+	cancel  context.CancelFunc // stops the running task
+	waiting int                // requests waiting for the result
+	abandon *time.Timer        // cancels the task when nobody waited for it meanwhile
+}
+
+// runTaskOnce runs a task only once, even when the broker sends it again after a
+// reconnect. The task is not cancelled when the connection breaks and a request on
+// the new connection receives its result. If no request waits for it within the
+// resultRetention, the task is cancelled. Tasks are identified by their id and a
+// digest of the request, because a restarted broker counts its ids from the start.
+func (p *Provider) runTaskOnce(ctx context.Context, request *wasimoff.Task_Request) (*wasimoff.Task_Response, error) {
+	id := request.GetInfo().GetId()
+	buf, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if id == "" || err != nil {
+		return p.runTask(ctx, request)
+	}
+	digest := sha256.Sum256(buf)
+	key := id + "@" + hex.EncodeToString(digest[:])
+
+	// start the task or find the one which is already running
+	p.resultsMutex.Lock()
+	result, ok := p.results[key]
+	if !ok {
+		detached, cancel := detach(ctx, request)
+		result = &taskResult{done: make(chan struct{}), cancel: cancel}
+		p.results[key] = result
+		go func() {
+			defer cancel()
+			result.response, result.err = p.runTask(detached, request)
+			close(result.done)
+			time.AfterFunc(resultRetention, func() { p.forgetResult(key, result) })
+		}()
+	} else {
+		log.Printf("resuming task %q", id)
+	}
+	result.waiting++
+	if result.abandon != nil {
+		result.abandon.Stop()
+		result.abandon = nil
+	}
+	p.resultsMutex.Unlock()
+	defer p.leaveResult(result)
+
+	select {
+	case <-result.done:
+		if ctx.Err() == nil {
+			p.forgetResult(key, result) // delivered, unless the connection breaks right now
+		}
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detach derives the context of a task, which outlives the connection but is
+// still limited by the task's QoS timeout, if it has one
+func detach(ctx context.Context, request *wasimoff.Task_Request) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if timeout := request.GetQos().GetTimeout().AsDuration(); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// leaveResult is called when a request stops waiting for a result; the task is
+// cancelled when no other request waits for it within the resultRetention
+func (p *Provider) leaveResult(result *taskResult) {
+	p.resultsMutex.Lock()
+	defer p.resultsMutex.Unlock()
+	select {
+	case <-result.done:
+		result.waiting--
+	default:
+		if result.waiting--; result.waiting == 0 {
+			result.abandon = time.AfterFunc(resultRetention, result.cancel)
+		}
+	}
+}
+
+// forgetResult removes a result, unless the task was started again meanwhile
+func (p *Provider) forgetResult(key string, result *taskResult) {
+	p.resultsMutex.Lock()
+	defer p.resultsMutex.Unlock()
+	if p.results[key] == result {
+		delete(p.results, key)
+	}
+}
+
+// runTask executes a Task_Request in the pool and wraps the output in a Task_Response
+func (p *Provider) runTask(ctx context.Context, request *wasimoff.Task_Request) (*wasimoff.Task_Response, error) {
+
+	// deconstruct the request and check type
+	info, parameters := request.GetInfo(), request.GetParameters()
+	if info == nil || parameters == nil {
+		return nil, fmt.Errorf("info and parameters cannot be nil")
+	}
+
+	// inner switch by type
+	switch parameters := parameters.(type) {
+
+	case *wasimoff.Task_Request_Wasip1:
+		params := parameters.Wasip1
+		if params.Binary == nil {
+			return nil, fmt.Errorf("wasip1.binary cannot be nil")
+		}
+
+		// get the webassembly binary and rootfs archive
+		binary, err := p.storage.Resolve(params.Binary)
+		if err != nil {
+			return nil, fmt.Errorf("binary: %w", err)
+		}
+		task := &Wasip1Task{
+			Binary:    binary,
+			Args:      params.GetArgs(),
+			Envs:      params.GetEnvs(),
+			Stdin:     params.GetStdin(),
+			Artifacts: params.GetArtifacts(),
+		}
+		if params.Rootfs != nil {
+			if task.Rootfs, err = p.storage.Resolve(params.Rootfs); err != nil {
+				return nil, fmt.Errorf("rootfs: %w", err)
+			}
+		}
+
+		// execute the module in the pool and send back the result
+		output, err := p.pool.RunWasip1(ctx, info.GetId(), task)
+		if err != nil {
+			// format failures as Task_Response.Error
+			return &wasimoff.Task_Response{
+				Result: &wasimoff.Task_Response_Error{Error: err.Error()},
+			}, nil
+		}
+		return &wasimoff.Task_Response{
+			Result: &wasimoff.Task_Response_Wasip1{Wasip1: &wasimoff.Task_Wasip1_Result{
+				Result: &wasimoff.Task_Wasip1_Result_Ok{Ok: output},
+			}},
+		}, nil
+
+	case *wasimoff.Task_Request_Pyodide:
+		return nil, fmt.Errorf("pyodide tasks are not supported on this provider")
+
+	default:
+		return nil, fmt.Errorf("unknown task format")
+
+	}
+}
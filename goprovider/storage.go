@@ -0,0 +1,248 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+	"wasimoff/broker/storage"
+	wasimoff "wasimoff/proto/v1"
+)
+
+// ProviderStorage holds the files that were uploaded by the Broker in memory. Files
+// which are not found locally are fetched from the Broker's storage on demand.
+type ProviderStorage struct {
+	broker string // base origin for remote fetching
+
+	mutex    sync.RWMutex
+	files    map[string]*storage.File // files keyed by content address
+	lookup   map[string]string        // fetched names to content addresses
+	partials map[string]*partialFile  // incomplete chunked transfers by ref
+
+	// download files from the broker without http, if possible
+	download func(name string) (*wasimoff.File, error)
+	// notify about files added by fetching
+	updates func(added []string)
+}
+
+// Create a new empty storage, which fetches from the given Broker origin. Files
+// are downloaded with the given function first and only fetched with http, when
+// that fails for any other reason than errNotInStorage.
+func NewProviderStorage(broker string, download func(name string) (*wasimoff.File, error), updates func(added []string)) *ProviderStorage {
+	return &ProviderStorage{
+		broker:   broker,
+		files:    make(map[string]*storage.File),
+		lookup:   make(map[string]string),
+		partials: make(map[string]*partialFile),
+		download: download,
+		updates:  updates,
+	}
+}
+
+// List all files in this storage by their content address.
+func (s *ProviderStorage) List() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	files := make([]string, 0, len(s.files))
+	for ref := range s.files {
+		files = append(files, ref)
+	}
+	slices.Sort(files)
+	return files
+}
+
+// Has checks if a file is available locally, without fetching it.
+func (s *ProviderStorage) Has(nameOrRef string) bool {
+	return s.get(nameOrRef) != nil
+}
+
+// Put a file into storage, keyed by its content address.
+func (s *ProviderStorage) Put(file *storage.File) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.files[file.Ref()] = file
+}
+
+// Delete a file from storage along with any fetched names pointing to it.
+func (s *ProviderStorage) Delete(ref string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.files, ref)
+	for name, r := range s.lookup {
+		if r == ref {
+			delete(s.lookup, name)
+		}
+	}
+}
+
+// Resolve returns the file contents from a *pb.File, which can either carry
+// the blob directly or reference a file that is in storage or on the Broker.
+func (s *ProviderStorage) Resolve(pbf *wasimoff.File) (*storage.File, error) {
+	switch {
+
+	// blob is given directly
+	case len(pbf.GetBlob()) != 0:
+		return storage.NewFile(pbf.GetMedia(), pbf.GetBlob()), nil
+
+	// lookup locally or fetch from broker
+	case pbf.GetRef() != "":
+		if file := s.get(pbf.GetRef()); file != nil {
+			return file, nil
+		}
+		return s.fetch(pbf.GetRef())
+
+	default:
+		return nil, fmt.Errorf("neither blob nor ref were given")
+	}
+}
+
+// partialTimeout drops incomplete files, which received no chunk for this long
+const partialTimeout = 5 * time.Minute
+
+// partialFile is an incomplete file, which is assembled from chunks in order
+type partialFile struct {
+	media string
+	total uint64
+	blob  []byte
+	idle  *time.Timer // drops the partial file after the partialTimeout
+}
+
+// dropPartial removes a partial file, if it is still the current one for its ref;
+// must be called with the mutex held
+func (s *ProviderStorage) dropPartial(ref string, partial *partialFile) {
+	if s.partials[ref] == partial {
+		partial.idle.Stop()
+		delete(s.partials, ref)
+	}
+}
+
+// Assemble appends a chunk to the partial file with the same ref and returns the
+// number of bytes received so far. A chunk which doesn't continue at the end is
+// ignored, so the sender can resume at the returned offset. When the file is
+// complete, it is verified against its ref and returned but not stored yet.
+func (s *ProviderStorage) Assemble(chunk *wasimoff.FileChunk) (received uint64, file *storage.File, err error) {
+	ref, total := chunk.GetRef(), chunk.GetTotal()
+	if !storage.IsRef(ref) {
+		return 0, nil, fmt.Errorf("chunk needs a digest ref")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// nothing to do if the file is complete already
+	if file, ok := s.files[ref]; ok {
+		return uint64(len(file.Bytes)), file, nil
+	}
+	partial, ok := s.partials[ref]
+	if !ok || partial.total != total {
+		if ok {
+			s.dropPartial(ref, partial)
+		}
+		created := &partialFile{media: chunk.GetMedia(), total: total}
+		created.idle = time.AfterFunc(partialTimeout, func() {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.dropPartial(ref, created)
+		})
+		s.partials[ref], partial = created, created
+	}
+	partial.idle.Reset(partialTimeout)
+
+	// append the chunk if it's the next one
+	if blob := chunk.GetBlob(); len(blob) != 0 && chunk.GetOffset() == uint64(len(partial.blob)) {
+		if uint64(len(partial.blob)+len(blob)) > total {
+			s.dropPartial(ref, partial)
+			return 0, nil, fmt.Errorf("chunk exceeds the total size")
+		}
+		partial.blob = append(partial.blob, blob...)
+	}
+	received = uint64(len(partial.blob))
+	if received < total {
+		return received, nil, nil
+	}
+
+	// verify the complete file
+	s.dropPartial(ref, partial)
+	file = storage.NewFile(partial.media, partial.blob)
+	if file.Ref() != ref {
+		return 0, nil, fmt.Errorf("digest does not match ref")
+	}
+	return received, file, nil
+}
+
+// get a file from storage, either by ref or a previously fetched name
+func (s *ProviderStorage) get(nameOrRef string) *storage.File {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if file, ok := s.files[nameOrRef]; ok {
+		return file
+	}
+	if ref, ok := s.lookup[nameOrRef]; ok {
+		return s.files[ref]
+	}
+	return nil
+}
+
+// fetch a file from the broker's storage and insert it locally
+func (s *ProviderStorage) fetch(name string) (*storage.File, error) {
+
+	// request the file from broker, preferably over the messenger
+	log.Printf("file %s not found locally, fetch from broker", name)
+	var download *wasimoff.File
+	var err error
+	if s.download != nil {
+		download, err = s.download(name)
+		if err != nil && !errors.Is(err, errNotInStorage) {
+			log.Printf("WARN: download over messenger failed, fetch with http: %s", err)
+			download, err = s.httpGet(name)
+		}
+	} else {
+		download, err = s.httpGet(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching from broker: %w", err)
+	}
+
+	// store fetched file in storage
+	file := storage.NewFile(download.GetMedia(), download.GetBlob())
+	if ref := download.GetRef(); ref != "" && ref != file.Ref() {
+		return nil, fmt.Errorf("fetched file does not match its ref")
+	}
+	s.Put(file)
+	s.mutex.Lock()
+	s.lookup[name] = file.Ref()
+	s.mutex.Unlock()
+
+	// emit event for broker
+	if s.updates != nil {
+		s.updates([]string{file.Ref()})
+	}
+	return file, nil
+
+}
+
+// errNotInStorage is returned by downloads when the broker doesn't have a file
+var errNotInStorage = errors.New("file not found in broker storage")
+
+// httpGet fetches a file from the broker's storage over plain http
+func (s *ProviderStorage) httpGet(name string) (*wasimoff.File, error) {
+	response, err := http.Get(s.broker + "/api/storage/" + name)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound {
+		return nil, errNotInStorage
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http: %s", response.Status)
+	}
+	blob, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	media, ref := response.Header.Get("content-type"), response.Header.Get("x-wasimoff-ref")
+	return &wasimoff.File{Ref: &ref, Media: &media, Blob: blob}, nil
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"wasimoff/broker/storage"
+	wasimoff "wasimoff/proto/v1"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"google.golang.org/protobuf/proto"
+)
+
+// WorkerPool executes WebAssembly modules in a WASI preview1 environment with wazero.
+// There are no actual workers; the number of concurrent tasks is limited with tickets.
+type WorkerPool struct {
+	runtime wazero.Runtime
+
+	// use "tickets" to limit the number of concurrent tasks
+	tickets chan struct{}
+
+	// compiled modules, keyed by content address of the binary, and the order of
+	// their use to evict the least-recently used modules
+	modulesMutex sync.Mutex
+	modules      map[string]*compiledModule
+	modulesLRU   *list.List
+
+	// cancellation functions of running tasks, keyed by task id
+	runningMutex sync.Mutex
+	running      map[string]context.CancelCauseFunc
+}
+
+// Wasip1Task holds the resolved parameters for a single wasip1 execution.
+type Wasip1Task struct {
+	Binary    *storage.File // the WebAssembly executable
+	Args      []string      // commandline arguments
+	Envs      []string      // environment variables in KEY=value notation
+	Stdin     []byte        // put something on stdin, instead of an empty file
+	Rootfs    *storage.File // zip archive to extract as the filesystem
+	Artifacts []string      // files to pack in a zip archive after execution
+}
+
+// maxModules is the number of compiled modules that are kept for reuse
+const maxModules = 64
+
+// compiledModule is a cached module, which may still be compiling
+type compiledModule struct {
+	ref     string
+	ready   chan struct{} // closed when the compilation finished
+	module  wazero.CompiledModule
+	err     error
+	element *list.Element // in the modulesLRU or nil, when evicted
+	users   int           // tasks using the module; it is closed after eviction when zero
+}
+
+// Create a new WorkerPool, which can run up to `workers` tasks concurrently.
+func NewWorkerPool(ctx context.Context, workers int) *WorkerPool {
+
+	// close modules when their context is cancelled to be able to abort tasks
+	config := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithCompilationCache(wazero.NewCompilationCache())
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	pool := &WorkerPool{
+		runtime:    runtime,
+		tickets:    make(chan struct{}, workers),
+		modules:    make(map[string]*compiledModule),
+		modulesLRU: list.New(),
+		running:    make(map[string]context.CancelCauseFunc),
+	}
+	for len(pool.tickets) < cap(pool.tickets) {
+		pool.tickets <- struct{}{}
+	}
+	return pool
+}
+
+// Capacity returns the maximum number of concurrent tasks.
+func (wp *WorkerPool) Capacity() int {
+	return cap(wp.tickets)
+}
+
+// Cancel a queued or running task by its id.
+func (wp *WorkerPool) Cancel(id, reason string) {
+	wp.runningMutex.Lock()
+	defer wp.runningMutex.Unlock()
+	if cancel, ok := wp.running[id]; ok {
+		cancel(fmt.Errorf("task cancelled: %s", reason))
+	}
+}
+
+// RunWasip1 waits for a free ticket and then executes the module with the given
+// parameters synchronously. A non-zero exit code is not considered an error.
+func (wp *WorkerPool) RunWasip1(ctx context.Context, id string, task *Wasip1Task) (*wasimoff.Task_Wasip1_Output, error) {
+
+	// register the task for cancellation before waiting in queue
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	wp.runningMutex.Lock()
+	wp.running[id] = cancel
+	wp.runningMutex.Unlock()
+	defer func() {
+		wp.runningMutex.Lock()
+		delete(wp.running, id)
+		wp.runningMutex.Unlock()
+	}()
+
+	// take a ticket and put it back afterwards
+	select {
+	case <-wp.tickets:
+		defer func() { wp.tickets <- struct{}{} }()
+	case <-ctx.Done():
+		return nil, context.Cause(ctx)
+	}
+
+	// get or compile the webassembly module
+	module, release, err := wp.compile(ctx, task.Binary)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// initialize the filesystem in a temporary directory
+	rootfs, err := os.MkdirTemp("", "wasimoff-rootfs-")
+	if err != nil {
+		return nil, fmt.Errorf("creating rootfs: %w", err)
+	}
+	defer os.RemoveAll(rootfs)
+	if task.Rootfs != nil {
+		if err := extractRootfs(task.Rootfs.Bytes, rootfs); err != nil {
+			return nil, fmt.Errorf("extracting rootfs: %w", err)
+		}
+	}
+
+	// prepare the module configuration
+	var stdout, stderr bytes.Buffer
+	config := wazero.NewModuleConfig().
+		WithName(""). // anonymous, so the same module can run concurrently
+		WithArgs(task.Args...).
+		WithStdin(bytes.NewReader(task.Stdin)).
+		WithStdout(&stdout).
+		WithStderr(&stderr).
+		WithFSConfig(wazero.NewFSConfig().WithDirMount(rootfs, "/")).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader)
+	for _, env := range task.Envs {
+		key, value, _ := strings.Cut(env, "=")
+		config = config.WithEnv(key, value)
+	}
+
+	// instantiate the module, which runs its _start function until it exits
+	status := int32(0)
+	instance, err := wp.runtime.InstantiateModule(ctx, module, config)
+	if instance != nil {
+		instance.Close(context.Background())
+	}
+	if err != nil {
+		var exit *sys.ExitError
+		if !errors.As(err, &exit) {
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			// module was closed due to cancellation
+			return nil, context.Cause(ctx)
+		}
+		status = int32(exit.ExitCode())
+	}
+
+	// format the result
+	output := &wasimoff.Task_Wasip1_Output{
+		Status: proto.Int32(status),
+		Stdout: stdout.Bytes(),
+		Stderr: stderr.Bytes(),
+	}
+	if len(task.Artifacts) > 0 {
+		artifacts, err := compressArtifacts(rootfs, task.Artifacts)
+		if err != nil {
+			return nil, fmt.Errorf("packing artifacts: %w", err)
+		}
+		output.Artifacts = &wasimoff.File{Blob: artifacts}
+	}
+	return output, nil
+}
+
+// compile a binary or reuse a previously compiled module with the same ref. The
+// compilation runs outside of the lock, so other modules are not held up, and
+// concurrent tasks with the same binary wait for it. Call release when done.
+func (wp *WorkerPool) compile(ctx context.Context, binary *storage.File) (module wazero.CompiledModule, release func(), err error) {
+	ref := binary.Ref()
+	wp.modulesMutex.Lock()
+	cached, ok := wp.modules[ref]
+	if ok {
+		wp.modulesLRU.MoveToFront(cached.element)
+	} else {
+		cached = &compiledModule{ref: ref, ready: make(chan struct{})}
+		cached.element = wp.modulesLRU.PushFront(cached)
+		wp.modules[ref] = cached
+		for wp.modulesLRU.Len() > maxModules {
+			wp.evict(wp.modulesLRU.Back().Value.(*compiledModule))
+		}
+		// finish compiling even if this task is cancelled, others may wait for it
+		go func() {
+			log.Printf("compiling module %s", ref)
+			module, err := wp.runtime.CompileModule(context.Background(), binary.Bytes)
+			wp.modulesMutex.Lock()
+			defer wp.modulesMutex.Unlock()
+			cached.module, cached.err = module, err
+			close(cached.ready)
+			if err != nil && cached.element != nil {
+				wp.evict(cached) // try again next time
+			}
+			wp.closeUnused(cached)
+		}()
+	}
+	cached.users++
+	wp.modulesMutex.Unlock()
+
+	release = func() {
+		wp.modulesMutex.Lock()
+		defer wp.modulesMutex.Unlock()
+		cached.users--
+		wp.closeUnused(cached)
+	}
+	select {
+	case <-cached.ready:
+	case <-ctx.Done():
+		release()
+		return nil, nil, context.Cause(ctx)
+	}
+	if cached.err != nil {
+		release()
+		return nil, nil, fmt.Errorf("compiling module: %w", cached.err)
+	}
+	return cached.module, release, nil
+}
+
+// Evict the compiled module of a binary, e.g. when it was deleted on the Broker.
+func (wp *WorkerPool) Evict(ref string) {
+	wp.modulesMutex.Lock()
+	defer wp.modulesMutex.Unlock()
+	if cached, ok := wp.modules[ref]; ok {
+		wp.evict(cached)
+	}
+}
+
+// evict a module from the cache; must be called with the modulesMutex held
+func (wp *WorkerPool) evict(cached *compiledModule) {
+	wp.modulesLRU.Remove(cached.element)
+	delete(wp.modules, cached.ref)
+	cached.element = nil
+	wp.closeUnused(cached)
+}
+
+// closeUnused closes an evicted module once it is compiled and no task uses it
+// anymore; must be called with the modulesMutex held
+func (wp *WorkerPool) closeUnused(cached *compiledModule) {
+	if cached.element == nil && cached.users == 0 && cached.module != nil {
+		cached.module.Close(context.Background())
+		cached.module = nil
+	}
+}
+
+//
+// -------------------- filesystem utils --------------------
+
+// extractRootfs unpacks a zip archive into a directory
+func extractRootfs(archive []byte, dir string) error {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+	for _, entry := range zr.File {
+
+		// never write outside of the rootfs
+		name := strings.TrimSuffix(entry.Name, "/")
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path in archive: %q", entry.Name)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		// create directories, including parents of files
+		if entry.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+
+		// copy file contents
+		if err := extractFile(entry, path); err != nil {
+			return err
+		}
+
+	}
+	return nil
+}
+
+// extractFile copies a single file entry from the zip archive to path
+func extractFile(entry *zip.File, path string) error {
+	r, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	w, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// compressArtifacts packs the requested files from rootfs in a zip archive
+func compressArtifacts(dir string, artifacts []string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	// add all requested files
+	for _, filename := range artifacts {
+		filename = strings.TrimPrefix(filename, "/")
+		if !filepath.IsLocal(filename) {
+			return nil, fmt.Errorf("invalid artifact path: %q", filename)
+		}
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(filename)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue // skip missing files
+		} else if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			if _, err := zw.Create(filename + "/"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		w, err := zw.Create(filename)
+		if err != nil {
+			return nil, err
+		}
+		contents, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(filename)))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(contents); err != nil {
+			return nil, err
+		}
+	}
+
+	// finish the file and return its contents
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
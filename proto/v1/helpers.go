@@ -29,6 +29,63 @@ func (wt *Task_Wasip1_Params) InheritNil(parent *Task_Wasip1_Params) *Task_Wasip
 	return wt
 }
 
+// Fill any nil (!) task parameters from a parent task specification.
+func (pt *Task_Pyodide_Params) InheritNil(parent *Task_Pyodide_Params) *Task_Pyodide_Params {
+	if parent == nil {
+		// nothing to do when parent is nil
+		return pt
+	}
+	if pt.Script == nil {
+		pt.Script = parent.Script
+	}
+	if pt.Args == nil {
+		pt.Args = parent.Args
+	}
+	if pt.Envs == nil {
+		pt.Envs = parent.Envs
+	}
+	if pt.Stdin == nil {
+		pt.Stdin = parent.Stdin
+	}
+	if pt.Rootfs == nil {
+		pt.Rootfs = parent.Rootfs
+	}
+	if pt.Artifacts == nil {
+		pt.Artifacts = parent.Artifacts
+	}
+	if pt.Packages == nil {
+		pt.Packages = parent.Packages
+	}
+	if pt.Pickle == nil {
+		pt.Pickle = parent.Pickle
+	}
+	return pt
+}
+
+// Wrap all tasks of a job in Task_Requests, inheriting nil parameters from the
+// parent. The metadata and QoS parameters are left for the caller to fill.
+func (job *Client_Job_Wasip1Request) TaskRequests() []*Task_Request {
+	requests := make([]*Task_Request, len(job.GetTasks()))
+	for i, task := range job.GetTasks() {
+		requests[i] = &Task_Request{Parameters: &Task_Request_Wasip1{
+			Wasip1: task.InheritNil(job.Parent),
+		}}
+	}
+	return requests
+}
+
+// Wrap all tasks of a job in Task_Requests, inheriting nil parameters from the
+// parent. The metadata and QoS parameters are left for the caller to fill.
+func (job *Client_Job_PyodideRequest) TaskRequests() []*Task_Request {
+	requests := make([]*Task_Request, len(job.GetTasks()))
+	for i, task := range job.GetTasks() {
+		requests[i] = &Task_Request{Parameters: &Task_Request_Pyodide{
+			Pyodide: task.InheritNil(job.Parent),
+		}}
+	}
+	return requests
+}
+
 // Return a string list of needed files for a task request.
 func (tr *Task_Request) GetRequiredFiles() (files []string) {
 	files = make([]string, 0, 2) // usually max. binary + rootfs
@@ -45,7 +102,10 @@ func (tr *Task_Request) GetRequiredFiles() (files []string) {
 		}
 
 	case *Task_Request_Pyodide:
-		// log.Fatalln("GetRequiredFiles is not implemented for Pyodide yet")
+		p := params.Pyodide
+		if p.Rootfs != nil && p.Rootfs.GetRef() != "" {
+			files = append(files, *p.Rootfs.Ref)
+		}
 
 	}
 
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	anypb "google.golang.org/protobuf/types/known/anypb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -241,6 +242,80 @@ func (*Task) Descriptor() ([]byte, []int) {
 	return file_proto_v1_messages_proto_rawDescGZIP(), []int{1}
 }
 
+// Ping checks the liveness of the other side and measures the round-trip time.
+// It is answered directly in the Messenger, without queueing it for a handler.
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_proto_v1_messages_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{2}
+}
+
+type PingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_proto_v1_messages_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{3}
+}
+
 // File is a file reference with optional mime-type. The ref could be a plain
 // filename, a prefixed hash digest or a URL to fetch from. When stored, a hash
 // digest should be computed to have a stable identifier.
@@ -255,7 +330,7 @@ type File struct {
 
 func (x *File) Reset() {
 	*x = File{}
-	mi := &file_proto_v1_messages_proto_msgTypes[2]
+	mi := &file_proto_v1_messages_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -267,7 +342,7 @@ func (x *File) String() string {
 func (*File) ProtoMessage() {}
 
 func (x *File) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[2]
+	mi := &file_proto_v1_messages_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -280,7 +355,7 @@ func (x *File) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use File.ProtoReflect.Descriptor instead.
 func (*File) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{2}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *File) GetRef() string {
@@ -313,7 +388,7 @@ type FileListingRequest struct {
 
 func (x *FileListingRequest) Reset() {
 	*x = FileListingRequest{}
-	mi := &file_proto_v1_messages_proto_msgTypes[3]
+	mi := &file_proto_v1_messages_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -325,7 +400,7 @@ func (x *FileListingRequest) String() string {
 func (*FileListingRequest) ProtoMessage() {}
 
 func (x *FileListingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[3]
+	mi := &file_proto_v1_messages_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -338,7 +413,7 @@ func (x *FileListingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileListingRequest.ProtoReflect.Descriptor instead.
 func (*FileListingRequest) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{3}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{5}
 }
 
 type FileListingResponse struct {
@@ -350,7 +425,7 @@ type FileListingResponse struct {
 
 func (x *FileListingResponse) Reset() {
 	*x = FileListingResponse{}
-	mi := &file_proto_v1_messages_proto_msgTypes[4]
+	mi := &file_proto_v1_messages_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -362,7 +437,7 @@ func (x *FileListingResponse) String() string {
 func (*FileListingResponse) ProtoMessage() {}
 
 func (x *FileListingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[4]
+	mi := &file_proto_v1_messages_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,7 +450,7 @@ func (x *FileListingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileListingResponse.ProtoReflect.Descriptor instead.
 func (*FileListingResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{4}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *FileListingResponse) GetFiles() []string {
@@ -395,7 +470,7 @@ type FileProbeRequest struct {
 
 func (x *FileProbeRequest) Reset() {
 	*x = FileProbeRequest{}
-	mi := &file_proto_v1_messages_proto_msgTypes[5]
+	mi := &file_proto_v1_messages_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -407,7 +482,7 @@ func (x *FileProbeRequest) String() string {
 func (*FileProbeRequest) ProtoMessage() {}
 
 func (x *FileProbeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[5]
+	mi := &file_proto_v1_messages_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -420,7 +495,7 @@ func (x *FileProbeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileProbeRequest.ProtoReflect.Descriptor instead.
 func (*FileProbeRequest) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{5}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *FileProbeRequest) GetFile() string {
@@ -439,7 +514,7 @@ type FileProbeResponse struct {
 
 func (x *FileProbeResponse) Reset() {
 	*x = FileProbeResponse{}
-	mi := &file_proto_v1_messages_proto_msgTypes[6]
+	mi := &file_proto_v1_messages_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -451,7 +526,7 @@ func (x *FileProbeResponse) String() string {
 func (*FileProbeResponse) ProtoMessage() {}
 
 func (x *FileProbeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[6]
+	mi := &file_proto_v1_messages_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -464,7 +539,7 @@ func (x *FileProbeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileProbeResponse.ProtoReflect.Descriptor instead.
 func (*FileProbeResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{6}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *FileProbeResponse) GetOk() bool {
@@ -484,7 +559,7 @@ type FileUploadRequest struct {
 
 func (x *FileUploadRequest) Reset() {
 	*x = FileUploadRequest{}
-	mi := &file_proto_v1_messages_proto_msgTypes[7]
+	mi := &file_proto_v1_messages_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -496,7 +571,7 @@ func (x *FileUploadRequest) String() string {
 func (*FileUploadRequest) ProtoMessage() {}
 
 func (x *FileUploadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[7]
+	mi := &file_proto_v1_messages_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -509,7 +584,7 @@ func (x *FileUploadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileUploadRequest.ProtoReflect.Descriptor instead.
 func (*FileUploadRequest) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{7}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *FileUploadRequest) GetUpload() *File {
@@ -528,7 +603,7 @@ type FileUploadResponse struct {
 
 func (x *FileUploadResponse) Reset() {
 	*x = FileUploadResponse{}
-	mi := &file_proto_v1_messages_proto_msgTypes[8]
+	mi := &file_proto_v1_messages_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -540,7 +615,7 @@ func (x *FileUploadResponse) String() string {
 func (*FileUploadResponse) ProtoMessage() {}
 
 func (x *FileUploadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[8]
+	mi := &file_proto_v1_messages_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -553,7 +628,7 @@ func (x *FileUploadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileUploadResponse.ProtoReflect.Descriptor instead.
 func (*FileUploadResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{8}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *FileUploadResponse) GetErr() string {
@@ -563,6 +638,95 @@ func (x *FileUploadResponse) GetErr() string {
 	return ""
 }
 
+// FileDelete asks the Provider to remove a file, after it was deleted on the Broker.
+type FileDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *string                `protobuf:"bytes,1,opt,name=file" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileDeleteRequest) Reset() {
+	*x = FileDeleteRequest{}
+	mi := &file_proto_v1_messages_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDeleteRequest) ProtoMessage() {}
+
+func (x *FileDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDeleteRequest.ProtoReflect.Descriptor instead.
+func (*FileDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FileDeleteRequest) GetFile() string {
+	if x != nil && x.File != nil {
+		return *x.File
+	}
+	return ""
+}
+
+type FileDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Err           *string                `protobuf:"bytes,1,opt,name=err" json:"err,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileDeleteResponse) Reset() {
+	*x = FileDeleteResponse{}
+	mi := &file_proto_v1_messages_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileDeleteResponse) ProtoMessage() {}
+
+func (x *FileDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileDeleteResponse.ProtoReflect.Descriptor instead.
+func (*FileDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FileDeleteResponse) GetErr() string {
+	if x != nil && x.Err != nil {
+		return *x.Err
+	}
+	return ""
+}
+
 // FileDownload can be sent by the Provider to request a file download.
 type FileDownloadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -573,7 +737,7 @@ type FileDownloadRequest struct {
 
 func (x *FileDownloadRequest) Reset() {
 	*x = FileDownloadRequest{}
-	mi := &file_proto_v1_messages_proto_msgTypes[9]
+	mi := &file_proto_v1_messages_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -585,7 +749,7 @@ func (x *FileDownloadRequest) String() string {
 func (*FileDownloadRequest) ProtoMessage() {}
 
 func (x *FileDownloadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[9]
+	mi := &file_proto_v1_messages_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -598,7 +762,7 @@ func (x *FileDownloadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileDownloadRequest.ProtoReflect.Descriptor instead.
 func (*FileDownloadRequest) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{9}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *FileDownloadRequest) GetFile() string {
@@ -618,7 +782,7 @@ type FileDownloadResponse struct {
 
 func (x *FileDownloadResponse) Reset() {
 	*x = FileDownloadResponse{}
-	mi := &file_proto_v1_messages_proto_msgTypes[10]
+	mi := &file_proto_v1_messages_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -630,7 +794,7 @@ func (x *FileDownloadResponse) String() string {
 func (*FileDownloadResponse) ProtoMessage() {}
 
 func (x *FileDownloadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[10]
+	mi := &file_proto_v1_messages_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -643,7 +807,7 @@ func (x *FileDownloadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FileDownloadResponse.ProtoReflect.Descriptor instead.
 func (*FileDownloadResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{10}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *FileDownloadResponse) GetDownload() *File {
@@ -660,6 +824,299 @@ func (x *FileDownloadResponse) GetErr() string {
 	return ""
 }
 
+// Large files are transferred in chunks, so they don't block other messages on
+// the connection for too long. The receiver keeps partial files by their ref,
+// so an interrupted transfer can resume where it stopped, even after a reconnect.
+// The assembled file is verified against the digest in its ref.
+type FileChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ref           *string                `protobuf:"bytes,1,opt,name=ref" json:"ref,omitempty"`        // digest of the complete file
+	Media         *string                `protobuf:"bytes,2,opt,name=media" json:"media,omitempty"`    // media type in MIME notation
+	Total         *uint64                `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`   // size of the complete file
+	Offset        *uint64                `protobuf:"varint,4,opt,name=offset" json:"offset,omitempty"` // position of this chunk in the file
+	Blob          []byte                 `protobuf:"bytes,5,opt,name=blob" json:"blob,omitempty"`      // the chunk contents, its length is implicit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	mi := &file_proto_v1_messages_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *FileChunk) GetRef() string {
+	if x != nil && x.Ref != nil {
+		return *x.Ref
+	}
+	return ""
+}
+
+func (x *FileChunk) GetMedia() string {
+	if x != nil && x.Media != nil {
+		return *x.Media
+	}
+	return ""
+}
+
+func (x *FileChunk) GetTotal() uint64 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+func (x *FileChunk) GetOffset() uint64 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+func (x *FileChunk) GetBlob() []byte {
+	if x != nil {
+		return x.Blob
+	}
+	return nil
+}
+
+// FileChunkUpload pushes a chunk of a file to the Provider. A chunk without any
+// contents only queries the progress. The Provider always responds with the
+// number of bytes it holds, i.e. the offset for the next chunk.
+type FileChunkUploadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunk         *FileChunk             `protobuf:"bytes,1,opt,name=chunk" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunkUploadRequest) Reset() {
+	*x = FileChunkUploadRequest{}
+	mi := &file_proto_v1_messages_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunkUploadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunkUploadRequest) ProtoMessage() {}
+
+func (x *FileChunkUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunkUploadRequest.ProtoReflect.Descriptor instead.
+func (*FileChunkUploadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *FileChunkUploadRequest) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type FileChunkUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Received      *uint64                `protobuf:"varint,1,opt,name=received" json:"received,omitempty"`
+	Err           *string                `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunkUploadResponse) Reset() {
+	*x = FileChunkUploadResponse{}
+	mi := &file_proto_v1_messages_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunkUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunkUploadResponse) ProtoMessage() {}
+
+func (x *FileChunkUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunkUploadResponse.ProtoReflect.Descriptor instead.
+func (*FileChunkUploadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FileChunkUploadResponse) GetReceived() uint64 {
+	if x != nil && x.Received != nil {
+		return *x.Received
+	}
+	return 0
+}
+
+func (x *FileChunkUploadResponse) GetErr() string {
+	if x != nil && x.Err != nil {
+		return *x.Err
+	}
+	return ""
+}
+
+// FileChunkDownload can be sent by the Provider to request a chunk of a file.
+// The Broker may send less than the requested length, but never more.
+type FileChunkDownloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *string                `protobuf:"bytes,1,opt,name=file" json:"file,omitempty"` // name or ref
+	Offset        *uint64                `protobuf:"varint,2,opt,name=offset" json:"offset,omitempty"`
+	Length        *uint64                `protobuf:"varint,3,opt,name=length" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunkDownloadRequest) Reset() {
+	*x = FileChunkDownloadRequest{}
+	mi := &file_proto_v1_messages_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunkDownloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunkDownloadRequest) ProtoMessage() {}
+
+func (x *FileChunkDownloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunkDownloadRequest.ProtoReflect.Descriptor instead.
+func (*FileChunkDownloadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FileChunkDownloadRequest) GetFile() string {
+	if x != nil && x.File != nil {
+		return *x.File
+	}
+	return ""
+}
+
+func (x *FileChunkDownloadRequest) GetOffset() uint64 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
+func (x *FileChunkDownloadRequest) GetLength() uint64 {
+	if x != nil && x.Length != nil {
+		return *x.Length
+	}
+	return 0
+}
+
+type FileChunkDownloadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunk         *FileChunk             `protobuf:"bytes,1,opt,name=chunk" json:"chunk,omitempty"`
+	Err           *string                `protobuf:"bytes,2,opt,name=err" json:"err,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileChunkDownloadResponse) Reset() {
+	*x = FileChunkDownloadResponse{}
+	mi := &file_proto_v1_messages_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileChunkDownloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunkDownloadResponse) ProtoMessage() {}
+
+func (x *FileChunkDownloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunkDownloadResponse.ProtoReflect.Descriptor instead.
+func (*FileChunkDownloadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *FileChunkDownloadResponse) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *FileChunkDownloadResponse) GetErr() string {
+	if x != nil && x.Err != nil {
+		return *x.Err
+	}
+	return ""
+}
+
 type Event struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -668,7 +1125,7 @@ type Event struct {
 
 func (x *Event) Reset() {
 	*x = Event{}
-	mi := &file_proto_v1_messages_proto_msgTypes[11]
+	mi := &file_proto_v1_messages_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -680,7 +1137,7 @@ func (x *Event) String() string {
 func (*Event) ProtoMessage() {}
 
 func (x *Event) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[11]
+	mi := &file_proto_v1_messages_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -693,7 +1150,7 @@ func (x *Event) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event.ProtoReflect.Descriptor instead.
 func (*Event) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20}
 }
 
 type Client struct {
@@ -704,7 +1161,7 @@ type Client struct {
 
 func (x *Client) Reset() {
 	*x = Client{}
-	mi := &file_proto_v1_messages_proto_msgTypes[12]
+	mi := &file_proto_v1_messages_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -716,7 +1173,7 @@ func (x *Client) String() string {
 func (*Client) ProtoMessage() {}
 
 func (x *Client) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[12]
+	mi := &file_proto_v1_messages_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -729,7 +1186,7 @@ func (x *Client) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Client.ProtoReflect.Descriptor instead.
 func (*Client) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21}
 }
 
 // Information about this task for identification and tracing.
@@ -744,7 +1201,7 @@ type Task_Metadata struct {
 
 func (x *Task_Metadata) Reset() {
 	*x = Task_Metadata{}
-	mi := &file_proto_v1_messages_proto_msgTypes[13]
+	mi := &file_proto_v1_messages_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -756,7 +1213,7 @@ func (x *Task_Metadata) String() string {
 func (*Task_Metadata) ProtoMessage() {}
 
 func (x *Task_Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[13]
+	mi := &file_proto_v1_messages_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -795,16 +1252,25 @@ func (x *Task_Metadata) GetProvider() string {
 
 // Quality of Service (QoS) parameters for a given task.
 type Task_QoS struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Priority      *bool                  `protobuf:"varint,1,opt,name=priority" json:"priority,omitempty"`
-	Deadline      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deadline" json:"deadline,omitempty"` // TODO
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Priority class from -2 (lowest) to 2 (highest), default 0. Higher classes
+	// are dispatched first but waiting tasks are promoted over time. This used
+	// to be a boolean flag with the same binary encoding, where true equals 1.
+	Priority *int32 `protobuf:"varint,1,opt,name=priority" json:"priority,omitempty"`
+	// Tasks which did not finish by then fail. Submissions with a deadline, which
+	// already passed or is sooner than the timeout, are rejected.
+	Deadline *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=deadline" json:"deadline,omitempty"`
+	// Maximum runtime of a single attempt on a Provider, after which the task is
+	// cancelled. Must be positive and can only shorten the timeout configured
+	// on the Broker.
+	Timeout       *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout" json:"timeout,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Task_QoS) Reset() {
 	*x = Task_QoS{}
-	mi := &file_proto_v1_messages_proto_msgTypes[14]
+	mi := &file_proto_v1_messages_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -816,7 +1282,7 @@ func (x *Task_QoS) String() string {
 func (*Task_QoS) ProtoMessage() {}
 
 func (x *Task_QoS) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[14]
+	mi := &file_proto_v1_messages_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -832,11 +1298,11 @@ func (*Task_QoS) Descriptor() ([]byte, []int) {
 	return file_proto_v1_messages_proto_rawDescGZIP(), []int{1, 1}
 }
 
-func (x *Task_QoS) GetPriority() bool {
+func (x *Task_QoS) GetPriority() int32 {
 	if x != nil && x.Priority != nil {
 		return *x.Priority
 	}
-	return false
+	return 0
 }
 
 func (x *Task_QoS) GetDeadline() *timestamppb.Timestamp {
@@ -846,6 +1312,13 @@ func (x *Task_QoS) GetDeadline() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Task_QoS) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
 // Event to terminate a running task on Provider.
 type Task_Cancel struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -857,7 +1330,7 @@ type Task_Cancel struct {
 
 func (x *Task_Cancel) Reset() {
 	*x = Task_Cancel{}
-	mi := &file_proto_v1_messages_proto_msgTypes[15]
+	mi := &file_proto_v1_messages_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -869,7 +1342,7 @@ func (x *Task_Cancel) String() string {
 func (*Task_Cancel) ProtoMessage() {}
 
 func (x *Task_Cancel) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[15]
+	mi := &file_proto_v1_messages_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -915,7 +1388,7 @@ type Task_Request struct {
 
 func (x *Task_Request) Reset() {
 	*x = Task_Request{}
-	mi := &file_proto_v1_messages_proto_msgTypes[16]
+	mi := &file_proto_v1_messages_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -927,7 +1400,7 @@ func (x *Task_Request) String() string {
 func (*Task_Request) ProtoMessage() {}
 
 func (x *Task_Request) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[16]
+	mi := &file_proto_v1_messages_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1014,7 +1487,7 @@ type Task_Response struct {
 
 func (x *Task_Response) Reset() {
 	*x = Task_Response{}
-	mi := &file_proto_v1_messages_proto_msgTypes[17]
+	mi := &file_proto_v1_messages_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1026,7 +1499,7 @@ func (x *Task_Response) String() string {
 func (*Task_Response) ProtoMessage() {}
 
 func (x *Task_Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[17]
+	mi := &file_proto_v1_messages_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1116,7 +1589,7 @@ type Task_Wasip1 struct {
 
 func (x *Task_Wasip1) Reset() {
 	*x = Task_Wasip1{}
-	mi := &file_proto_v1_messages_proto_msgTypes[18]
+	mi := &file_proto_v1_messages_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1128,7 +1601,7 @@ func (x *Task_Wasip1) String() string {
 func (*Task_Wasip1) ProtoMessage() {}
 
 func (x *Task_Wasip1) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[18]
+	mi := &file_proto_v1_messages_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1155,7 +1628,7 @@ type Task_Pyodide struct {
 
 func (x *Task_Pyodide) Reset() {
 	*x = Task_Pyodide{}
-	mi := &file_proto_v1_messages_proto_msgTypes[19]
+	mi := &file_proto_v1_messages_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1167,7 +1640,7 @@ func (x *Task_Pyodide) String() string {
 func (*Task_Pyodide) ProtoMessage() {}
 
 func (x *Task_Pyodide) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[19]
+	mi := &file_proto_v1_messages_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1199,7 +1672,7 @@ type Task_Wasip1_Params struct {
 
 func (x *Task_Wasip1_Params) Reset() {
 	*x = Task_Wasip1_Params{}
-	mi := &file_proto_v1_messages_proto_msgTypes[20]
+	mi := &file_proto_v1_messages_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1211,7 +1684,7 @@ func (x *Task_Wasip1_Params) String() string {
 func (*Task_Wasip1_Params) ProtoMessage() {}
 
 func (x *Task_Wasip1_Params) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[20]
+	mi := &file_proto_v1_messages_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1283,7 +1756,7 @@ type Task_Wasip1_Output struct {
 
 func (x *Task_Wasip1_Output) Reset() {
 	*x = Task_Wasip1_Output{}
-	mi := &file_proto_v1_messages_proto_msgTypes[21]
+	mi := &file_proto_v1_messages_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1295,7 +1768,7 @@ func (x *Task_Wasip1_Output) String() string {
 func (*Task_Wasip1_Output) ProtoMessage() {}
 
 func (x *Task_Wasip1_Output) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[21]
+	mi := &file_proto_v1_messages_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1347,13 +1820,14 @@ type Task_Wasip1_Result struct {
 	//	*Task_Wasip1_Result_Error
 	//	*Task_Wasip1_Result_Ok
 	Result        isTask_Wasip1_Result_Result `protobuf_oneof:"result"`
+	Runtime       *durationpb.Duration        `protobuf:"bytes,3,opt,name=runtime" json:"runtime,omitempty"` // time spent on the Provider, measured by the Broker
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Task_Wasip1_Result) Reset() {
 	*x = Task_Wasip1_Result{}
-	mi := &file_proto_v1_messages_proto_msgTypes[22]
+	mi := &file_proto_v1_messages_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1365,7 +1839,7 @@ func (x *Task_Wasip1_Result) String() string {
 func (*Task_Wasip1_Result) ProtoMessage() {}
 
 func (x *Task_Wasip1_Result) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[22]
+	mi := &file_proto_v1_messages_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1406,6 +1880,13 @@ func (x *Task_Wasip1_Result) GetOk() *Task_Wasip1_Output {
 	return nil
 }
 
+func (x *Task_Wasip1_Result) GetRuntime() *durationpb.Duration {
+	if x != nil {
+		return x.Runtime
+	}
+	return nil
+}
+
 type isTask_Wasip1_Result_Result interface {
 	isTask_Wasip1_Result_Result()
 }
@@ -1422,9 +1903,17 @@ func (*Task_Wasip1_Result_Error) isTask_Wasip1_Result_Result() {}
 
 func (*Task_Wasip1_Result_Ok) isTask_Wasip1_Result_Result() {}
 
+// Contains the script and execution arguments to run a single Python task in
+// Pyodide on the Provider. The rootfs archive is extracted to the working
+// directory before the script runs, and artifacts are collected from there.
 type Task_Pyodide_Params struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Script        *string                `protobuf:"bytes,1,opt,name=script" json:"script,omitempty"`
+	Args          []string               `protobuf:"bytes,2,rep,name=args" json:"args,omitempty"`
+	Envs          []string               `protobuf:"bytes,3,rep,name=envs" json:"envs,omitempty"`
+	Stdin         []byte                 `protobuf:"bytes,4,opt,name=stdin" json:"stdin,omitempty"`
+	Rootfs        *File                  `protobuf:"bytes,5,opt,name=rootfs" json:"rootfs,omitempty"`
+	Artifacts     []string               `protobuf:"bytes,6,rep,name=artifacts" json:"artifacts,omitempty"`
 	Packages      []string               `protobuf:"bytes,7,rep,name=packages" json:"packages,omitempty"`
 	Pickle        []byte                 `protobuf:"bytes,8,opt,name=pickle" json:"pickle,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -1433,7 +1922,7 @@ type Task_Pyodide_Params struct {
 
 func (x *Task_Pyodide_Params) Reset() {
 	*x = Task_Pyodide_Params{}
-	mi := &file_proto_v1_messages_proto_msgTypes[23]
+	mi := &file_proto_v1_messages_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1445,7 +1934,7 @@ func (x *Task_Pyodide_Params) String() string {
 func (*Task_Pyodide_Params) ProtoMessage() {}
 
 func (x *Task_Pyodide_Params) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[23]
+	mi := &file_proto_v1_messages_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1468,6 +1957,41 @@ func (x *Task_Pyodide_Params) GetScript() string {
 	return ""
 }
 
+func (x *Task_Pyodide_Params) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *Task_Pyodide_Params) GetEnvs() []string {
+	if x != nil {
+		return x.Envs
+	}
+	return nil
+}
+
+func (x *Task_Pyodide_Params) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *Task_Pyodide_Params) GetRootfs() *File {
+	if x != nil {
+		return x.Rootfs
+	}
+	return nil
+}
+
+func (x *Task_Pyodide_Params) GetArtifacts() []string {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
 func (x *Task_Pyodide_Params) GetPackages() []string {
 	if x != nil {
 		return x.Packages
@@ -1487,14 +2011,15 @@ type Task_Pyodide_Output struct {
 	Pickle        []byte                 `protobuf:"bytes,1,opt,name=pickle" json:"pickle,omitempty"`
 	Stdout        []byte                 `protobuf:"bytes,2,opt,name=stdout" json:"stdout,omitempty"`
 	Stderr        []byte                 `protobuf:"bytes,3,opt,name=stderr" json:"stderr,omitempty"`
-	Version       *string                `protobuf:"bytes,4,opt,name=version" json:"version,omitempty"` // File artifacts = 4;
+	Version       *string                `protobuf:"bytes,4,opt,name=version" json:"version,omitempty"`
+	Artifacts     *File                  `protobuf:"bytes,5,opt,name=artifacts" json:"artifacts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Task_Pyodide_Output) Reset() {
 	*x = Task_Pyodide_Output{}
-	mi := &file_proto_v1_messages_proto_msgTypes[24]
+	mi := &file_proto_v1_messages_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1506,7 +2031,7 @@ func (x *Task_Pyodide_Output) String() string {
 func (*Task_Pyodide_Output) ProtoMessage() {}
 
 func (x *Task_Pyodide_Output) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[24]
+	mi := &file_proto_v1_messages_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1550,6 +2075,13 @@ func (x *Task_Pyodide_Output) GetVersion() string {
 	return ""
 }
 
+func (x *Task_Pyodide_Output) GetArtifacts() *File {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
 // Wrap a Pyodide.Output in a Result, which can be an Error or OK.
 type Task_Pyodide_Result struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -1558,13 +2090,14 @@ type Task_Pyodide_Result struct {
 	//	*Task_Pyodide_Result_Error
 	//	*Task_Pyodide_Result_Ok
 	Result        isTask_Pyodide_Result_Result `protobuf_oneof:"result"`
+	Runtime       *durationpb.Duration         `protobuf:"bytes,3,opt,name=runtime" json:"runtime,omitempty"` // time spent on the Provider, measured by the Broker
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Task_Pyodide_Result) Reset() {
 	*x = Task_Pyodide_Result{}
-	mi := &file_proto_v1_messages_proto_msgTypes[25]
+	mi := &file_proto_v1_messages_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1576,7 +2109,7 @@ func (x *Task_Pyodide_Result) String() string {
 func (*Task_Pyodide_Result) ProtoMessage() {}
 
 func (x *Task_Pyodide_Result) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[25]
+	mi := &file_proto_v1_messages_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1617,6 +2150,13 @@ func (x *Task_Pyodide_Result) GetOk() *Task_Pyodide_Output {
 	return nil
 }
 
+func (x *Task_Pyodide_Result) GetRuntime() *durationpb.Duration {
+	if x != nil {
+		return x.Runtime
+	}
+	return nil
+}
+
 type isTask_Pyodide_Result_Result interface {
 	isTask_Pyodide_Result_Result()
 }
@@ -1643,7 +2183,7 @@ type Event_GenericMessage struct {
 
 func (x *Event_GenericMessage) Reset() {
 	*x = Event_GenericMessage{}
-	mi := &file_proto_v1_messages_proto_msgTypes[26]
+	mi := &file_proto_v1_messages_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1655,7 +2195,7 @@ func (x *Event_GenericMessage) String() string {
 func (*Event_GenericMessage) ProtoMessage() {}
 
 func (x *Event_GenericMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[26]
+	mi := &file_proto_v1_messages_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1668,7 +2208,7 @@ func (x *Event_GenericMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_GenericMessage.ProtoReflect.Descriptor instead.
 func (*Event_GenericMessage) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11, 0}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20, 0}
 }
 
 func (x *Event_GenericMessage) GetMessage() string {
@@ -1683,13 +2223,14 @@ type Event_ProviderHello struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`           // a logging-friendly name of the provider
 	Useragent     *string                `protobuf:"bytes,2,opt,name=useragent" json:"useragent,omitempty"` // like the navigator.useragent in browser
+	Id            *string                `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`               // a persistent identifier to resume the session after reconnects
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Event_ProviderHello) Reset() {
 	*x = Event_ProviderHello{}
-	mi := &file_proto_v1_messages_proto_msgTypes[27]
+	mi := &file_proto_v1_messages_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1701,7 +2242,7 @@ func (x *Event_ProviderHello) String() string {
 func (*Event_ProviderHello) ProtoMessage() {}
 
 func (x *Event_ProviderHello) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[27]
+	mi := &file_proto_v1_messages_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1714,7 +2255,7 @@ func (x *Event_ProviderHello) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_ProviderHello.ProtoReflect.Descriptor instead.
 func (*Event_ProviderHello) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11, 1}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20, 1}
 }
 
 func (x *Event_ProviderHello) GetName() string {
@@ -1731,6 +2272,13 @@ func (x *Event_ProviderHello) GetUseragent() string {
 	return ""
 }
 
+func (x *Event_ProviderHello) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
 // ProviderResources is information about the available resources in Worker pool
 type Event_ProviderResources struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1742,7 +2290,7 @@ type Event_ProviderResources struct {
 
 func (x *Event_ProviderResources) Reset() {
 	*x = Event_ProviderResources{}
-	mi := &file_proto_v1_messages_proto_msgTypes[28]
+	mi := &file_proto_v1_messages_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1754,7 +2302,7 @@ func (x *Event_ProviderResources) String() string {
 func (*Event_ProviderResources) ProtoMessage() {}
 
 func (x *Event_ProviderResources) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[28]
+	mi := &file_proto_v1_messages_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1767,7 +2315,7 @@ func (x *Event_ProviderResources) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_ProviderResources.ProtoReflect.Descriptor instead.
 func (*Event_ProviderResources) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11, 2}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20, 2}
 }
 
 func (x *Event_ProviderResources) GetConcurrency() uint32 {
@@ -1794,7 +2342,7 @@ type Event_ClusterInfo struct {
 
 func (x *Event_ClusterInfo) Reset() {
 	*x = Event_ClusterInfo{}
-	mi := &file_proto_v1_messages_proto_msgTypes[29]
+	mi := &file_proto_v1_messages_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1806,7 +2354,7 @@ func (x *Event_ClusterInfo) String() string {
 func (*Event_ClusterInfo) ProtoMessage() {}
 
 func (x *Event_ClusterInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[29]
+	mi := &file_proto_v1_messages_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1819,7 +2367,7 @@ func (x *Event_ClusterInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_ClusterInfo.ProtoReflect.Descriptor instead.
 func (*Event_ClusterInfo) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11, 3}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20, 3}
 }
 
 func (x *Event_ClusterInfo) GetProviders() uint32 {
@@ -1840,7 +2388,7 @@ type Event_Throughput struct {
 
 func (x *Event_Throughput) Reset() {
 	*x = Event_Throughput{}
-	mi := &file_proto_v1_messages_proto_msgTypes[30]
+	mi := &file_proto_v1_messages_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1852,7 +2400,7 @@ func (x *Event_Throughput) String() string {
 func (*Event_Throughput) ProtoMessage() {}
 
 func (x *Event_Throughput) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[30]
+	mi := &file_proto_v1_messages_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1865,7 +2413,7 @@ func (x *Event_Throughput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_Throughput.ProtoReflect.Descriptor instead.
 func (*Event_Throughput) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11, 4}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20, 4}
 }
 
 func (x *Event_Throughput) GetOverall() float32 {
@@ -1893,7 +2441,7 @@ type Event_FileSystemUpdate struct {
 
 func (x *Event_FileSystemUpdate) Reset() {
 	*x = Event_FileSystemUpdate{}
-	mi := &file_proto_v1_messages_proto_msgTypes[31]
+	mi := &file_proto_v1_messages_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1905,7 +2453,7 @@ func (x *Event_FileSystemUpdate) String() string {
 func (*Event_FileSystemUpdate) ProtoMessage() {}
 
 func (x *Event_FileSystemUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[31]
+	mi := &file_proto_v1_messages_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1918,7 +2466,7 @@ func (x *Event_FileSystemUpdate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Event_FileSystemUpdate.ProtoReflect.Descriptor instead.
 func (*Event_FileSystemUpdate) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{11, 5}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{20, 5}
 }
 
 func (x *Event_FileSystemUpdate) GetAdded() []string {
@@ -1949,7 +2497,7 @@ type Client_Job struct {
 
 func (x *Client_Job) Reset() {
 	*x = Client_Job{}
-	mi := &file_proto_v1_messages_proto_msgTypes[32]
+	mi := &file_proto_v1_messages_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1961,7 +2509,7 @@ func (x *Client_Job) String() string {
 func (*Client_Job) ProtoMessage() {}
 
 func (x *Client_Job) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[32]
+	mi := &file_proto_v1_messages_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1974,20 +2522,21 @@ func (x *Client_Job) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Client_Job.ProtoReflect.Descriptor instead.
 func (*Client_Job) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12, 0}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0}
 }
 
 type Client_Job_Wasip1Request struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Parent        *Task_Wasip1_Params    `protobuf:"bytes,1,opt,name=parent" json:"parent,omitempty"`
 	Tasks         []*Task_Wasip1_Params  `protobuf:"bytes,2,rep,name=tasks" json:"tasks,omitempty"`
+	Qos           *Task_QoS              `protobuf:"bytes,3,opt,name=qos" json:"qos,omitempty"` // applies to every task of the job
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Client_Job_Wasip1Request) Reset() {
 	*x = Client_Job_Wasip1Request{}
-	mi := &file_proto_v1_messages_proto_msgTypes[33]
+	mi := &file_proto_v1_messages_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1999,7 +2548,7 @@ func (x *Client_Job_Wasip1Request) String() string {
 func (*Client_Job_Wasip1Request) ProtoMessage() {}
 
 func (x *Client_Job_Wasip1Request) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[33]
+	mi := &file_proto_v1_messages_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2012,7 +2561,7 @@ func (x *Client_Job_Wasip1Request) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Client_Job_Wasip1Request.ProtoReflect.Descriptor instead.
 func (*Client_Job_Wasip1Request) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12, 0, 0}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 0}
 }
 
 func (x *Client_Job_Wasip1Request) GetParent() *Task_Wasip1_Params {
@@ -2029,6 +2578,13 @@ func (x *Client_Job_Wasip1Request) GetTasks() []*Task_Wasip1_Params {
 	return nil
 }
 
+func (x *Client_Job_Wasip1Request) GetQos() *Task_QoS {
+	if x != nil {
+		return x.Qos
+	}
+	return nil
+}
+
 type Client_Job_Wasip1Response struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         *string                `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
@@ -2039,7 +2595,7 @@ type Client_Job_Wasip1Response struct {
 
 func (x *Client_Job_Wasip1Response) Reset() {
 	*x = Client_Job_Wasip1Response{}
-	mi := &file_proto_v1_messages_proto_msgTypes[34]
+	mi := &file_proto_v1_messages_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2051,7 +2607,7 @@ func (x *Client_Job_Wasip1Response) String() string {
 func (*Client_Job_Wasip1Response) ProtoMessage() {}
 
 func (x *Client_Job_Wasip1Response) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[34]
+	mi := &file_proto_v1_messages_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2064,7 +2620,7 @@ func (x *Client_Job_Wasip1Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Client_Job_Wasip1Response.ProtoReflect.Descriptor instead.
 func (*Client_Job_Wasip1Response) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12, 0, 1}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 1}
 }
 
 func (x *Client_Job_Wasip1Response) GetError() string {
@@ -2085,13 +2641,14 @@ type Client_Job_PyodideRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Parent        *Task_Pyodide_Params   `protobuf:"bytes,1,opt,name=parent" json:"parent,omitempty"`
 	Tasks         []*Task_Pyodide_Params `protobuf:"bytes,2,rep,name=tasks" json:"tasks,omitempty"`
+	Qos           *Task_QoS              `protobuf:"bytes,3,opt,name=qos" json:"qos,omitempty"` // applies to every task of the job
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Client_Job_PyodideRequest) Reset() {
 	*x = Client_Job_PyodideRequest{}
-	mi := &file_proto_v1_messages_proto_msgTypes[35]
+	mi := &file_proto_v1_messages_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2103,7 +2660,7 @@ func (x *Client_Job_PyodideRequest) String() string {
 func (*Client_Job_PyodideRequest) ProtoMessage() {}
 
 func (x *Client_Job_PyodideRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[35]
+	mi := &file_proto_v1_messages_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2116,7 +2673,7 @@ func (x *Client_Job_PyodideRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Client_Job_PyodideRequest.ProtoReflect.Descriptor instead.
 func (*Client_Job_PyodideRequest) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12, 0, 2}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 2}
 }
 
 func (x *Client_Job_PyodideRequest) GetParent() *Task_Pyodide_Params {
@@ -2133,6 +2690,13 @@ func (x *Client_Job_PyodideRequest) GetTasks() []*Task_Pyodide_Params {
 	return nil
 }
 
+func (x *Client_Job_PyodideRequest) GetQos() *Task_QoS {
+	if x != nil {
+		return x.Qos
+	}
+	return nil
+}
+
 type Client_Job_PyodideResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Error         *string                `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
@@ -2143,7 +2707,7 @@ type Client_Job_PyodideResponse struct {
 
 func (x *Client_Job_PyodideResponse) Reset() {
 	*x = Client_Job_PyodideResponse{}
-	mi := &file_proto_v1_messages_proto_msgTypes[36]
+	mi := &file_proto_v1_messages_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2155,7 +2719,7 @@ func (x *Client_Job_PyodideResponse) String() string {
 func (*Client_Job_PyodideResponse) ProtoMessage() {}
 
 func (x *Client_Job_PyodideResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_v1_messages_proto_msgTypes[36]
+	mi := &file_proto_v1_messages_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2168,7 +2732,7 @@ func (x *Client_Job_PyodideResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Client_Job_PyodideResponse.ProtoReflect.Descriptor instead.
 func (*Client_Job_PyodideResponse) Descriptor() ([]byte, []int) {
-	return file_proto_v1_messages_proto_rawDescGZIP(), []int{12, 0, 3}
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 3}
 }
 
 func (x *Client_Job_PyodideResponse) GetError() string {
@@ -2185,6 +2749,223 @@ func (x *Client_Job_PyodideResponse) GetTasks() []*Task_Pyodide_Result {
 	return nil
 }
 
+// Asynchronous jobs are submitted with the same requests but only return a
+// Status with an identifier, which can be polled for progress later.
+type Client_Job_Status struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *string                `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`                // identifier of this job
+	Error         *string                `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`          // the job failed entirely, e.g. due to missing files
+	Tasks         *uint32                `protobuf:"varint,3,opt,name=tasks" json:"tasks,omitempty"`         // total number of tasks
+	Pending       *uint32                `protobuf:"varint,4,opt,name=pending" json:"pending,omitempty"`     // tasks still queued or running
+	Completed     *uint32                `protobuf:"varint,5,opt,name=completed" json:"completed,omitempty"` // tasks finished with a result
+	Failed        *uint32                `protobuf:"varint,6,opt,name=failed" json:"failed,omitempty"`       // tasks finished with an error
+	Cancelled     *bool                  `protobuf:"varint,7,opt,name=cancelled" json:"cancelled,omitempty"` // the job was cancelled by the client
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Client_Job_Status) Reset() {
+	*x = Client_Job_Status{}
+	mi := &file_proto_v1_messages_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Client_Job_Status) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client_Job_Status) ProtoMessage() {}
+
+func (x *Client_Job_Status) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client_Job_Status.ProtoReflect.Descriptor instead.
+func (*Client_Job_Status) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 4}
+}
+
+func (x *Client_Job_Status) GetId() string {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return ""
+}
+
+func (x *Client_Job_Status) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *Client_Job_Status) GetTasks() uint32 {
+	if x != nil && x.Tasks != nil {
+		return *x.Tasks
+	}
+	return 0
+}
+
+func (x *Client_Job_Status) GetPending() uint32 {
+	if x != nil && x.Pending != nil {
+		return *x.Pending
+	}
+	return 0
+}
+
+func (x *Client_Job_Status) GetCompleted() uint32 {
+	if x != nil && x.Completed != nil {
+		return *x.Completed
+	}
+	return 0
+}
+
+func (x *Client_Job_Status) GetFailed() uint32 {
+	if x != nil && x.Failed != nil {
+		return *x.Failed
+	}
+	return 0
+}
+
+func (x *Client_Job_Status) GetCancelled() bool {
+	if x != nil && x.Cancelled != nil {
+		return *x.Cancelled
+	}
+	return false
+}
+
+// Results of asynchronous jobs are streamed individually as tasks finish,
+// so they carry the identifier of the job and the index of the task in the
+// original request. On the client WebSocket they are sent as Events.
+type Client_Job_Wasip1TaskResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         *uint32                `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Result        *Task_Wasip1_Result    `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
+	Job           *string                `protobuf:"bytes,3,opt,name=job" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Client_Job_Wasip1TaskResult) Reset() {
+	*x = Client_Job_Wasip1TaskResult{}
+	mi := &file_proto_v1_messages_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Client_Job_Wasip1TaskResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client_Job_Wasip1TaskResult) ProtoMessage() {}
+
+func (x *Client_Job_Wasip1TaskResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client_Job_Wasip1TaskResult.ProtoReflect.Descriptor instead.
+func (*Client_Job_Wasip1TaskResult) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 5}
+}
+
+func (x *Client_Job_Wasip1TaskResult) GetIndex() uint32 {
+	if x != nil && x.Index != nil {
+		return *x.Index
+	}
+	return 0
+}
+
+func (x *Client_Job_Wasip1TaskResult) GetResult() *Task_Wasip1_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Client_Job_Wasip1TaskResult) GetJob() string {
+	if x != nil && x.Job != nil {
+		return *x.Job
+	}
+	return ""
+}
+
+type Client_Job_PyodideTaskResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         *uint32                `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	Result        *Task_Pyodide_Result   `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
+	Job           *string                `protobuf:"bytes,3,opt,name=job" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Client_Job_PyodideTaskResult) Reset() {
+	*x = Client_Job_PyodideTaskResult{}
+	mi := &file_proto_v1_messages_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Client_Job_PyodideTaskResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Client_Job_PyodideTaskResult) ProtoMessage() {}
+
+func (x *Client_Job_PyodideTaskResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_v1_messages_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Client_Job_PyodideTaskResult.ProtoReflect.Descriptor instead.
+func (*Client_Job_PyodideTaskResult) Descriptor() ([]byte, []int) {
+	return file_proto_v1_messages_proto_rawDescGZIP(), []int{21, 0, 6}
+}
+
+func (x *Client_Job_PyodideTaskResult) GetIndex() uint32 {
+	if x != nil && x.Index != nil {
+		return *x.Index
+	}
+	return 0
+}
+
+func (x *Client_Job_PyodideTaskResult) GetResult() *Task_Pyodide_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *Client_Job_PyodideTaskResult) GetJob() string {
+	if x != nil && x.Job != nil {
+		return *x.Job
+	}
+	return ""
+}
+
 var File_proto_v1_messages_proto protoreflect.FileDescriptor
 
 var file_proto_v1_messages_proto_rawDesc = string([]byte{
@@ -2192,6 +2973,8 @@ var file_proto_v1_messages_proto_rawDesc = string([]byte{
 	0x67, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x77, 0x61, 0x73, 0x69, 0x6d,
 	0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
 	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
 	0x74, 0x6f, 0x22, 0xe5, 0x01, 0x0a, 0x08, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12,
@@ -2208,192 +2991,281 @@ var file_proto_v1_messages_proto_rawDesc = string([]byte{
 	0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f,
 	0x57, 0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x10,
 	0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x10, 0x02, 0x12,
-	0x09, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x03, 0x22, 0x92, 0x0b, 0x0a, 0x04, 0x54,
+	0x09, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x10, 0x03, 0x22, 0xee, 0x0d, 0x0a, 0x04, 0x54,
 	0x61, 0x73, 0x6b, 0x1a, 0x54, 0x0a, 0x08, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
 	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
 	0x1c, 0x0a, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x12, 0x1a, 0x0a,
 	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x1a, 0x59, 0x0a, 0x03, 0x51, 0x6f, 0x53,
-	0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x36, 0x0a, 0x08,
-	0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a,
-	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
-	0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x64, 0x65, 0x61, 0x64,
-	0x6c, 0x69, 0x6e, 0x65, 0x1a, 0x30, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x16,
-	0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x1a, 0xef, 0x01, 0x0a, 0x07, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1a, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54,
-	0x61, 0x73, 0x6b, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x04, 0x69, 0x6e,
-	0x66, 0x6f, 0x12, 0x27, 0x0a, 0x03, 0x71, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x15, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61,
-	0x73, 0x6b, 0x2e, 0x51, 0x6f, 0x53, 0x52, 0x03, 0x71, 0x6f, 0x73, 0x12, 0x39, 0x0a, 0x06, 0x77,
-	0x61, 0x73, 0x69, 0x70, 0x31, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61,
-	0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57,
-	0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x48, 0x00, 0x52, 0x06,
-	0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x12, 0x3c, 0x0a, 0x07, 0x70, 0x79, 0x6f, 0x64, 0x69, 0x64,
-	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f,
-	0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69,
-	0x64, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x48, 0x00, 0x52, 0x07, 0x70, 0x79, 0x6f,
-	0x64, 0x69, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
-	0x72, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x0a, 0x1a, 0xdb, 0x01, 0x0a, 0x08, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76,
-	0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52,
-	0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x39, 0x0a,
-	0x06, 0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
+	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x1a, 0x8e, 0x01, 0x0a, 0x03, 0x51, 0x6f,
+	0x53, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x36, 0x0a,
+	0x08, 0x64, 0x65, 0x61, 0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x08, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x33, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x1a, 0x30, 0x0a, 0x06, 0x43, 0x61,
+	0x6e, 0x63, 0x65, 0x6c, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x1a, 0xef, 0x01, 0x0a,
+	0x07, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66,
+	0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x27, 0x0a, 0x03, 0x71, 0x6f, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x51, 0x6f, 0x53, 0x52, 0x03, 0x71, 0x6f,
+	0x73, 0x12, 0x39, 0x0a, 0x06, 0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e,
+	0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x48, 0x00, 0x52, 0x06, 0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x12, 0x3c, 0x0a, 0x07,
+	0x70, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
 	0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b,
-	0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00,
-	0x52, 0x06, 0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x12, 0x3c, 0x0a, 0x07, 0x70, 0x79, 0x6f, 0x64,
-	0x69, 0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69,
-	0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f,
-	0x64, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x07, 0x70,
-	0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x4a, 0x04, 0x08, 0x03, 0x10, 0x0a, 0x1a, 0xa8, 0x03, 0x0a, 0x06, 0x57, 0x61, 0x73, 0x69, 0x70,
-	0x31, 0x1a, 0xba, 0x01, 0x0a, 0x06, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x29, 0x0a, 0x06,
-	0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77,
+	0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x48,
+	0x00, 0x52, 0x07, 0x70, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x0a, 0x1a, 0xdb,
+	0x01, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x04, 0x69,
+	0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x77, 0x61, 0x73, 0x69,
+	0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x4d, 0x65, 0x74,
+	0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x12, 0x39, 0x0a, 0x06, 0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x18, 0x0a, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76,
+	0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x48, 0x00, 0x52, 0x06, 0x77, 0x61, 0x73, 0x69, 0x70, 0x31, 0x12, 0x3c,
+	0x0a, 0x07, 0x70, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61,
+	0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x48, 0x00, 0x52, 0x07, 0x70, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x42, 0x08, 0x0a, 0x06,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x0a, 0x1a, 0xde, 0x03, 0x0a,
+	0x06, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x1a, 0xba, 0x01, 0x0a, 0x06, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x73, 0x12, 0x29, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x12, 0x0a,
+	0x04, 0x61, 0x72, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x04, 0x65, 0x6e, 0x76, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x72,
+	0x6f, 0x6f, 0x74, 0x66, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61,
+	0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06,
+	0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x1a, 0x81, 0x01, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x12, 0x2f, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73,
+	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x09, 0x61,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x1a, 0x92, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x31, 0x0a, 0x02, 0x6f,
+	0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f,
+	0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70,
+	0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x48, 0x00, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x33,
+	0x0a, 0x07, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x74,
+	0x69, 0x6d, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x1a, 0x9b, 0x04,
+	0x0a, 0x07, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x1a, 0xdb, 0x01, 0x0a, 0x06, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x61, 0x72, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73,
+	0x12, 0x12, 0x0a, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04,
+	0x65, 0x6e, 0x76, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x72, 0x6f,
+	0x6f, 0x74, 0x66, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73,
+	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06, 0x72,
+	0x6f, 0x6f, 0x74, 0x66, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18,
+	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x70, 0x69, 0x63, 0x6b, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x70, 0x69, 0x63, 0x6b, 0x6c, 0x65, 0x1a, 0x9b, 0x01, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x69, 0x63, 0x6b, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x06, 0x70, 0x69, 0x63, 0x6b, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
+	0x64, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f,
+	0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f,
+	0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69,
+	0x66, 0x61, 0x63, 0x74, 0x73, 0x1a, 0x93, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x32, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e,
+	0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x48, 0x00, 0x52, 0x02, 0x6f, 0x6b, 0x12, 0x33, 0x0a, 0x07,
+	0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x07, 0x72, 0x75, 0x6e, 0x74, 0x69, 0x6d,
+	0x65, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x0d, 0x0a, 0x0b, 0x50,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x0e, 0x0a, 0x0c, 0x50, 0x69,
+	0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x42, 0x0a, 0x04, 0x46, 0x69,
+	0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x72, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6c,
+	0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x22, 0x14,
+	0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x2b, 0x0a, 0x13, 0x46, 0x69, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x66,
+	0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x22, 0x26, 0x0a, 0x10, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0x23, 0x0a, 0x11, 0x46, 0x69, 0x6c,
+	0x65, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e,
+	0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x02, 0x6f, 0x6b, 0x22, 0x3e,
+	0x0a, 0x11, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76,
+	0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x26,
+	0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x65, 0x72, 0x72, 0x22, 0x27, 0x0a, 0x11, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22,
+	0x26, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72, 0x22, 0x29, 0x0a, 0x13, 0x46, 0x69, 0x6c, 0x65, 0x44,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69,
+	0x6c, 0x65, 0x22, 0x57, 0x0a, 0x14, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x64, 0x6f,
+	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77,
 	0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52,
-	0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x61, 0x72, 0x67, 0x73, 0x18,
-	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x65,
-	0x6e, 0x76, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x65, 0x6e, 0x76, 0x73, 0x12,
-	0x14, 0x0a, 0x05, 0x73, 0x74, 0x64, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
-	0x73, 0x74, 0x64, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66,
-	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x66, 0x73,
-	0x12, 0x1c, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18, 0x06, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x1a, 0x81,
-	0x01, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64,
-	0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x65, 0x72,
-	0x72, 0x12, 0x2f, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e,
-	0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
-	0x74, 0x73, 0x1a, 0x5d, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x05,
-	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x12, 0x31, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54,
-	0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75,
-	0x74, 0x48, 0x00, 0x52, 0x02, 0x6f, 0x6b, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x1a, 0xab, 0x02, 0x0a, 0x07, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x1a, 0x54, 0x0a,
-	0x06, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12,
-	0x1a, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70,
-	0x69, 0x63, 0x6b, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x69, 0x63,
-	0x6b, 0x6c, 0x65, 0x1a, 0x6a, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12, 0x16, 0x0a,
-	0x06, 0x70, 0x69, 0x63, 0x6b, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70,
-	0x69, 0x63, 0x6b, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x74, 0x64, 0x6f, 0x75, 0x74, 0x12, 0x16, 0x0a,
-	0x06, 0x73, 0x74, 0x64, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73,
-	0x74, 0x64, 0x65, 0x72, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x1a,
-	0x5e, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x05, 0x65, 0x72, 0x72,
-	0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
-	0x72, 0x12, 0x32, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
-	0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b,
-	0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x48,
-	0x00, 0x52, 0x02, 0x6f, 0x6b, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
-	0x42, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x65, 0x64,
-	0x69, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x12,
-	0x12, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62,
-	0x6c, 0x6f, 0x62, 0x22, 0x14, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x69,
-	0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x2b, 0x0a, 0x13, 0x46, 0x69, 0x6c,
-	0x65, 0x4c, 0x69, 0x73, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x26, 0x0a, 0x10, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x72,
-	0x6f, 0x62, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69,
-	0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0x23,
-	0x0a, 0x11, 0x46, 0x69, 0x6c, 0x65, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x6f, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x02, 0x6f, 0x6b, 0x22, 0x3e, 0x0a, 0x11, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x75, 0x70, 0x6c, 0x6f,
-	0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d,
-	0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x06, 0x75, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x22, 0x26, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x65, 0x55, 0x70, 0x6c, 0x6f, 0x61,
-	0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72, 0x22, 0x29, 0x0a, 0x13, 0x46,
-	0x69, 0x6c, 0x65, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0x57, 0x0a, 0x14, 0x46, 0x69, 0x6c, 0x65, 0x44, 0x6f,
-	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2d,
-	0x0a, 0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x11, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46,
-	0x69, 0x6c, 0x65, 0x52, 0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x10, 0x0a,
-	0x03, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72, 0x22,
-	0xf2, 0x02, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x2a, 0x0a, 0x0e, 0x47, 0x65, 0x6e,
-	0x65, 0x72, 0x69, 0x63, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x41, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
-	0x72, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x73,
-	0x65, 0x72, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75,
-	0x73, 0x65, 0x72, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x1a, 0x4b, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x76,
-	0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x20, 0x0a,
-	0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0d, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05,
-	0x74, 0x61, 0x73, 0x6b, 0x73, 0x1a, 0x2b, 0x0a, 0x0b, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
-	0x72, 0x73, 0x1a, 0x3c, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74,
-	0x12, 0x18, 0x0a, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x02, 0x52, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x6c, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x79, 0x6f,
-	0x75, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x79, 0x6f, 0x75, 0x72, 0x73,
-	0x1a, 0x42, 0x0a, 0x10, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65,
-	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x6d,
-	0x6f, 0x76, 0x65, 0x64, 0x22, 0xd6, 0x03, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x1a,
-	0xcb, 0x03, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x1a, 0x7f, 0x0a, 0x0d, 0x57, 0x61, 0x73, 0x69, 0x70,
-	0x31, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x65,
-	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d,
-	0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69,
-	0x70, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72, 0x65, 0x6e,
-	0x74, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72, 0x22, 0x75, 0x0a, 0x09, 0x46,
+	0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x62, 0x6c,
+	0x6f, 0x62, 0x22, 0x46, 0x0a, 0x16, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x05,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x77, 0x61,
+	0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x47, 0x0a, 0x17, 0x46, 0x69,
+	0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x64, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x65, 0x72, 0x72, 0x22, 0x5e, 0x0a, 0x18, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66,
+	0x69, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x22, 0x5b, 0x0a, 0x19, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x2c, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x10,
+	0x0a, 0x03, 0x65, 0x72, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x72, 0x72,
+	0x22, 0x82, 0x03, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x1a, 0x2a, 0x0a, 0x0e, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x69, 0x63, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x51, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x75,
+	0x73, 0x65, 0x72, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x75, 0x73, 0x65, 0x72, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x1a, 0x4b, 0x0a, 0x11, 0x50, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x20,
+	0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x1a, 0x2b, 0x0a, 0x0b, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65,
+	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x73, 0x1a, 0x3c, 0x0a, 0x0a, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x02, 0x52, 0x07, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x6c, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x79,
+	0x6f, 0x75, 0x72, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x79, 0x6f, 0x75, 0x72,
+	0x73, 0x1a, 0x42, 0x0a, 0x10, 0x46, 0x69, 0x6c, 0x65, 0x53, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x61, 0x64, 0x64, 0x65, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x72,
+	0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65,
+	0x6d, 0x6f, 0x76, 0x65, 0x64, 0x22, 0xca, 0x07, 0x0a, 0x06, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x1a, 0xbf, 0x07, 0x0a, 0x03, 0x4a, 0x6f, 0x62, 0x1a, 0xa8, 0x01, 0x0a, 0x0d, 0x57, 0x61, 0x73,
+	0x69, 0x70, 0x31, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73,
+	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61,
+	0x73, 0x69, 0x70, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72,
+	0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31,
+	0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x73, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x27, 0x0a, 0x03, 0x71, 0x6f,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f,
+	0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x51, 0x6f, 0x53, 0x52, 0x03,
+	0x71, 0x6f, 0x73, 0x1a, 0x5d, 0x0a, 0x0e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x35, 0x0a, 0x05, 0x74,
+	0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73,
+	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61,
+	0x73, 0x69, 0x70, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x05, 0x74, 0x61, 0x73,
+	0x6b, 0x73, 0x1a, 0xab, 0x01, 0x0a, 0x0e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66,
+	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65,
+	0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x12,
+	0x36, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73,
+	0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73,
+	0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x27, 0x0a, 0x03, 0x71, 0x6f, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e,
+	0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x51, 0x6f, 0x53, 0x52, 0x03, 0x71, 0x6f, 0x73,
+	0x1a, 0x5f, 0x0a, 0x0f, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x36, 0x0a, 0x05, 0x74, 0x61, 0x73,
+	0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d,
+	0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64,
+	0x69, 0x64, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b,
+	0x73, 0x1a, 0xb2, 0x01, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x70, 0x65, 0x6e, 0x64, 0x69,
+	0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x6c, 0x65, 0x64, 0x1a, 0x73, 0x0a, 0x10, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31,
+	0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x12, 0x37, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
 	0x32, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54,
-	0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d,
-	0x73, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x1a, 0x5d, 0x0a, 0x0e, 0x57, 0x61, 0x73, 0x69,
-	0x70, 0x31, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
-	0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
-	0x12, 0x35, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61,
-	0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x1a, 0x82, 0x01, 0x0a, 0x0e, 0x50, 0x79, 0x6f, 0x64,
-	0x69, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x06, 0x70, 0x61,
-	0x72, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73,
-	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79,
-	0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x06, 0x70, 0x61,
-	0x72, 0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76,
-	0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x73, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x1a, 0x5f, 0x0a, 0x0f,
-	0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x36, 0x0a, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e,
-	0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x2e,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x05, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x2a, 0x5c, 0x0a,
-	0x0b, 0x53, 0x75, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x0b, 0x0a, 0x07,
-	0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x21, 0x0a, 0x1d, 0x77, 0x61, 0x73,
-	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x76,
-	0x31, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19,
-	0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65,
-	0x72, 0x5f, 0x76, 0x31, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x10, 0x02, 0x32, 0x5b, 0x0a, 0x08, 0x57,
-	0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x12, 0x4f, 0x0a, 0x09, 0x52, 0x75, 0x6e, 0x57, 0x61,
-	0x73, 0x69, 0x70, 0x31, 0x12, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e,
-	0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x50,
-	0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66,
-	0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x42, 0x1e, 0x5a, 0x1c, 0x77, 0x61, 0x73, 0x69,
-	0x6d, 0x6f, 0x66, 0x66, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x76, 0x31, 0x3b, 0x77, 0x61,
-	0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x76, 0x31, 0x62, 0x08, 0x65, 0x64, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x70, 0xe8, 0x07,
+	0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a, 0x6f, 0x62, 0x1a, 0x75, 0x0a, 0x11, 0x50,
+	0x79, 0x6f, 0x64, 0x69, 0x64, 0x65, 0x54, 0x61, 0x73, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x38, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66,
+	0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x50, 0x79, 0x6f, 0x64, 0x69, 0x64,
+	0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x6a, 0x6f, 0x62, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6a,
+	0x6f, 0x62, 0x2a, 0x5c, 0x0a, 0x0b, 0x53, 0x75, 0x62, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x21,
+	0x0a, 0x1d, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x5f, 0x70, 0x72, 0x6f, 0x76, 0x69,
+	0x64, 0x65, 0x72, 0x5f, 0x76, 0x31, 0x5f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x10,
+	0x01, 0x12, 0x1d, 0x0a, 0x19, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x5f, 0x70, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x5f, 0x76, 0x31, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x10, 0x02,
+	0x32, 0x5b, 0x0a, 0x08, 0x57, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x12, 0x4f, 0x0a, 0x09,
+	0x52, 0x75, 0x6e, 0x57, 0x61, 0x73, 0x69, 0x70, 0x31, 0x12, 0x1f, 0x2e, 0x77, 0x61, 0x73, 0x69,
+	0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61, 0x73,
+	0x69, 0x70, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x1a, 0x1f, 0x2e, 0x77, 0x61, 0x73,
+	0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x73, 0x6b, 0x2e, 0x57, 0x61,
+	0x73, 0x69, 0x70, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x42, 0x1e, 0x5a,
+	0x1c, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f,
+	0x76, 0x31, 0x3b, 0x77, 0x61, 0x73, 0x69, 0x6d, 0x6f, 0x66, 0x66, 0x76, 0x31, 0x62, 0x08, 0x65,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x70, 0xe8, 0x07,
 })
 
 var (
@@ -2409,81 +3281,105 @@ func file_proto_v1_messages_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_v1_messages_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_proto_v1_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
+var file_proto_v1_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_proto_v1_messages_proto_goTypes = []any{
-	(Subprotocol)(0),                   // 0: wasimoff.v1.Subprotocol
-	(Envelope_MessageType)(0),          // 1: wasimoff.v1.Envelope.MessageType
-	(*Envelope)(nil),                   // 2: wasimoff.v1.Envelope
-	(*Task)(nil),                       // 3: wasimoff.v1.Task
-	(*File)(nil),                       // 4: wasimoff.v1.File
-	(*FileListingRequest)(nil),         // 5: wasimoff.v1.FileListingRequest
-	(*FileListingResponse)(nil),        // 6: wasimoff.v1.FileListingResponse
-	(*FileProbeRequest)(nil),           // 7: wasimoff.v1.FileProbeRequest
-	(*FileProbeResponse)(nil),          // 8: wasimoff.v1.FileProbeResponse
-	(*FileUploadRequest)(nil),          // 9: wasimoff.v1.FileUploadRequest
-	(*FileUploadResponse)(nil),         // 10: wasimoff.v1.FileUploadResponse
-	(*FileDownloadRequest)(nil),        // 11: wasimoff.v1.FileDownloadRequest
-	(*FileDownloadResponse)(nil),       // 12: wasimoff.v1.FileDownloadResponse
-	(*Event)(nil),                      // 13: wasimoff.v1.Event
-	(*Client)(nil),                     // 14: wasimoff.v1.Client
-	(*Task_Metadata)(nil),              // 15: wasimoff.v1.Task.Metadata
-	(*Task_QoS)(nil),                   // 16: wasimoff.v1.Task.QoS
-	(*Task_Cancel)(nil),                // 17: wasimoff.v1.Task.Cancel
-	(*Task_Request)(nil),               // 18: wasimoff.v1.Task.Request
-	(*Task_Response)(nil),              // 19: wasimoff.v1.Task.Response
-	(*Task_Wasip1)(nil),                // 20: wasimoff.v1.Task.Wasip1
-	(*Task_Pyodide)(nil),               // 21: wasimoff.v1.Task.Pyodide
-	(*Task_Wasip1_Params)(nil),         // 22: wasimoff.v1.Task.Wasip1.Params
-	(*Task_Wasip1_Output)(nil),         // 23: wasimoff.v1.Task.Wasip1.Output
-	(*Task_Wasip1_Result)(nil),         // 24: wasimoff.v1.Task.Wasip1.Result
-	(*Task_Pyodide_Params)(nil),        // 25: wasimoff.v1.Task.Pyodide.Params
-	(*Task_Pyodide_Output)(nil),        // 26: wasimoff.v1.Task.Pyodide.Output
-	(*Task_Pyodide_Result)(nil),        // 27: wasimoff.v1.Task.Pyodide.Result
-	(*Event_GenericMessage)(nil),       // 28: wasimoff.v1.Event.GenericMessage
-	(*Event_ProviderHello)(nil),        // 29: wasimoff.v1.Event.ProviderHello
-	(*Event_ProviderResources)(nil),    // 30: wasimoff.v1.Event.ProviderResources
-	(*Event_ClusterInfo)(nil),          // 31: wasimoff.v1.Event.ClusterInfo
-	(*Event_Throughput)(nil),           // 32: wasimoff.v1.Event.Throughput
-	(*Event_FileSystemUpdate)(nil),     // 33: wasimoff.v1.Event.FileSystemUpdate
-	(*Client_Job)(nil),                 // 34: wasimoff.v1.Client.Job
-	(*Client_Job_Wasip1Request)(nil),   // 35: wasimoff.v1.Client.Job.Wasip1Request
-	(*Client_Job_Wasip1Response)(nil),  // 36: wasimoff.v1.Client.Job.Wasip1Response
-	(*Client_Job_PyodideRequest)(nil),  // 37: wasimoff.v1.Client.Job.PyodideRequest
-	(*Client_Job_PyodideResponse)(nil), // 38: wasimoff.v1.Client.Job.PyodideResponse
-	(*anypb.Any)(nil),                  // 39: google.protobuf.Any
-	(*timestamppb.Timestamp)(nil),      // 40: google.protobuf.Timestamp
+	(Subprotocol)(0),                     // 0: wasimoff.v1.Subprotocol
+	(Envelope_MessageType)(0),            // 1: wasimoff.v1.Envelope.MessageType
+	(*Envelope)(nil),                     // 2: wasimoff.v1.Envelope
+	(*Task)(nil),                         // 3: wasimoff.v1.Task
+	(*PingRequest)(nil),                  // 4: wasimoff.v1.PingRequest
+	(*PingResponse)(nil),                 // 5: wasimoff.v1.PingResponse
+	(*File)(nil),                         // 6: wasimoff.v1.File
+	(*FileListingRequest)(nil),           // 7: wasimoff.v1.FileListingRequest
+	(*FileListingResponse)(nil),          // 8: wasimoff.v1.FileListingResponse
+	(*FileProbeRequest)(nil),             // 9: wasimoff.v1.FileProbeRequest
+	(*FileProbeResponse)(nil),            // 10: wasimoff.v1.FileProbeResponse
+	(*FileUploadRequest)(nil),            // 11: wasimoff.v1.FileUploadRequest
+	(*FileUploadResponse)(nil),           // 12: wasimoff.v1.FileUploadResponse
+	(*FileDeleteRequest)(nil),            // 13: wasimoff.v1.FileDeleteRequest
+	(*FileDeleteResponse)(nil),           // 14: wasimoff.v1.FileDeleteResponse
+	(*FileDownloadRequest)(nil),          // 15: wasimoff.v1.FileDownloadRequest
+	(*FileDownloadResponse)(nil),         // 16: wasimoff.v1.FileDownloadResponse
+	(*FileChunk)(nil),                    // 17: wasimoff.v1.FileChunk
+	(*FileChunkUploadRequest)(nil),       // 18: wasimoff.v1.FileChunkUploadRequest
+	(*FileChunkUploadResponse)(nil),      // 19: wasimoff.v1.FileChunkUploadResponse
+	(*FileChunkDownloadRequest)(nil),     // 20: wasimoff.v1.FileChunkDownloadRequest
+	(*FileChunkDownloadResponse)(nil),    // 21: wasimoff.v1.FileChunkDownloadResponse
+	(*Event)(nil),                        // 22: wasimoff.v1.Event
+	(*Client)(nil),                       // 23: wasimoff.v1.Client
+	(*Task_Metadata)(nil),                // 24: wasimoff.v1.Task.Metadata
+	(*Task_QoS)(nil),                     // 25: wasimoff.v1.Task.QoS
+	(*Task_Cancel)(nil),                  // 26: wasimoff.v1.Task.Cancel
+	(*Task_Request)(nil),                 // 27: wasimoff.v1.Task.Request
+	(*Task_Response)(nil),                // 28: wasimoff.v1.Task.Response
+	(*Task_Wasip1)(nil),                  // 29: wasimoff.v1.Task.Wasip1
+	(*Task_Pyodide)(nil),                 // 30: wasimoff.v1.Task.Pyodide
+	(*Task_Wasip1_Params)(nil),           // 31: wasimoff.v1.Task.Wasip1.Params
+	(*Task_Wasip1_Output)(nil),           // 32: wasimoff.v1.Task.Wasip1.Output
+	(*Task_Wasip1_Result)(nil),           // 33: wasimoff.v1.Task.Wasip1.Result
+	(*Task_Pyodide_Params)(nil),          // 34: wasimoff.v1.Task.Pyodide.Params
+	(*Task_Pyodide_Output)(nil),          // 35: wasimoff.v1.Task.Pyodide.Output
+	(*Task_Pyodide_Result)(nil),          // 36: wasimoff.v1.Task.Pyodide.Result
+	(*Event_GenericMessage)(nil),         // 37: wasimoff.v1.Event.GenericMessage
+	(*Event_ProviderHello)(nil),          // 38: wasimoff.v1.Event.ProviderHello
+	(*Event_ProviderResources)(nil),      // 39: wasimoff.v1.Event.ProviderResources
+	(*Event_ClusterInfo)(nil),            // 40: wasimoff.v1.Event.ClusterInfo
+	(*Event_Throughput)(nil),             // 41: wasimoff.v1.Event.Throughput
+	(*Event_FileSystemUpdate)(nil),       // 42: wasimoff.v1.Event.FileSystemUpdate
+	(*Client_Job)(nil),                   // 43: wasimoff.v1.Client.Job
+	(*Client_Job_Wasip1Request)(nil),     // 44: wasimoff.v1.Client.Job.Wasip1Request
+	(*Client_Job_Wasip1Response)(nil),    // 45: wasimoff.v1.Client.Job.Wasip1Response
+	(*Client_Job_PyodideRequest)(nil),    // 46: wasimoff.v1.Client.Job.PyodideRequest
+	(*Client_Job_PyodideResponse)(nil),   // 47: wasimoff.v1.Client.Job.PyodideResponse
+	(*Client_Job_Status)(nil),            // 48: wasimoff.v1.Client.Job.Status
+	(*Client_Job_Wasip1TaskResult)(nil),  // 49: wasimoff.v1.Client.Job.Wasip1TaskResult
+	(*Client_Job_PyodideTaskResult)(nil), // 50: wasimoff.v1.Client.Job.PyodideTaskResult
+	(*anypb.Any)(nil),                    // 51: google.protobuf.Any
+	(*timestamppb.Timestamp)(nil),        // 52: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),          // 53: google.protobuf.Duration
 }
 var file_proto_v1_messages_proto_depIdxs = []int32{
 	1,  // 0: wasimoff.v1.Envelope.type:type_name -> wasimoff.v1.Envelope.MessageType
-	39, // 1: wasimoff.v1.Envelope.payload:type_name -> google.protobuf.Any
-	4,  // 2: wasimoff.v1.FileUploadRequest.upload:type_name -> wasimoff.v1.File
-	4,  // 3: wasimoff.v1.FileDownloadResponse.download:type_name -> wasimoff.v1.File
-	40, // 4: wasimoff.v1.Task.QoS.deadline:type_name -> google.protobuf.Timestamp
-	15, // 5: wasimoff.v1.Task.Request.info:type_name -> wasimoff.v1.Task.Metadata
-	16, // 6: wasimoff.v1.Task.Request.qos:type_name -> wasimoff.v1.Task.QoS
-	22, // 7: wasimoff.v1.Task.Request.wasip1:type_name -> wasimoff.v1.Task.Wasip1.Params
-	25, // 8: wasimoff.v1.Task.Request.pyodide:type_name -> wasimoff.v1.Task.Pyodide.Params
-	15, // 9: wasimoff.v1.Task.Response.info:type_name -> wasimoff.v1.Task.Metadata
-	24, // 10: wasimoff.v1.Task.Response.wasip1:type_name -> wasimoff.v1.Task.Wasip1.Result
-	27, // 11: wasimoff.v1.Task.Response.pyodide:type_name -> wasimoff.v1.Task.Pyodide.Result
-	4,  // 12: wasimoff.v1.Task.Wasip1.Params.binary:type_name -> wasimoff.v1.File
-	4,  // 13: wasimoff.v1.Task.Wasip1.Params.rootfs:type_name -> wasimoff.v1.File
-	4,  // 14: wasimoff.v1.Task.Wasip1.Output.artifacts:type_name -> wasimoff.v1.File
-	23, // 15: wasimoff.v1.Task.Wasip1.Result.ok:type_name -> wasimoff.v1.Task.Wasip1.Output
-	26, // 16: wasimoff.v1.Task.Pyodide.Result.ok:type_name -> wasimoff.v1.Task.Pyodide.Output
-	22, // 17: wasimoff.v1.Client.Job.Wasip1Request.parent:type_name -> wasimoff.v1.Task.Wasip1.Params
-	22, // 18: wasimoff.v1.Client.Job.Wasip1Request.tasks:type_name -> wasimoff.v1.Task.Wasip1.Params
-	24, // 19: wasimoff.v1.Client.Job.Wasip1Response.tasks:type_name -> wasimoff.v1.Task.Wasip1.Result
-	25, // 20: wasimoff.v1.Client.Job.PyodideRequest.parent:type_name -> wasimoff.v1.Task.Pyodide.Params
-	25, // 21: wasimoff.v1.Client.Job.PyodideRequest.tasks:type_name -> wasimoff.v1.Task.Pyodide.Params
-	27, // 22: wasimoff.v1.Client.Job.PyodideResponse.tasks:type_name -> wasimoff.v1.Task.Pyodide.Result
-	22, // 23: wasimoff.v1.Wasimoff.RunWasip1:input_type -> wasimoff.v1.Task.Wasip1.Params
-	24, // 24: wasimoff.v1.Wasimoff.RunWasip1:output_type -> wasimoff.v1.Task.Wasip1.Result
-	24, // [24:25] is the sub-list for method output_type
-	23, // [23:24] is the sub-list for method input_type
-	23, // [23:23] is the sub-list for extension type_name
-	23, // [23:23] is the sub-list for extension extendee
-	0,  // [0:23] is the sub-list for field type_name
+	51, // 1: wasimoff.v1.Envelope.payload:type_name -> google.protobuf.Any
+	6,  // 2: wasimoff.v1.FileUploadRequest.upload:type_name -> wasimoff.v1.File
+	6,  // 3: wasimoff.v1.FileDownloadResponse.download:type_name -> wasimoff.v1.File
+	17, // 4: wasimoff.v1.FileChunkUploadRequest.chunk:type_name -> wasimoff.v1.FileChunk
+	17, // 5: wasimoff.v1.FileChunkDownloadResponse.chunk:type_name -> wasimoff.v1.FileChunk
+	52, // 6: wasimoff.v1.Task.QoS.deadline:type_name -> google.protobuf.Timestamp
+	53, // 7: wasimoff.v1.Task.QoS.timeout:type_name -> google.protobuf.Duration
+	24, // 8: wasimoff.v1.Task.Request.info:type_name -> wasimoff.v1.Task.Metadata
+	25, // 9: wasimoff.v1.Task.Request.qos:type_name -> wasimoff.v1.Task.QoS
+	31, // 10: wasimoff.v1.Task.Request.wasip1:type_name -> wasimoff.v1.Task.Wasip1.Params
+	34, // 11: wasimoff.v1.Task.Request.pyodide:type_name -> wasimoff.v1.Task.Pyodide.Params
+	24, // 12: wasimoff.v1.Task.Response.info:type_name -> wasimoff.v1.Task.Metadata
+	33, // 13: wasimoff.v1.Task.Response.wasip1:type_name -> wasimoff.v1.Task.Wasip1.Result
+	36, // 14: wasimoff.v1.Task.Response.pyodide:type_name -> wasimoff.v1.Task.Pyodide.Result
+	6,  // 15: wasimoff.v1.Task.Wasip1.Params.binary:type_name -> wasimoff.v1.File
+	6,  // 16: wasimoff.v1.Task.Wasip1.Params.rootfs:type_name -> wasimoff.v1.File
+	6,  // 17: wasimoff.v1.Task.Wasip1.Output.artifacts:type_name -> wasimoff.v1.File
+	32, // 18: wasimoff.v1.Task.Wasip1.Result.ok:type_name -> wasimoff.v1.Task.Wasip1.Output
+	53, // 19: wasimoff.v1.Task.Wasip1.Result.runtime:type_name -> google.protobuf.Duration
+	6,  // 20: wasimoff.v1.Task.Pyodide.Params.rootfs:type_name -> wasimoff.v1.File
+	6,  // 21: wasimoff.v1.Task.Pyodide.Output.artifacts:type_name -> wasimoff.v1.File
+	35, // 22: wasimoff.v1.Task.Pyodide.Result.ok:type_name -> wasimoff.v1.Task.Pyodide.Output
+	53, // 23: wasimoff.v1.Task.Pyodide.Result.runtime:type_name -> google.protobuf.Duration
+	31, // 24: wasimoff.v1.Client.Job.Wasip1Request.parent:type_name -> wasimoff.v1.Task.Wasip1.Params
+	31, // 25: wasimoff.v1.Client.Job.Wasip1Request.tasks:type_name -> wasimoff.v1.Task.Wasip1.Params
+	25, // 26: wasimoff.v1.Client.Job.Wasip1Request.qos:type_name -> wasimoff.v1.Task.QoS
+	33, // 27: wasimoff.v1.Client.Job.Wasip1Response.tasks:type_name -> wasimoff.v1.Task.Wasip1.Result
+	34, // 28: wasimoff.v1.Client.Job.PyodideRequest.parent:type_name -> wasimoff.v1.Task.Pyodide.Params
+	34, // 29: wasimoff.v1.Client.Job.PyodideRequest.tasks:type_name -> wasimoff.v1.Task.Pyodide.Params
+	25, // 30: wasimoff.v1.Client.Job.PyodideRequest.qos:type_name -> wasimoff.v1.Task.QoS
+	36, // 31: wasimoff.v1.Client.Job.PyodideResponse.tasks:type_name -> wasimoff.v1.Task.Pyodide.Result
+	33, // 32: wasimoff.v1.Client.Job.Wasip1TaskResult.result:type_name -> wasimoff.v1.Task.Wasip1.Result
+	36, // 33: wasimoff.v1.Client.Job.PyodideTaskResult.result:type_name -> wasimoff.v1.Task.Pyodide.Result
+	31, // 34: wasimoff.v1.Wasimoff.RunWasip1:input_type -> wasimoff.v1.Task.Wasip1.Params
+	33, // 35: wasimoff.v1.Wasimoff.RunWasip1:output_type -> wasimoff.v1.Task.Wasip1.Result
+	35, // [35:36] is the sub-list for method output_type
+	34, // [34:35] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_proto_v1_messages_proto_init() }
@@ -2491,20 +3387,20 @@ func file_proto_v1_messages_proto_init() {
 	if File_proto_v1_messages_proto != nil {
 		return
 	}
-	file_proto_v1_messages_proto_msgTypes[16].OneofWrappers = []any{
+	file_proto_v1_messages_proto_msgTypes[25].OneofWrappers = []any{
 		(*Task_Request_Wasip1)(nil),
 		(*Task_Request_Pyodide)(nil),
 	}
-	file_proto_v1_messages_proto_msgTypes[17].OneofWrappers = []any{
+	file_proto_v1_messages_proto_msgTypes[26].OneofWrappers = []any{
 		(*Task_Response_Error)(nil),
 		(*Task_Response_Wasip1)(nil),
 		(*Task_Response_Pyodide)(nil),
 	}
-	file_proto_v1_messages_proto_msgTypes[22].OneofWrappers = []any{
+	file_proto_v1_messages_proto_msgTypes[31].OneofWrappers = []any{
 		(*Task_Wasip1_Result_Error)(nil),
 		(*Task_Wasip1_Result_Ok)(nil),
 	}
-	file_proto_v1_messages_proto_msgTypes[25].OneofWrappers = []any{
+	file_proto_v1_messages_proto_msgTypes[34].OneofWrappers = []any{
 		(*Task_Pyodide_Result_Error)(nil),
 		(*Task_Pyodide_Result_Ok)(nil),
 	}
@@ -2514,7 +3410,7 @@ func file_proto_v1_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_v1_messages_proto_rawDesc), len(file_proto_v1_messages_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   37,
+			NumMessages:   49,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -4,15 +4,15 @@
 
 import type { GenEnum, GenFile, GenMessage, GenService } from "@bufbuild/protobuf/codegenv1";
 import { enumDesc, fileDesc, messageDesc, serviceDesc } from "@bufbuild/protobuf/codegenv1";
-import type { Any, AnyJson, Timestamp, TimestampJson } from "@bufbuild/protobuf/wkt";
-import { file_google_protobuf_any, file_google_protobuf_timestamp } from "@bufbuild/protobuf/wkt";
+import type { Any, AnyJson, Duration, DurationJson, Timestamp, TimestampJson } from "@bufbuild/protobuf/wkt";
+import { file_google_protobuf_any, file_google_protobuf_duration, file_google_protobuf_timestamp } from "@bufbuild/protobuf/wkt";
 import type { Message } from "@bufbuild/protobuf";
 
 /**
  * Describes the file proto/v1/messages.proto.
  */
 export const file_proto_v1_messages: GenFile = /*@__PURE__*/
-  fileDesc("Chdwcm90by92MS9tZXNzYWdlcy5wcm90bxILd2FzaW1vZmYudjEixQEKCEVudmVsb3BlEhAKCHNlcXVlbmNlGAEgASgEEi8KBHR5cGUYAiABKA4yIS53YXNpbW9mZi52MS5FbnZlbG9wZS5NZXNzYWdlVHlwZRINCgVlcnJvchgDIAEoCRIlCgdwYXlsb2FkGAQgASgLMhQuZ29vZ2xlLnByb3RvYnVmLkFueSJACgtNZXNzYWdlVHlwZRILCgdVTktOT1dOEAASCwoHUmVxdWVzdBABEgwKCFJlc3BvbnNlEAISCQoFRXZlbnQQAyL8CAoEVGFzaxo7CghNZXRhZGF0YRIKCgJpZBgBIAEoCRIRCglyZXF1ZXN0ZXIYAiABKAkSEAoIcHJvdmlkZXIYAyABKAkaRQoDUW9TEhAKCHByaW9yaXR5GAEgASgIEiwKCGRlYWRsaW5lGAIgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBokCgZDYW5jZWwSCgoCaWQYASABKAkSDgoGcmVhc29uGAIgASgJGtMBCgdSZXF1ZXN0EigKBGluZm8YASABKAsyGi53YXNpbW9mZi52MS5UYXNrLk1ldGFkYXRhEiIKA3FvcxgCIAEoCzIVLndhc2ltb2ZmLnYxLlRhc2suUW9TEjEKBndhc2lwMRgKIAEoCzIfLndhc2ltb2ZmLnYxLlRhc2suV2FzaXAxLlBhcmFtc0gAEjMKB3B5b2RpZGUYCyABKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUGFyYW1zSABCDAoKcGFyYW1ldGVyc0oECAMQChq9AQoIUmVzcG9uc2USKAoEaW5mbxgBIAEoCzIaLndhc2ltb2ZmLnYxLlRhc2suTWV0YWRhdGESDwoFZXJyb3IYAiABKAlIABIxCgZ3YXNpcDEYCiABKAsyHy53YXNpbW9mZi52MS5UYXNrLldhc2lwMS5SZXN1bHRIABIzCgdweW9kaWRlGAsgASgLMiAud2FzaW1vZmYudjEuVGFzay5QeW9kaWRlLlJlc3VsdEgAQggKBnJlc3VsdEoECAMQChrLAgoGV2FzaXAxGowBCgZQYXJhbXMSIQoGYmluYXJ5GAEgASgLMhEud2FzaW1vZmYudjEuRmlsZRIMCgRhcmdzGAIgAygJEgwKBGVudnMYAyADKAkSDQoFc3RkaW4YBCABKAwSIQoGcm9vdGZzGAUgASgLMhEud2FzaW1vZmYudjEuRmlsZRIRCglhcnRpZmFjdHMYBiADKAkaXgoGT3V0cHV0Eg4KBnN0YXR1cxgBIAEoBRIOCgZzdGRvdXQYAiABKAwSDgoGc3RkZXJyGAMgASgMEiQKCWFydGlmYWN0cxgEIAEoCzIRLndhc2ltb2ZmLnYxLkZpbGUaUgoGUmVzdWx0Eg8KBWVycm9yGAEgASgJSAASLQoCb2sYAiABKAsyHy53YXNpbW9mZi52MS5UYXNrLldhc2lwMS5PdXRwdXRIAEIICgZyZXN1bHQa5QEKB1B5b2RpZGUaOgoGUGFyYW1zEg4KBnNjcmlwdBgBIAEoCRIQCghwYWNrYWdlcxgHIAMoCRIOCgZwaWNrbGUYCCABKAwaSQoGT3V0cHV0Eg4KBnBpY2tsZRgBIAEoDBIOCgZzdGRvdXQYAiABKAwSDgoGc3RkZXJyGAMgASgMEg8KB3ZlcnNpb24YBCABKAkaUwoGUmVzdWx0Eg8KBWVycm9yGAEgASgJSAASLgoCb2sYAiABKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuT3V0cHV0SABCCAoGcmVzdWx0IjAKBEZpbGUSCwoDcmVmGAEgASgJEg0KBW1lZGlhGAIgASgJEgwKBGJsb2IYAyABKAwiFAoSRmlsZUxpc3RpbmdSZXF1ZXN0IiQKE0ZpbGVMaXN0aW5nUmVzcG9uc2USDQoFZmlsZXMYASADKAkiIAoQRmlsZVByb2JlUmVxdWVzdBIMCgRmaWxlGAEgASgJIh8KEUZpbGVQcm9iZVJlc3BvbnNlEgoKAm9rGAEgASgIIjYKEUZpbGVVcGxvYWRSZXF1ZXN0EiEKBnVwbG9hZBgBIAEoCzIRLndhc2ltb2ZmLnYxLkZpbGUiIQoSRmlsZVVwbG9hZFJlc3BvbnNlEgsKA2VychgBIAEoCSIjChNGaWxlRG93bmxvYWRSZXF1ZXN0EgwKBGZpbGUYASABKAkiSAoURmlsZURvd25sb2FkUmVzcG9uc2USIwoIZG93bmxvYWQYASABKAsyES53YXNpbW9mZi52MS5GaWxlEgsKA2VychgCIAEoCSKZAgoFRXZlbnQaIQoOR2VuZXJpY01lc3NhZ2USDwoHbWVzc2FnZRgBIAEoCRowCg1Qcm92aWRlckhlbGxvEgwKBG5hbWUYASABKAkSEQoJdXNlcmFnZW50GAIgASgJGjcKEVByb3ZpZGVyUmVzb3VyY2VzEhMKC2NvbmN1cnJlbmN5GAEgASgNEg0KBXRhc2tzGAIgASgNGiAKC0NsdXN0ZXJJbmZvEhEKCXByb3ZpZGVycxgBIAEoDRosCgpUaHJvdWdocHV0Eg8KB292ZXJhbGwYASABKAISDQoFeW91cnMYAiABKAIaMgoQRmlsZVN5c3RlbVVwZGF0ZRINCgVhZGRlZBgBIAMoCRIPCgdyZW1vdmVkGAIgAygJIpsDCgZDbGllbnQakAMKA0pvYhpwCg1XYXNpcDFSZXF1ZXN0Ei8KBnBhcmVudBgBIAEoCzIfLndhc2ltb2ZmLnYxLlRhc2suV2FzaXAxLlBhcmFtcxIuCgV0YXNrcxgCIAMoCzIfLndhc2ltb2ZmLnYxLlRhc2suV2FzaXAxLlBhcmFtcxpPCg5XYXNpcDFSZXNwb25zZRINCgVlcnJvchgBIAEoCRIuCgV0YXNrcxgCIAMoCzIfLndhc2ltb2ZmLnYxLlRhc2suV2FzaXAxLlJlc3VsdBpzCg5QeW9kaWRlUmVxdWVzdBIwCgZwYXJlbnQYASABKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUGFyYW1zEi8KBXRhc2tzGAIgAygLMiAud2FzaW1vZmYudjEuVGFzay5QeW9kaWRlLlBhcmFtcxpRCg9QeW9kaWRlUmVzcG9uc2USDQoFZXJyb3IYASABKAkSLwoFdGFza3MYAiADKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUmVzdWx0KlwKC1N1YnByb3RvY29sEgsKB1VOS05PV04QABIhCh13YXNpbW9mZl9wcm92aWRlcl92MV9wcm90b2J1ZhABEh0KGXdhc2ltb2ZmX3Byb3ZpZGVyX3YxX2pzb24QAjJbCghXYXNpbW9mZhJPCglSdW5XYXNpcDESHy53YXNpbW9mZi52MS5UYXNrLldhc2lwMS5QYXJhbXMaHy53YXNpbW9mZi52MS5UYXNrLldhc2lwMS5SZXN1bHQiAEIeWhx3YXNpbW9mZi9wcm90by92MTt3YXNpbW9mZnYxYghlZGl0aW9uc3DoBw", [file_google_protobuf_any, file_google_protobuf_timestamp]);
+  fileDesc("Chdwcm90by92MS9tZXNzYWdlcy5wcm90bxILd2FzaW1vZmYudjEixQEKCEVudmVsb3BlEhAKCHNlcXVlbmNlGAEgASgEEi8KBHR5cGUYAiABKA4yIS53YXNpbW9mZi52MS5FbnZlbG9wZS5NZXNzYWdlVHlwZRINCgVlcnJvchgDIAEoCRIlCgdwYXlsb2FkGAQgASgLMhQuZ29vZ2xlLnByb3RvYnVmLkFueSJACgtNZXNzYWdlVHlwZRILCgdVTktOT1dOEAASCwoHUmVxdWVzdBABEgwKCFJlc3BvbnNlEAISCQoFRXZlbnQQAyKICwoEVGFzaxo7CghNZXRhZGF0YRIKCgJpZBgBIAEoCRIRCglyZXF1ZXN0ZXIYAiABKAkSEAoIcHJvdmlkZXIYAyABKAkacQoDUW9TEhAKCHByaW9yaXR5GAEgASgFEiwKCGRlYWRsaW5lGAIgASgLMhouZ29vZ2xlLnByb3RvYnVmLlRpbWVzdGFtcBIqCgd0aW1lb3V0GAMgASgLMhkuZ29vZ2xlLnByb3RvYnVmLkR1cmF0aW9uGiQKBkNhbmNlbBIKCgJpZBgBIAEoCRIOCgZyZWFzb24YAiABKAka0wEKB1JlcXVlc3QSKAoEaW5mbxgBIAEoCzIaLndhc2ltb2ZmLnYxLlRhc2suTWV0YWRhdGESIgoDcW9zGAIgASgLMhUud2FzaW1vZmYudjEuVGFzay5Rb1MSMQoGd2FzaXAxGAogASgLMh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUGFyYW1zSAASMwoHcHlvZGlkZRgLIAEoCzIgLndhc2ltb2ZmLnYxLlRhc2suUHlvZGlkZS5QYXJhbXNIAEIMCgpwYXJhbWV0ZXJzSgQIAxAKGr0BCghSZXNwb25zZRIoCgRpbmZvGAEgASgLMhoud2FzaW1vZmYudjEuVGFzay5NZXRhZGF0YRIPCgVlcnJvchgCIAEoCUgAEjEKBndhc2lwMRgKIAEoCzIfLndhc2ltb2ZmLnYxLlRhc2suV2FzaXAxLlJlc3VsdEgAEjMKB3B5b2RpZGUYCyABKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUmVzdWx0SABCCAoGcmVzdWx0SgQIAxAKGvcCCgZXYXNpcDEajAEKBlBhcmFtcxIhCgZiaW5hcnkYASABKAsyES53YXNpbW9mZi52MS5GaWxlEgwKBGFyZ3MYAiADKAkSDAoEZW52cxgDIAMoCRINCgVzdGRpbhgEIAEoDBIhCgZyb290ZnMYBSABKAsyES53YXNpbW9mZi52MS5GaWxlEhEKCWFydGlmYWN0cxgGIAMoCRpeCgZPdXRwdXQSDgoGc3RhdHVzGAEgASgFEg4KBnN0ZG91dBgCIAEoDBIOCgZzdGRlcnIYAyABKAwSJAoJYXJ0aWZhY3RzGAQgASgLMhEud2FzaW1vZmYudjEuRmlsZRp+CgZSZXN1bHQSDwoFZXJyb3IYASABKAlIABItCgJvaxgCIAEoCzIfLndhc2ltb2ZmLnYxLlRhc2suV2FzaXAxLk91dHB1dEgAEioKB3J1bnRpbWUYAyABKAsyGS5nb29nbGUucHJvdG9idWYuRHVyYXRpb25CCAoGcmVzdWx0GpkDCgdQeW9kaWRlGpsBCgZQYXJhbXMSDgoGc2NyaXB0GAEgASgJEgwKBGFyZ3MYAiADKAkSDAoEZW52cxgDIAMoCRINCgVzdGRpbhgEIAEoDBIhCgZyb290ZnMYBSABKAsyES53YXNpbW9mZi52MS5GaWxlEhEKCWFydGlmYWN0cxgGIAMoCRIQCghwYWNrYWdlcxgHIAMoCRIOCgZwaWNrbGUYCCABKAwabwoGT3V0cHV0Eg4KBnBpY2tsZRgBIAEoDBIOCgZzdGRvdXQYAiABKAwSDgoGc3RkZXJyGAMgASgMEg8KB3ZlcnNpb24YBCABKAkSJAoJYXJ0aWZhY3RzGAUgASgLMhEud2FzaW1vZmYudjEuRmlsZRp/CgZSZXN1bHQSDwoFZXJyb3IYASABKAlIABIuCgJvaxgCIAEoCzIgLndhc2ltb2ZmLnYxLlRhc2suUHlvZGlkZS5PdXRwdXRIABIqCgdydW50aW1lGAMgASgLMhkuZ29vZ2xlLnByb3RvYnVmLkR1cmF0aW9uQggKBnJlc3VsdCINCgtQaW5nUmVxdWVzdCIOCgxQaW5nUmVzcG9uc2UiMAoERmlsZRILCgNyZWYYASABKAkSDQoFbWVkaWEYAiABKAkSDAoEYmxvYhgDIAEoDCIUChJGaWxlTGlzdGluZ1JlcXVlc3QiJAoTRmlsZUxpc3RpbmdSZXNwb25zZRINCgVmaWxlcxgBIAMoCSIgChBGaWxlUHJvYmVSZXF1ZXN0EgwKBGZpbGUYASABKAkiHwoRRmlsZVByb2JlUmVzcG9uc2USCgoCb2sYASABKAgiNgoRRmlsZVVwbG9hZFJlcXVlc3QSIQoGdXBsb2FkGAEgASgLMhEud2FzaW1vZmYudjEuRmlsZSIhChJGaWxlVXBsb2FkUmVzcG9uc2USCwoDZXJyGAEgASgJIiEKEUZpbGVEZWxldGVSZXF1ZXN0EgwKBGZpbGUYASABKAkiIQoSRmlsZURlbGV0ZVJlc3BvbnNlEgsKA2VychgBIAEoCSIjChNGaWxlRG93bmxvYWRSZXF1ZXN0EgwKBGZpbGUYASABKAkiSAoURmlsZURvd25sb2FkUmVzcG9uc2USIwoIZG93bmxvYWQYASABKAsyES53YXNpbW9mZi52MS5GaWxlEgsKA2VychgCIAEoCSJUCglGaWxlQ2h1bmsSCwoDcmVmGAEgASgJEg0KBW1lZGlhGAIgASgJEg0KBXRvdGFsGAMgASgEEg4KBm9mZnNldBgEIAEoBBIMCgRibG9iGAUgASgMIj8KFkZpbGVDaHVua1VwbG9hZFJlcXVlc3QSJQoFY2h1bmsYASABKAsyFi53YXNpbW9mZi52MS5GaWxlQ2h1bmsiOAoXRmlsZUNodW5rVXBsb2FkUmVzcG9uc2USEAoIcmVjZWl2ZWQYASABKAQSCwoDZXJyGAIgASgJIkgKGEZpbGVDaHVua0Rvd25sb2FkUmVxdWVzdBIMCgRmaWxlGAEgASgJEg4KBm9mZnNldBgCIAEoBBIOCgZsZW5ndGgYAyABKAQiTwoZRmlsZUNodW5rRG93bmxvYWRSZXNwb25zZRIlCgVjaHVuaxgBIAEoCzIWLndhc2ltb2ZmLnYxLkZpbGVDaHVuaxILCgNlcnIYAiABKAkipQIKBUV2ZW50GiEKDkdlbmVyaWNNZXNzYWdlEg8KB21lc3NhZ2UYASABKAkaPAoNUHJvdmlkZXJIZWxsbxIMCgRuYW1lGAEgASgJEhEKCXVzZXJhZ2VudBgCIAEoCRIKCgJpZBgDIAEoCRo3ChFQcm92aWRlclJlc291cmNlcxITCgtjb25jdXJyZW5jeRgBIAEoDRINCgV0YXNrcxgCIAEoDRogCgtDbHVzdGVySW5mbxIRCglwcm92aWRlcnMYASABKA0aLAoKVGhyb3VnaHB1dBIPCgdvdmVyYWxsGAEgASgCEg0KBXlvdXJzGAIgASgCGjIKEEZpbGVTeXN0ZW1VcGRhdGUSDQoFYWRkZWQYASADKAkSDwoHcmVtb3ZlZBgCIAMoCSKkBgoGQ2xpZW50GpkGCgNKb2IalAEKDVdhc2lwMVJlcXVlc3QSLwoGcGFyZW50GAEgASgLMh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUGFyYW1zEi4KBXRhc2tzGAIgAygLMh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUGFyYW1zEiIKA3FvcxgDIAEoCzIVLndhc2ltb2ZmLnYxLlRhc2suUW9TGk8KDldhc2lwMVJlc3BvbnNlEg0KBWVycm9yGAEgASgJEi4KBXRhc2tzGAIgAygLMh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUmVzdWx0GpcBCg5QeW9kaWRlUmVxdWVzdBIwCgZwYXJlbnQYASABKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUGFyYW1zEi8KBXRhc2tzGAIgAygLMiAud2FzaW1vZmYudjEuVGFzay5QeW9kaWRlLlBhcmFtcxIiCgNxb3MYAyABKAsyFS53YXNpbW9mZi52MS5UYXNrLlFvUxpRCg9QeW9kaWRlUmVzcG9uc2USDQoFZXJyb3IYASABKAkSLwoFdGFza3MYAiADKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUmVzdWx0GnkKBlN0YXR1cxIKCgJpZBgBIAEoCRINCgVlcnJvchgCIAEoCRINCgV0YXNrcxgDIAEoDRIPCgdwZW5kaW5nGAQgASgNEhEKCWNvbXBsZXRlZBgFIAEoDRIOCgZmYWlsZWQYBiABKA0SEQoJY2FuY2VsbGVkGAcgASgIGl8KEFdhc2lwMVRhc2tSZXN1bHQSDQoFaW5kZXgYASABKA0SLwoGcmVzdWx0GAIgASgLMh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUmVzdWx0EgsKA2pvYhgDIAEoCRphChFQeW9kaWRlVGFza1Jlc3VsdBINCgVpbmRleBgBIAEoDRIwCgZyZXN1bHQYAiABKAsyIC53YXNpbW9mZi52MS5UYXNrLlB5b2RpZGUuUmVzdWx0EgsKA2pvYhgDIAEoCSpcCgtTdWJwcm90b2NvbBILCgdVTktOT1dOEAASIQodd2FzaW1vZmZfcHJvdmlkZXJfdjFfcHJvdG9idWYQARIdChl3YXNpbW9mZl9wcm92aWRlcl92MV9qc29uEAIyWwoIV2FzaW1vZmYSTwoJUnVuV2FzaXAxEh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUGFyYW1zGh8ud2FzaW1vZmYudjEuVGFzay5XYXNpcDEuUmVzdWx0IgBCHlocd2FzaW1vZmYvcHJvdG8vdjE7d2FzaW1vZmZ2MWIIZWRpdGlvbnNw6Ac", [file_google_protobuf_any, file_google_protobuf_duration, file_google_protobuf_timestamp]);
 
 /**
  * Envelope is a generic message wrapper with a sequence counter and message type.
@@ -206,16 +206,30 @@ export const Task_MetadataSchema: GenMessage<Task_Metadata, Task_MetadataJson> =
  */
 export type Task_QoS = Message<"wasimoff.v1.Task.QoS"> & {
   /**
-   * @generated from field: bool priority = 1;
+   * Priority class from -2 (lowest) to 2 (highest), default 0. Higher classes
+   * are dispatched first but waiting tasks are promoted over time. This used
+   * to be a boolean flag with the same binary encoding, where true equals 1.
+   *
+   * @generated from field: int32 priority = 1;
    */
-  priority: boolean;
+  priority: number;
 
   /**
-   * TODO
+   * Tasks which did not finish by then fail. Submissions with a deadline, which
+   * already passed or is sooner than the timeout, are rejected.
    *
    * @generated from field: google.protobuf.Timestamp deadline = 2;
    */
   deadline?: Timestamp;
+
+  /**
+   * Maximum runtime of a single attempt on a Provider, after which the task is
+   * cancelled. Must be positive and can only shorten the timeout configured
+   * on the Broker.
+   *
+   * @generated from field: google.protobuf.Duration timeout = 3;
+   */
+  timeout?: Duration;
 };
 
 /**
@@ -223,14 +237,18 @@ export type Task_QoS = Message<"wasimoff.v1.Task.QoS"> & {
  */
 export type Task_QoSJson = {
   /**
-   * @generated from field: bool priority = 1;
+   * @generated from field: int32 priority = 1;
    */
-  priority?: boolean;
+  priority?: number;
 
   /**
    * @generated from field: google.protobuf.Timestamp deadline = 2;
    */
   deadline?: TimestampJson;
+  /**
+   * @generated from field: google.protobuf.Duration timeout = 3;
+   */
+  timeout?: DurationJson;
 };
 
 /**
@@ -600,6 +618,12 @@ export type Task_Wasip1_Result = Message<"wasimoff.v1.Task.Wasip1.Result"> & {
     value: Task_Wasip1_Output;
     case: "ok";
   } | { case: undefined; value?: undefined };
+  /**
+   * time spent on the Provider, measured by the Broker
+   *
+   * @generated from field: google.protobuf.Duration runtime = 3;
+   */
+  runtime?: Duration;
 };
 
 /**
@@ -615,6 +639,10 @@ export type Task_Wasip1_ResultJson = {
    * @generated from field: wasimoff.v1.Task.Wasip1.Output ok = 2;
    */
   ok?: Task_Wasip1_OutputJson;
+  /**
+   * @generated from field: google.protobuf.Duration runtime = 3;
+   */
+  runtime?: DurationJson;
 };
 
 /**
@@ -647,6 +675,10 @@ export const Task_PyodideSchema: GenMessage<Task_Pyodide, Task_PyodideJson> = /*
   messageDesc(file_proto_v1_messages, 1, 6);
 
 /**
+ * Contains the script and execution arguments to run a single Python task in
+ * Pyodide on the Provider. The rootfs archive is extracted to the working
+ * directory before the script runs, and artifacts are collected from there.
+ *
  * @generated from message wasimoff.v1.Task.Pyodide.Params
  */
 export type Task_Pyodide_Params = Message<"wasimoff.v1.Task.Pyodide.Params"> & {
@@ -655,18 +687,37 @@ export type Task_Pyodide_Params = Message<"wasimoff.v1.Task.Pyodide.Params"> & {
    */
   script: string;
 
+  /**
+   * @generated from field: repeated string args = 2;
+   */
+  args: string[];
+
+  /**
+   * @generated from field: repeated string envs = 3;
+   */
+  envs: string[];
+
+  /**
+   * @generated from field: bytes stdin = 4;
+   */
+  stdin: Uint8Array;
+
+  /**
+   * @generated from field: wasimoff.v1.File rootfs = 5;
+   */
+  rootfs?: File;
+
+  /**
+   * @generated from field: repeated string artifacts = 6;
+   */
+  artifacts: string[];
+
   /**
    * @generated from field: repeated string packages = 7;
    */
   packages: string[];
 
   /**
-   * repeated string args = 2; // TODO
-   * repeated string envs = 3; // TODO
-   * bytes stdin = 4; // TODO
-   * File rootfs = 5; // TODO
-   * repeated string artifacts = 6; // TODO
-   *
    * @generated from field: bytes pickle = 8;
    */
   pickle: Uint8Array;
@@ -681,6 +732,31 @@ export type Task_Pyodide_ParamsJson = {
    */
   script?: string;
 
+  /**
+   * @generated from field: repeated string args = 2;
+   */
+  args?: string[];
+
+  /**
+   * @generated from field: repeated string envs = 3;
+   */
+  envs?: string[];
+
+  /**
+   * @generated from field: bytes stdin = 4;
+   */
+  stdin?: string;
+
+  /**
+   * @generated from field: wasimoff.v1.File rootfs = 5;
+   */
+  rootfs?: FileJson;
+
+  /**
+   * @generated from field: repeated string artifacts = 6;
+   */
+  artifacts?: string[];
+
   /**
    * @generated from field: repeated string packages = 7;
    */
@@ -719,11 +795,14 @@ export type Task_Pyodide_Output = Message<"wasimoff.v1.Task.Pyodide.Output"> & {
   stderr: Uint8Array;
 
   /**
-   * File artifacts = 4;
-   *
    * @generated from field: string version = 4;
    */
   version: string;
+
+  /**
+   * @generated from field: wasimoff.v1.File artifacts = 5;
+   */
+  artifacts?: File;
 };
 
 /**
@@ -749,6 +828,11 @@ export type Task_Pyodide_OutputJson = {
    * @generated from field: string version = 4;
    */
   version?: string;
+
+  /**
+   * @generated from field: wasimoff.v1.File artifacts = 5;
+   */
+  artifacts?: FileJson;
 };
 
 /**
@@ -780,6 +864,12 @@ export type Task_Pyodide_Result = Message<"wasimoff.v1.Task.Pyodide.Result"> & {
     value: Task_Pyodide_Output;
     case: "ok";
   } | { case: undefined; value?: undefined };
+  /**
+   * time spent on the Provider, measured by the Broker
+   *
+   * @generated from field: google.protobuf.Duration runtime = 3;
+   */
+  runtime?: Duration;
 };
 
 /**
@@ -795,6 +885,10 @@ export type Task_Pyodide_ResultJson = {
    * @generated from field: wasimoff.v1.Task.Pyodide.Output ok = 2;
    */
   ok?: Task_Pyodide_OutputJson;
+  /**
+   * @generated from field: google.protobuf.Duration runtime = 3;
+   */
+  runtime?: DurationJson;
 };
 
 /**
@@ -804,6 +898,51 @@ export type Task_Pyodide_ResultJson = {
 export const Task_Pyodide_ResultSchema: GenMessage<Task_Pyodide_Result, Task_Pyodide_ResultJson> = /*@__PURE__*/
   messageDesc(file_proto_v1_messages, 1, 6, 2);
 
+/**
+ * Ping checks the liveness of the other side and measures the round-trip time.
+ * It is answered directly in the Messenger, without queueing it for a handler.
+ *
+ * empty
+ *
+ * @generated from message wasimoff.v1.PingRequest
+ */
+export type PingRequest = Message<"wasimoff.v1.PingRequest"> & {
+};
+
+/**
+ * JSON type for the message wasimoff.v1.PingRequest.
+ */
+export type PingRequestJson = {
+};
+
+/**
+ * Describes the message wasimoff.v1.PingRequest.
+ * Use `create(PingRequestSchema)` to create a new message.
+ */
+export const PingRequestSchema: GenMessage<PingRequest, PingRequestJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 2);
+
+/**
+ * empty
+ *
+ * @generated from message wasimoff.v1.PingResponse
+ */
+export type PingResponse = Message<"wasimoff.v1.PingResponse"> & {
+};
+
+/**
+ * JSON type for the message wasimoff.v1.PingResponse.
+ */
+export type PingResponseJson = {
+};
+
+/**
+ * Describes the message wasimoff.v1.PingResponse.
+ * Use `create(PingResponseSchema)` to create a new message.
+ */
+export const PingResponseSchema: GenMessage<PingResponse, PingResponseJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 3);
+
 /**
  * File is a file reference with optional mime-type. The ref could be a plain
  * filename, a prefixed hash digest or a URL to fetch from. When stored, a hash
@@ -859,7 +998,7 @@ export type FileJson = {
  * Use `create(FileSchema)` to create a new message.
  */
 export const FileSchema: GenMessage<File, FileJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 2);
+  messageDesc(file_proto_v1_messages, 4);
 
 /**
  * FileListing asks for a listing of all available files on Provider
@@ -882,7 +1021,7 @@ export type FileListingRequestJson = {
  * Use `create(FileListingRequestSchema)` to create a new message.
  */
 export const FileListingRequestSchema: GenMessage<FileListingRequest, FileListingRequestJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 3);
+  messageDesc(file_proto_v1_messages, 5);
 
 /**
  * @generated from message wasimoff.v1.FileListingResponse
@@ -909,7 +1048,7 @@ export type FileListingResponseJson = {
  * Use `create(FileListingResponseSchema)` to create a new message.
  */
 export const FileListingResponseSchema: GenMessage<FileListingResponse, FileListingResponseJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 4);
+  messageDesc(file_proto_v1_messages, 6);
 
 /**
  * FileProbe checks if a certain file exists on provider
@@ -938,7 +1077,7 @@ export type FileProbeRequestJson = {
  * Use `create(FileProbeRequestSchema)` to create a new message.
  */
 export const FileProbeRequestSchema: GenMessage<FileProbeRequest, FileProbeRequestJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 5);
+  messageDesc(file_proto_v1_messages, 7);
 
 /**
  * @generated from message wasimoff.v1.FileProbeResponse
@@ -965,7 +1104,7 @@ export type FileProbeResponseJson = {
  * Use `create(FileProbeResponseSchema)` to create a new message.
  */
 export const FileProbeResponseSchema: GenMessage<FileProbeResponse, FileProbeResponseJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 6);
+  messageDesc(file_proto_v1_messages, 8);
 
 /**
  * FileUpload pushes a file to the Provider.
@@ -994,7 +1133,7 @@ export type FileUploadRequestJson = {
  * Use `create(FileUploadRequestSchema)` to create a new message.
  */
 export const FileUploadRequestSchema: GenMessage<FileUploadRequest, FileUploadRequestJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 7);
+  messageDesc(file_proto_v1_messages, 9);
 
 /**
  * @generated from message wasimoff.v1.FileUploadResponse
@@ -1021,7 +1160,63 @@ export type FileUploadResponseJson = {
  * Use `create(FileUploadResponseSchema)` to create a new message.
  */
 export const FileUploadResponseSchema: GenMessage<FileUploadResponse, FileUploadResponseJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 8);
+  messageDesc(file_proto_v1_messages, 10);
+
+/**
+ * FileDelete asks the Provider to remove a file, after it was deleted on the Broker.
+ *
+ * @generated from message wasimoff.v1.FileDeleteRequest
+ */
+export type FileDeleteRequest = Message<"wasimoff.v1.FileDeleteRequest"> & {
+  /**
+   * @generated from field: string file = 1;
+   */
+  file: string;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileDeleteRequest.
+ */
+export type FileDeleteRequestJson = {
+  /**
+   * @generated from field: string file = 1;
+   */
+  file?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileDeleteRequest.
+ * Use `create(FileDeleteRequestSchema)` to create a new message.
+ */
+export const FileDeleteRequestSchema: GenMessage<FileDeleteRequest, FileDeleteRequestJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 11);
+
+/**
+ * @generated from message wasimoff.v1.FileDeleteResponse
+ */
+export type FileDeleteResponse = Message<"wasimoff.v1.FileDeleteResponse"> & {
+  /**
+   * @generated from field: string err = 1;
+   */
+  err: string;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileDeleteResponse.
+ */
+export type FileDeleteResponseJson = {
+  /**
+   * @generated from field: string err = 1;
+   */
+  err?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileDeleteResponse.
+ * Use `create(FileDeleteResponseSchema)` to create a new message.
+ */
+export const FileDeleteResponseSchema: GenMessage<FileDeleteResponse, FileDeleteResponseJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 12);
 
 /**
  * FileDownload can be sent by the Provider to request a file download.
@@ -1050,7 +1245,7 @@ export type FileDownloadRequestJson = {
  * Use `create(FileDownloadRequestSchema)` to create a new message.
  */
 export const FileDownloadRequestSchema: GenMessage<FileDownloadRequest, FileDownloadRequestJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 9);
+  messageDesc(file_proto_v1_messages, 13);
 
 /**
  * @generated from message wasimoff.v1.FileDownloadResponse
@@ -1087,7 +1282,246 @@ export type FileDownloadResponseJson = {
  * Use `create(FileDownloadResponseSchema)` to create a new message.
  */
 export const FileDownloadResponseSchema: GenMessage<FileDownloadResponse, FileDownloadResponseJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 10);
+  messageDesc(file_proto_v1_messages, 14);
+
+/**
+ * Large files are transferred in chunks, so they don't block other messages on
+ * the connection for too long. The receiver keeps partial files by their ref,
+ * so an interrupted transfer can resume where it stopped, even after a reconnect.
+ * The assembled file is verified against the digest in its ref.
+ *
+ * @generated from message wasimoff.v1.FileChunk
+ */
+export type FileChunk = Message<"wasimoff.v1.FileChunk"> & {
+  /**
+   * digest of the complete file
+   *
+   * @generated from field: string ref = 1;
+   */
+  ref: string;
+
+  /**
+   * media type in MIME notation
+   *
+   * @generated from field: string media = 2;
+   */
+  media: string;
+
+  /**
+   * size of the complete file
+   *
+   * @generated from field: uint64 total = 3;
+   */
+  total: bigint;
+
+  /**
+   * position of this chunk in the file
+   *
+   * @generated from field: uint64 offset = 4;
+   */
+  offset: bigint;
+
+  /**
+   * the chunk contents, its length is implicit
+   *
+   * @generated from field: bytes blob = 5;
+   */
+  blob: Uint8Array;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileChunk.
+ */
+export type FileChunkJson = {
+  /**
+   * @generated from field: string ref = 1;
+   */
+  ref?: string;
+
+  /**
+   * @generated from field: string media = 2;
+   */
+  media?: string;
+
+  /**
+   * @generated from field: uint64 total = 3;
+   */
+  total?: string;
+
+  /**
+   * @generated from field: uint64 offset = 4;
+   */
+  offset?: string;
+
+  /**
+   * @generated from field: bytes blob = 5;
+   */
+  blob?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileChunk.
+ * Use `create(FileChunkSchema)` to create a new message.
+ */
+export const FileChunkSchema: GenMessage<FileChunk, FileChunkJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 15);
+
+/**
+ * FileChunkUpload pushes a chunk of a file to the Provider. A chunk without any
+ * contents only queries the progress. The Provider always responds with the
+ * number of bytes it holds, i.e. the offset for the next chunk.
+ *
+ * @generated from message wasimoff.v1.FileChunkUploadRequest
+ */
+export type FileChunkUploadRequest = Message<"wasimoff.v1.FileChunkUploadRequest"> & {
+  /**
+   * @generated from field: wasimoff.v1.FileChunk chunk = 1;
+   */
+  chunk?: FileChunk;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileChunkUploadRequest.
+ */
+export type FileChunkUploadRequestJson = {
+  /**
+   * @generated from field: wasimoff.v1.FileChunk chunk = 1;
+   */
+  chunk?: FileChunkJson;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileChunkUploadRequest.
+ * Use `create(FileChunkUploadRequestSchema)` to create a new message.
+ */
+export const FileChunkUploadRequestSchema: GenMessage<FileChunkUploadRequest, FileChunkUploadRequestJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 16);
+
+/**
+ * @generated from message wasimoff.v1.FileChunkUploadResponse
+ */
+export type FileChunkUploadResponse = Message<"wasimoff.v1.FileChunkUploadResponse"> & {
+  /**
+   * @generated from field: uint64 received = 1;
+   */
+  received: bigint;
+
+  /**
+   * @generated from field: string err = 2;
+   */
+  err: string;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileChunkUploadResponse.
+ */
+export type FileChunkUploadResponseJson = {
+  /**
+   * @generated from field: uint64 received = 1;
+   */
+  received?: string;
+
+  /**
+   * @generated from field: string err = 2;
+   */
+  err?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileChunkUploadResponse.
+ * Use `create(FileChunkUploadResponseSchema)` to create a new message.
+ */
+export const FileChunkUploadResponseSchema: GenMessage<FileChunkUploadResponse, FileChunkUploadResponseJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 17);
+
+/**
+ * FileChunkDownload can be sent by the Provider to request a chunk of a file.
+ * The Broker may send less than the requested length, but never more.
+ *
+ * @generated from message wasimoff.v1.FileChunkDownloadRequest
+ */
+export type FileChunkDownloadRequest = Message<"wasimoff.v1.FileChunkDownloadRequest"> & {
+  /**
+   * name or ref
+   *
+   * @generated from field: string file = 1;
+   */
+  file: string;
+
+  /**
+   * @generated from field: uint64 offset = 2;
+   */
+  offset: bigint;
+
+  /**
+   * @generated from field: uint64 length = 3;
+   */
+  length: bigint;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileChunkDownloadRequest.
+ */
+export type FileChunkDownloadRequestJson = {
+  /**
+   * @generated from field: string file = 1;
+   */
+  file?: string;
+
+  /**
+   * @generated from field: uint64 offset = 2;
+   */
+  offset?: string;
+
+  /**
+   * @generated from field: uint64 length = 3;
+   */
+  length?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileChunkDownloadRequest.
+ * Use `create(FileChunkDownloadRequestSchema)` to create a new message.
+ */
+export const FileChunkDownloadRequestSchema: GenMessage<FileChunkDownloadRequest, FileChunkDownloadRequestJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 18);
+
+/**
+ * @generated from message wasimoff.v1.FileChunkDownloadResponse
+ */
+export type FileChunkDownloadResponse = Message<"wasimoff.v1.FileChunkDownloadResponse"> & {
+  /**
+   * @generated from field: wasimoff.v1.FileChunk chunk = 1;
+   */
+  chunk?: FileChunk;
+
+  /**
+   * @generated from field: string err = 2;
+   */
+  err: string;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.FileChunkDownloadResponse.
+ */
+export type FileChunkDownloadResponseJson = {
+  /**
+   * @generated from field: wasimoff.v1.FileChunk chunk = 1;
+   */
+  chunk?: FileChunkJson;
+
+  /**
+   * @generated from field: string err = 2;
+   */
+  err?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.FileChunkDownloadResponse.
+ * Use `create(FileChunkDownloadResponseSchema)` to create a new message.
+ */
+export const FileChunkDownloadResponseSchema: GenMessage<FileChunkDownloadResponse, FileChunkDownloadResponseJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 19);
 
 /**
  * @generated from message wasimoff.v1.Event
@@ -1106,7 +1540,7 @@ export type EventJson = {
  * Use `create(EventSchema)` to create a new message.
  */
 export const EventSchema: GenMessage<Event, EventJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11);
+  messageDesc(file_proto_v1_messages, 20);
 
 /**
  * GenericMessage is just a generic piece of text for logging
@@ -1135,7 +1569,7 @@ export type Event_GenericMessageJson = {
  * Use `create(Event_GenericMessageSchema)` to create a new message.
  */
 export const Event_GenericMessageSchema: GenMessage<Event_GenericMessage, Event_GenericMessageJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11, 0);
+  messageDesc(file_proto_v1_messages, 20, 0);
 
 /**
  * ProviderHello is sent once at the beginning to identify the Provider
@@ -1156,6 +1590,13 @@ export type Event_ProviderHello = Message<"wasimoff.v1.Event.ProviderHello"> & {
    * @generated from field: string useragent = 2;
    */
   useragent: string;
+
+  /**
+   * a persistent identifier to resume the session after reconnects
+   *
+   * @generated from field: string id = 3;
+   */
+  id: string;
 };
 
 /**
@@ -1171,6 +1612,11 @@ export type Event_ProviderHelloJson = {
    * @generated from field: string useragent = 2;
    */
   useragent?: string;
+
+  /**
+   * @generated from field: string id = 3;
+   */
+  id?: string;
 };
 
 /**
@@ -1178,7 +1624,7 @@ export type Event_ProviderHelloJson = {
  * Use `create(Event_ProviderHelloSchema)` to create a new message.
  */
 export const Event_ProviderHelloSchema: GenMessage<Event_ProviderHello, Event_ProviderHelloJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11, 1);
+  messageDesc(file_proto_v1_messages, 20, 1);
 
 /**
  * ProviderResources is information about the available resources in Worker pool
@@ -1221,7 +1667,7 @@ export type Event_ProviderResourcesJson = {
  * Use `create(Event_ProviderResourcesSchema)` to create a new message.
  */
 export const Event_ProviderResourcesSchema: GenMessage<Event_ProviderResources, Event_ProviderResourcesJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11, 2);
+  messageDesc(file_proto_v1_messages, 20, 2);
 
 /**
  * ClusterInfo contains information about all connected Providers
@@ -1252,7 +1698,7 @@ export type Event_ClusterInfoJson = {
  * Use `create(Event_ClusterInfoSchema)` to create a new message.
  */
 export const Event_ClusterInfoSchema: GenMessage<Event_ClusterInfo, Event_ClusterInfoJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11, 3);
+  messageDesc(file_proto_v1_messages, 20, 3);
 
 /**
  * Throughput contains information about overall cluster throughput
@@ -1295,7 +1741,7 @@ export type Event_ThroughputJson = {
  * Use `create(Event_ThroughputSchema)` to create a new message.
  */
 export const Event_ThroughputSchema: GenMessage<Event_Throughput, Event_ThroughputJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11, 4);
+  messageDesc(file_proto_v1_messages, 20, 4);
 
 /**
  * FileSystemUpdate notifies the Broker about changed files on the Provider.
@@ -1334,7 +1780,7 @@ export type Event_FileSystemUpdateJson = {
  * Use `create(Event_FileSystemUpdateSchema)` to create a new message.
  */
 export const Event_FileSystemUpdateSchema: GenMessage<Event_FileSystemUpdate, Event_FileSystemUpdateJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 11, 5);
+  messageDesc(file_proto_v1_messages, 20, 5);
 
 /**
  * @generated from message wasimoff.v1.Client
@@ -1353,7 +1799,7 @@ export type ClientJson = {
  * Use `create(ClientSchema)` to create a new message.
  */
 export const ClientSchema: GenMessage<Client, ClientJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 12);
+  messageDesc(file_proto_v1_messages, 21);
 
 /**
  * Jobs specify a simple parent-inheritance structure for each task format, so
@@ -1379,7 +1825,7 @@ export type Client_JobJson = {
  * Use `create(Client_JobSchema)` to create a new message.
  */
 export const Client_JobSchema: GenMessage<Client_Job, Client_JobJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 12, 0);
+  messageDesc(file_proto_v1_messages, 21, 0);
 
 /**
  * @generated from message wasimoff.v1.Client.Job.Wasip1Request
@@ -1394,6 +1840,13 @@ export type Client_Job_Wasip1Request = Message<"wasimoff.v1.Client.Job.Wasip1Req
    * @generated from field: repeated wasimoff.v1.Task.Wasip1.Params tasks = 2;
    */
   tasks: Task_Wasip1_Params[];
+
+  /**
+   * applies to every task of the job
+   *
+   * @generated from field: wasimoff.v1.Task.QoS qos = 3;
+   */
+  qos?: Task_QoS;
 };
 
 /**
@@ -1409,6 +1862,11 @@ export type Client_Job_Wasip1RequestJson = {
    * @generated from field: repeated wasimoff.v1.Task.Wasip1.Params tasks = 2;
    */
   tasks?: Task_Wasip1_ParamsJson[];
+
+  /**
+   * @generated from field: wasimoff.v1.Task.QoS qos = 3;
+   */
+  qos?: Task_QoSJson;
 };
 
 /**
@@ -1416,7 +1874,7 @@ export type Client_Job_Wasip1RequestJson = {
  * Use `create(Client_Job_Wasip1RequestSchema)` to create a new message.
  */
 export const Client_Job_Wasip1RequestSchema: GenMessage<Client_Job_Wasip1Request, Client_Job_Wasip1RequestJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 12, 0, 0);
+  messageDesc(file_proto_v1_messages, 21, 0, 0);
 
 /**
  * @generated from message wasimoff.v1.Client.Job.Wasip1Response
@@ -1453,7 +1911,7 @@ export type Client_Job_Wasip1ResponseJson = {
  * Use `create(Client_Job_Wasip1ResponseSchema)` to create a new message.
  */
 export const Client_Job_Wasip1ResponseSchema: GenMessage<Client_Job_Wasip1Response, Client_Job_Wasip1ResponseJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 12, 0, 1);
+  messageDesc(file_proto_v1_messages, 21, 0, 1);
 
 /**
  * @generated from message wasimoff.v1.Client.Job.PyodideRequest
@@ -1468,6 +1926,13 @@ export type Client_Job_PyodideRequest = Message<"wasimoff.v1.Client.Job.PyodideR
    * @generated from field: repeated wasimoff.v1.Task.Pyodide.Params tasks = 2;
    */
   tasks: Task_Pyodide_Params[];
+
+  /**
+   * applies to every task of the job
+   *
+   * @generated from field: wasimoff.v1.Task.QoS qos = 3;
+   */
+  qos?: Task_QoS;
 };
 
 /**
@@ -1483,6 +1948,11 @@ export type Client_Job_PyodideRequestJson = {
    * @generated from field: repeated wasimoff.v1.Task.Pyodide.Params tasks = 2;
    */
   tasks?: Task_Pyodide_ParamsJson[];
+
+  /**
+   * @generated from field: wasimoff.v1.Task.QoS qos = 3;
+   */
+  qos?: Task_QoSJson;
 };
 
 /**
@@ -1490,7 +1960,7 @@ export type Client_Job_PyodideRequestJson = {
  * Use `create(Client_Job_PyodideRequestSchema)` to create a new message.
  */
 export const Client_Job_PyodideRequestSchema: GenMessage<Client_Job_PyodideRequest, Client_Job_PyodideRequestJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 12, 0, 2);
+  messageDesc(file_proto_v1_messages, 21, 0, 2);
 
 /**
  * @generated from message wasimoff.v1.Client.Job.PyodideResponse
@@ -1527,7 +1997,209 @@ export type Client_Job_PyodideResponseJson = {
  * Use `create(Client_Job_PyodideResponseSchema)` to create a new message.
  */
 export const Client_Job_PyodideResponseSchema: GenMessage<Client_Job_PyodideResponse, Client_Job_PyodideResponseJson> = /*@__PURE__*/
-  messageDesc(file_proto_v1_messages, 12, 0, 3);
+  messageDesc(file_proto_v1_messages, 21, 0, 3);
+
+/**
+ * Asynchronous jobs are submitted with the same requests but only return a
+ * Status with an identifier, which can be polled for progress later.
+ *
+ * @generated from message wasimoff.v1.Client.Job.Status
+ */
+export type Client_Job_Status = Message<"wasimoff.v1.Client.Job.Status"> & {
+  /**
+   * identifier of this job
+   *
+   * @generated from field: string id = 1;
+   */
+  id: string;
+
+  /**
+   * the job failed entirely, e.g. due to missing files
+   *
+   * @generated from field: string error = 2;
+   */
+  error: string;
+
+  /**
+   * total number of tasks
+   *
+   * @generated from field: uint32 tasks = 3;
+   */
+  tasks: number;
+
+  /**
+   * tasks still queued or running
+   *
+   * @generated from field: uint32 pending = 4;
+   */
+  pending: number;
+
+  /**
+   * tasks finished with a result
+   *
+   * @generated from field: uint32 completed = 5;
+   */
+  completed: number;
+
+  /**
+   * tasks finished with an error
+   *
+   * @generated from field: uint32 failed = 6;
+   */
+  failed: number;
+
+  /**
+   * the job was cancelled by the client
+   *
+   * @generated from field: bool cancelled = 7;
+   */
+  cancelled: boolean;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.Client.Job.Status.
+ */
+export type Client_Job_StatusJson = {
+  /**
+   * @generated from field: string id = 1;
+   */
+  id?: string;
+
+  /**
+   * @generated from field: string error = 2;
+   */
+  error?: string;
+
+  /**
+   * @generated from field: uint32 tasks = 3;
+   */
+  tasks?: number;
+
+  /**
+   * @generated from field: uint32 pending = 4;
+   */
+  pending?: number;
+
+  /**
+   * @generated from field: uint32 completed = 5;
+   */
+  completed?: number;
+
+  /**
+   * @generated from field: uint32 failed = 6;
+   */
+  failed?: number;
+
+  /**
+   * @generated from field: bool cancelled = 7;
+   */
+  cancelled?: boolean;
+};
+
+/**
+ * Describes the message wasimoff.v1.Client.Job.Status.
+ * Use `create(Client_Job_StatusSchema)` to create a new message.
+ */
+export const Client_Job_StatusSchema: GenMessage<Client_Job_Status, Client_Job_StatusJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 21, 0, 4);
+
+/**
+ * Results of asynchronous jobs are streamed individually as tasks finish,
+ * so they carry the identifier of the job and the index of the task in the
+ * original request. On the client WebSocket they are sent as Events.
+ *
+ * @generated from message wasimoff.v1.Client.Job.Wasip1TaskResult
+ */
+export type Client_Job_Wasip1TaskResult = Message<"wasimoff.v1.Client.Job.Wasip1TaskResult"> & {
+  /**
+   * @generated from field: uint32 index = 1;
+   */
+  index: number;
+
+  /**
+   * @generated from field: wasimoff.v1.Task.Wasip1.Result result = 2;
+   */
+  result?: Task_Wasip1_Result;
+
+  /**
+   * @generated from field: string job = 3;
+   */
+  job: string;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.Client.Job.Wasip1TaskResult.
+ */
+export type Client_Job_Wasip1TaskResultJson = {
+  /**
+   * @generated from field: uint32 index = 1;
+   */
+  index?: number;
+
+  /**
+   * @generated from field: wasimoff.v1.Task.Wasip1.Result result = 2;
+   */
+  result?: Task_Wasip1_ResultJson;
+
+  /**
+   * @generated from field: string job = 3;
+   */
+  job?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.Client.Job.Wasip1TaskResult.
+ * Use `create(Client_Job_Wasip1TaskResultSchema)` to create a new message.
+ */
+export const Client_Job_Wasip1TaskResultSchema: GenMessage<Client_Job_Wasip1TaskResult, Client_Job_Wasip1TaskResultJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 21, 0, 5);
+
+/**
+ * @generated from message wasimoff.v1.Client.Job.PyodideTaskResult
+ */
+export type Client_Job_PyodideTaskResult = Message<"wasimoff.v1.Client.Job.PyodideTaskResult"> & {
+  /**
+   * @generated from field: uint32 index = 1;
+   */
+  index: number;
+
+  /**
+   * @generated from field: wasimoff.v1.Task.Pyodide.Result result = 2;
+   */
+  result?: Task_Pyodide_Result;
+
+  /**
+   * @generated from field: string job = 3;
+   */
+  job: string;
+};
+
+/**
+ * JSON type for the message wasimoff.v1.Client.Job.PyodideTaskResult.
+ */
+export type Client_Job_PyodideTaskResultJson = {
+  /**
+   * @generated from field: uint32 index = 1;
+   */
+  index?: number;
+
+  /**
+   * @generated from field: wasimoff.v1.Task.Pyodide.Result result = 2;
+   */
+  result?: Task_Pyodide_ResultJson;
+
+  /**
+   * @generated from field: string job = 3;
+   */
+  job?: string;
+};
+
+/**
+ * Describes the message wasimoff.v1.Client.Job.PyodideTaskResult.
+ * Use `create(Client_Job_PyodideTaskResultSchema)` to create a new message.
+ */
+export const Client_Job_PyodideTaskResultSchema: GenMessage<Client_Job_PyodideTaskResult, Client_Job_PyodideTaskResultJson> = /*@__PURE__*/
+  messageDesc(file_proto_v1_messages, 21, 0, 6);
 
 /**
  * Subprotocol is used to identify the concrete encoding on the wire.
@@ -1,9 +1,11 @@
 import { LRUCache } from "lru-cache";
 import { MemoryFileSystem } from "./memory.ts";
+import { type FileChunk } from "@wasimoff/proto/v1/messages_pb.ts";
 
 const logprefix = [ "%c[ProviderStorage]", "color: purple;" ];
 
 const twentyfourhours = 24*60*60*1000; // in milliseconds
+const fiveminutes = 5*60*1000;
 
 /** ProviderStorage is an abstract interface to store and retrieve WebAssembly
  * executables and packed assets. It can for example be backed by a simple
@@ -16,6 +18,19 @@ export class ProviderStorage {
   // base origin for the remote fetching
   public origin: string;
 
+  // api token for the remote fetching, if the broker requires authentication
+  private token?: string;
+
+  /** Optionally download files from the broker without HTTP, e.g. over the Messenger.
+   * The returned File must be named by its ref. Resolve to undefined if the broker
+   * doesn't have the file; throw to fall back to HTTP. */
+  public download?: (filename: string) => Promise<File | undefined>;
+
+  // incomplete chunked transfers, keyed by ref; dropped when no chunk arrives for a while
+  private partials = new LRUCache<string, { media: string, total: bigint, received: bigint, chunks: Uint8Array[] }>({
+    max: 8, ttl: fiveminutes, ttlAutopurge: true, updateAgeOnGet: true,
+  });
+
   public updates = new EventEmitter<{ added?: string[], removed?: string[] }>();
 
   // cache compiled webassembly modules
@@ -49,23 +64,30 @@ export class ProviderStorage {
       // OriginPrivateFileSystem.open(path).then(fs => this.filesystem = fs);
     };
 
-    this.origin = origin;
+    // the token can be given in the url query
+    let url = new URL(origin);
+    this.origin = url.origin;
+    this.token = url.searchParams.get("token") ?? undefined;
 
   };
 
   // fetch a file from the backend
   private async fetchFile(filename: string): Promise<File | undefined> {
 
-    // request the file from broker
+    // request the file from broker, preferably with the download hook
     console.warn(...logprefix, `file ${filename} not found locally, fetch from broker`);
-    let response = await fetch(`${this.origin}/api/storage/${filename}`);
-    if (!response.ok) return undefined;
+    let file: File | undefined;
+    try {
+      if (this.download === undefined) throw "no download hook";
+      file = await this.download(filename);
+    } catch (err) {
+      if (this.download !== undefined) console.warn(...logprefix, `download failed, fetch with http:`, err);
+      file = await this.httpFetch(filename);
+    };
+    if (file === undefined) return undefined;
 
-    // store fetched file to filesystem
-    let buf = await response.arrayBuffer();
-    let media = response.headers.get("content-type") || "";
-    let name = response.headers.get("x-wasimoff-ref") || await getRef(buf);
-    let file = new File([buf], name, { type: media });
+    // store fetched file to filesystem, named by its ref
+    let name = file.name;
     await this.filesystem.put(name, file);
     
     // emit event for broker
@@ -74,6 +96,52 @@ export class ProviderStorage {
 
   };
 
+  // fetch a file from the broker's storage over http
+  private async httpFetch(filename: string): Promise<File | undefined> {
+    let headers: HeadersInit = this.token ? { "authorization": `Bearer ${this.token}` } : {};
+    let response = await fetch(`${this.origin}/api/storage/${filename}`, { headers });
+    if (!response.ok) return undefined;
+    let buf = await response.arrayBuffer();
+    let media = response.headers.get("content-type") || "";
+    let name = await checkRef(buf, response.headers.get("x-wasimoff-ref") || undefined);
+    return new File([buf], name, { type: media });
+  };
+
+  /** Append a chunk to the partial file with the same ref and return the number of bytes
+   * received so far. A chunk which doesn't continue at the end is ignored, so the sender
+   * can resume at the returned offset. When the file is complete, it is verified against
+   * its ref (if a digest can be computed in this context) and returned but not stored yet. */
+  async assemble(chunk: FileChunk): Promise<{ received: bigint, file?: File }> {
+    let { ref, media, total, offset, blob } = chunk;
+    if (!isRef(ref)) throw "chunk needs a digest ref";
+
+    // nothing to do if the file is complete already
+    let existing = await this.filesystem.get(ref);
+    if (existing !== undefined) return { received: BigInt(existing.size), file: existing };
+    let partial = this.partials.get(ref);
+    if (partial === undefined || partial.total !== total) {
+      partial = { media, total, received: 0n, chunks: [] };
+      this.partials.set(ref, partial);
+    };
+
+    // append the chunk if it's the next one
+    if (blob.length !== 0 && offset === partial.received) {
+      if (partial.received + BigInt(blob.length) > total) {
+        this.partials.delete(ref);
+        throw "chunk exceeds the total size";
+      };
+      partial.chunks.push(blob);
+      partial.received += BigInt(blob.length);
+    };
+    if (partial.received < total) return { received: partial.received };
+
+    // verify the complete file
+    this.partials.delete(ref);
+    let file = new File(partial.chunks, ref, { type: partial.media });
+    await checkRef(await file.arrayBuffer(), ref);
+    return { received: partial.received, file };
+  };
+
   // TODO: emitting events for removed files requires shimming the FileSystem functions
 
   // either return a file from filesystem or attempt to fetch it remotely
@@ -93,6 +161,14 @@ export class ProviderStorage {
     return this.zipCache.fetch(filename);
   };
 
+  /** Remove a file from the filesystem and the caches. */
+  async rm(filename: string): Promise<boolean> {
+    this.wasmCache.delete(filename);
+    this.zipCache.delete(filename);
+    if (await this.filesystem.get(filename) === undefined) return false;
+    return this.filesystem.rm(filename);
+  };
+
 }
 
 /** ProviderStorageFileSystem is an underlying structure, which actually holds the
@@ -135,6 +211,15 @@ export async function getRef(buf: ArrayBuffer): Promise<string> {
   return `sha256:${hex}`;
 }
 
+/** Check that the digest of a file matches its given ref, or compute the ref if
+ * there is none. In insecure contexts without a digest, the given ref is trusted. */
+export async function checkRef(buf: ArrayBuffer, ref?: string): Promise<string> {
+  if (ref === undefined || !isRef(ref)) return getRef(buf);
+  if (crypto.subtle && await getRef(buf) !== ref.toLowerCase())
+    throw "digest does not match ref";
+  return ref;
+}
+
 /** Return a bytelength in human-readable unit. */
 export function filesize(bytes: number): string {
   if (bytes < 1024) return `${bytes} B`;
@@ -1,6 +1,6 @@
 import { create, createRegistry, toBinary, Message as ProtoMessage } from "@bufbuild/protobuf";
-import { AnySchema, anyUnpack, type Any } from "@bufbuild/protobuf/wkt";
-import { Envelope_MessageType as MessageType, EnvelopeSchema, file_proto_v1_messages } from "@wasimoff/proto/v1/messages_pb.ts";
+import { AnySchema, anyIs, anyUnpack, type Any } from "@bufbuild/protobuf/wkt";
+import { Envelope_MessageType as MessageType, EnvelopeSchema, file_proto_v1_messages, PingRequestSchema, PingResponseSchema } from "@wasimoff/proto/v1/messages_pb.ts";
 import { type Transport } from "./index.ts";
 import { PushableAsyncIterable } from "@wasimoff/func/pushableiterable.ts";
 
@@ -48,6 +48,13 @@ export class Messenger implements MessengerInterface {
       switch (m.type) {
 
         case MessageType.Request:
+          // answer heartbeats directly, so they don't wait behind queued requests
+          if (m.payload && anyIs(m.payload, PingRequestSchema)) {
+            this.transport.send(create(EnvelopeSchema, {
+              type: MessageType.Response, sequence: m.sequence, payload: this.pack(create(PingResponseSchema)),
+            })).catch(err => console.warn("failed to answer ping:", err));
+            break;
+          };
           // construct a RemoteProcedureCall that will send a response when it's done
           //? careful not to await the call itself here, otherwise stream is blocked
           this.requests.push(async (handler) => {
@@ -2,11 +2,11 @@
 declare var self: DedicatedWorkerGlobalScope | SharedWorkerGlobalScope;
 export {};
 
-import { ProviderStorage } from "@wasimoff/storage/index.ts";
+import { checkRef, ProviderStorage } from "@wasimoff/storage/index.ts";
 import { Messenger, WebSocketTransport } from "@wasimoff/transport/index.ts";
 import { WasiWorkerPool } from "./workerpool.ts";
-import { create, Message } from "@bufbuild/protobuf";
-import { Event_FileSystemUpdateSchema, Event_ProviderHelloSchema, Event_ProviderResourcesSchema }
+import { create, isMessage, Message } from "@bufbuild/protobuf";
+import { Event_FileSystemUpdateSchema, Event_ProviderHelloSchema, Event_ProviderResourcesSchema, FileChunkDownloadRequestSchema, FileChunkDownloadResponseSchema, FileDownloadRequestSchema, FileDownloadResponseSchema }
   from "@wasimoff/proto/v1/messages_pb.ts";
 import { rpchandler } from "@wasimoff/worker/rpchandler.ts";
 import { expose, proxy as comlinkProxy, workerReady, transfer, proxy } from "./comlink.ts";
@@ -86,12 +86,12 @@ export class WasimoffProvider {
     let url = new URL(origin);
     if (!/^https?:$/.test(url.protocol)) throw "origin should be https? url";
 
-    // connect the storage
-    await p.open(dir, url.origin);
+    // connect the storage, keeping a ?token= in the query
+    await p.open(dir, url.href);
 
     // replace protocol with websocket for transport
     url.protocol = url.protocol.replace("http", "ws");
-    await p.connect(url.origin);
+    await p.connect(url.href);
 
     return p;
   };
@@ -112,6 +112,34 @@ export class WasimoffProvider {
   };
 
 
+  // --------->  session resumption
+
+  /** persistent id to resume the session with the broker after a reconnect */
+  public readonly id = crypto.randomUUID();
+
+  /** keep results which could not be delivered this long, in milliseconds */
+  static readonly resultRetention = 5 * 60_000;
+
+  // results of tasks which are running or were not delivered yet, by task id and digest
+  private results = new Map<string, Promise<Message>>();
+
+  /** Run a task only once. When the broker sends it again after a reconnect,
+   * it receives the result of the task which was already running. */
+  async once(key: string, run: () => Promise<Message>): Promise<Message> {
+    let result = this.results.get(key);
+    if (result === undefined) {
+      const messenger = this.messenger;
+      result = run();
+      this.results.set(key, result);
+      result.catch(() => { /* handled by the caller */ }).finally(() => {
+        if (this.messenger === messenger && !messenger?.closed.aborted) this.results.delete(key);
+        else setTimeout(() => this.results.delete(key), WasimoffProvider.resultRetention);
+      });
+    };
+    return result;
+  };
+
+
   // --------->  file storage
 
   /** Return a comlink proxy of the storage. */
@@ -131,7 +159,48 @@ export class WasimoffProvider {
     this.storage.updates.on(update => {
       if (this.messenger) this.messenger.sendEvent(create(Event_FileSystemUpdateSchema, update));
     });
+    this.storage.download = (filename) => this.download(filename);
+
+  };
+
+  // length of chunks to request, the broker may send less
+  static readonly downloadChunkSize = 1n << 20n;
+
+  // download a file from the broker's storage over the messenger, in chunks if the
+  // broker supports it; chunks are assembled by ref in the storage, so a download
+  // which was interrupted by a reconnect continues where it stopped
+  private async download(filename: string): Promise<File | undefined> {
+    if (!this.messenger || !this.storage) throw "not connected";
+    let name = filename, offset = 0n;
+    while (true) {
+      let response = await this.messenger.sendRequest(create(FileChunkDownloadRequestSchema, {
+        file: name, offset, length: WasimoffProvider.downloadChunkSize,
+      }));
+      if (response instanceof Error) {
+        if (offset === 0n) return this.downloadWhole(filename); // broker doesn't serve chunks
+        throw response;
+      };
+      if (!isMessage(response, FileChunkDownloadResponseSchema)) throw "unexpected response type";
+      if (response.err || !response.chunk) return undefined;
+      let { received, file } = await this.storage.assemble(response.chunk);
+      if (file !== undefined) return file;
+      if (received === offset && response.chunk.blob.length === 0) throw `download stalled at offset ${offset}`;
+      // continue by ref, in case the name is changed meanwhile
+      name = response.chunk.ref;
+      offset = received;
+    };
+  };
 
+  // download a file from the broker's storage in a single message
+  private async downloadWhole(filename: string): Promise<File | undefined> {
+    if (!this.messenger) throw "not connected";
+    let response = await this.messenger.sendRequest(create(FileDownloadRequestSchema, { file: filename }));
+    if (response instanceof Error) throw response;
+    if (!isMessage(response, FileDownloadResponseSchema)) throw "unexpected response type";
+    if (response.err || !response.download) return undefined;
+    let { blob, media, ref } = response.download;
+    ref = await checkRef(blob, ref);
+    return new File([blob], ref, { type: media });
   };
 
 
@@ -239,7 +308,7 @@ export class WasimoffProvider {
       this.messenger.sendEvent(create(Event_ProviderResourcesSchema, { concurrency: pool }));
     };
     if (name !== undefined || useragent !== undefined) {
-      this.messenger.sendEvent(create(Event_ProviderHelloSchema, { name, useragent }));
+      this.messenger.sendEvent(create(Event_ProviderHelloSchema, { name, useragent, id: this.id }));
     };
   };
 
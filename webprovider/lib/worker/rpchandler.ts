@@ -1,12 +1,34 @@
-import { create, isMessage, Message as ProtoMessage } from "@bufbuild/protobuf";
+import { create, isMessage, Message as ProtoMessage, toBinary } from "@bufbuild/protobuf";
 import * as wasimoff from "@wasimoff/proto/v1/messages_pb.ts";
-import { getRef, isRef } from "@wasimoff/storage/index.ts";
+import { checkRef } from "@wasimoff/storage/index.ts";
 import { WasimoffProvider } from "./provider.ts";
 
 // Handle incoming RemoteProcedureCalls on the Messenger iterable. Moved into a
 // separate file for better readability and separation of concerns in a way.
 
 export async function rpchandler(this: WasimoffProvider, request: ProtoMessage): Promise<ProtoMessage> {
+  // the broker sends running tasks again after a reconnect, run them only once;
+  // identified by a digest as well because a restarted broker reuses the ids
+  if (isMessage(request, wasimoff.Task_RequestSchema) && request.info?.id) {
+    const digest = await crypto.subtle.digest("SHA-256", toBinary(wasimoff.Task_RequestSchema, request));
+    const hex = Array.from(new Uint8Array(digest), b => b.toString(16).padStart(2, "0")).join("");
+    return this.once(`${request.info.id}@${hex}`, () => handle.call(this, request));
+  };
+  return handle.call(this, request);
+};
+
+// get the rootfs archive of a task from the inline blob or from storage
+async function rootfsArchive(this: WasimoffProvider, file?: wasimoff.File): Promise<Uint8Array | undefined> {
+  if (file === undefined) return undefined;
+  if (file.blob.length !== 0) return file.blob;
+  if (file.ref === "") throw new Error("rootfs: neither blob nor ref were given");
+  if (this.storage === undefined) throw "cannot access storage yet";
+  let z = await this.storage.getZipArchive(file.ref);
+  if (z === undefined) throw "zip not found in storage";
+  return new Uint8Array(z);
+};
+
+async function handle(this: WasimoffProvider, request: ProtoMessage): Promise<ProtoMessage> {
   switch (true) {
 
     // execute a task
@@ -42,19 +64,7 @@ export async function rpchandler(this: WasimoffProvider, request: ProtoMessage):
           };
     
           // get rootfs archive
-          let rootfs: Uint8Array | undefined;
-          if (task.rootfs !== undefined) {
-            if (task.rootfs.blob.length !== 0) {
-              rootfs = task.rootfs.blob;
-            } else if (task.rootfs.ref !== "") {
-              if (this.storage === undefined) throw "cannot access storage yet";
-              let z = await this.storage.getZipArchive(task.rootfs.ref);
-              if (z === undefined) throw "zip not found in storage";
-              else rootfs = new Uint8Array(z);
-            } else {
-              throw new Error("rootfs: neither blob nor ref were given");
-            }
-          }
+          let rootfs = await rootfsArchive.call(this, task.rootfs);
     
           console.debug("%c[RPCHandler]", "color: orange;", task);
     
@@ -103,10 +113,21 @@ export async function rpchandler(this: WasimoffProvider, request: ProtoMessage):
           if (pytask.script === undefined)
             throw "pyodide.script cannot be undefined";
     
+          // get rootfs archive
+          let pyrootfs = await rootfsArchive.call(this, pytask.rootfs);
+
           console.debug("%c[RPCHandler]", "color: orange;", pytask);
           try {
 
-            let run = await this.pool.runPyodide(info.id, pytask);
+            let run = await this.pool.runPyodide(info.id, {
+              script: pytask.script,
+              packages: pytask.packages,
+              args: pytask.args,
+              envs: pytask.envs,
+              stdin: pytask.stdin,
+              rootfs: pyrootfs,
+              artifacts: pytask.artifacts,
+            });
             return create(wasimoff.Task_ResponseSchema, {
               result: { case: "pyodide", value: {
                 result: { case: "ok", value: {
@@ -114,6 +135,7 @@ export async function rpchandler(this: WasimoffProvider, request: ProtoMessage):
                   stdout: run.stdout,
                   stderr: run.stderr,
                   version: run.version,
+                  artifacts: run.artifacts ? { blob: run.artifacts } : undefined,
                 }},
               }},
             });
@@ -163,12 +185,32 @@ export async function rpchandler(this: WasimoffProvider, request: ProtoMessage):
       if (request.upload === undefined) throw "empty upload";
       if (this.storage === undefined) throw "cannot access storage yet";
       let { blob, media, ref } = request.upload;
-      // overwrite name with computed digest
-      if (!isRef(ref)) { ref = await getRef(blob); };
+      // use the computed digest as name, or check the given one
+      ref = await checkRef(blob, ref);
       await this.storage.filesystem.put(ref, new File([blob], ref, { type: media }));
       return create(wasimoff.FileUploadResponseSchema, { });
     })();
 
+    // large files uploaded from the broker in chunks
+    case isMessage(request, wasimoff.FileChunkUploadRequestSchema): return <Promise<wasimoff.FileChunkUploadResponse>>(async () => {
+      if (request.chunk === undefined) throw "empty chunk";
+      if (this.storage === undefined) throw "cannot access storage yet";
+      try {
+        let { received, file } = await this.storage.assemble(request.chunk);
+        if (file !== undefined) await this.storage.filesystem.put(file.name, file);
+        return create(wasimoff.FileChunkUploadResponseSchema, { received });
+      } catch (err) {
+        return create(wasimoff.FileChunkUploadResponseSchema, { err: String(err) });
+      };
+    })();
+
+    // files deleted on the broker
+    case isMessage(request, wasimoff.FileDeleteRequestSchema): return <Promise<wasimoff.FileDeleteResponse>>(async () => {
+      if (this.storage === undefined) throw "cannot access storage yet";
+      await this.storage.rm(request.file);
+      return create(wasimoff.FileDeleteResponseSchema, { });
+    })();
+
     default:
       throw "not implemented yet";
 
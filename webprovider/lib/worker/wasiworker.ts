@@ -9,7 +9,7 @@ import { ZipReader, Uint8ArrayReader, Uint8ArrayWriter, ZipWriter } from "@zip.j
 import { expose, workerReady } from "./comlink.ts";
 import { Inode } from "@bjorn3/browser_wasi_shim";
 import { Directory } from "@bjorn3/browser_wasi_shim";
-import { loadPyodide } from "pyodide";
+import { loadPyodide, type PyodideInterface } from "pyodide";
 
 
 /** Web Worker which runs WebAssembly modules with a WASI shim in a quasi threadpool. */
@@ -144,12 +144,31 @@ export class WasiWorker {
         return more.length;
       }});
 
+      // setup stdin, which is read once
+      let stdin = task.stdin;
+      py.setStdin({ stdin: () => {
+        let chunk = stdin;
+        stdin = undefined;
+        return chunk;
+      }});
+
+      // prepare the working directory, commandline arguments and environment
+      const cwd: string = py.runPython("import os; os.getcwd()");
+      if (task.rootfs !== undefined)
+        await extractPyodideRootfs(py.FS, cwd, task.rootfs);
+      py.runPython("import os, sys; sys.argv = list(argv); os.environ.update(e.partition('=')[::2] for e in envs)", {
+        locals: new Map<string, unknown>([["argv", task.args], ["envs", task.envs]]) as any,
+      });
+
       // run the script
       await py.loadPackagesFromImports(task.script);
       let ret = py.runPython(task.script);
       let result: PyodideTaskResult = {
         stdout, stderr, version: py.version,
       };
+      if (task.artifacts !== undefined && task.artifacts.length > 0) {
+        result.artifacts = await compressPyodideArtifacts(py.FS, cwd, task.artifacts);
+      };
 
       // maybe pickle the last line result
       if (ret !== undefined) {
@@ -222,6 +241,16 @@ export type PyodideTaskParams = {
   script: string;
   /** Preload known packages more efficiently during instantiation. */
   packages: string[];
+  /** Commandline arguments in `sys.argv`. */
+  args: string[];
+  /** Environment variables in a `KEY=value` mapping. */
+  envs: string[];
+  /** Put something on `stdin`, instead of an empty file. */
+  stdin?: Uint8Array;
+  /** Load files into the working directory from a zip archive. */
+  rootfs?: Uint8Array;
+  /** Send back a zip archive with artifacts from the working directory. */
+  artifacts?: string[];
 };
 
 /** Result of a Pyodide task. */
@@ -234,6 +263,8 @@ export type PyodideTaskResult = {
   pickle?: Uint8Array;
   /** Pyodide version, might be important to unpickle. */
   version: string;
+  /** Packed artifacts that were requested. */
+  artifacts?: Uint8Array;
 };
 
 
@@ -318,6 +349,43 @@ async function compressArtifacts(dir: PreopenDirectory, artifacts: string[]): Pr
   return await zip.close();
 };
 
+/** Extract a zip archive to the working directory in Pyodide's filesystem. */
+async function extractPyodideRootfs(fs: PyodideInterface["FS"], cwd: string, archive: Uint8Array) {
+  const zip = new ZipReader(new Uint8ArrayReader(archive));
+  for await (const entry of zip.getEntriesGenerator()) {
+    // mkdirTree only works with absolute paths
+    const path = `${cwd}/${entry.filename.replace(/^\/+|\/+$/g, "")}`;
+    if (entry.directory) {
+      fs.mkdirTree(path);
+    } else {
+      fs.mkdirTree(path.slice(0, path.lastIndexOf("/")));
+      let bufwriter = new Uint8ArrayWriter();
+      await entry.getData!(bufwriter);
+      fs.writeFile(path, await bufwriter.getData());
+    };
+  };
+};
+
+/** Pack requested artifacts from the working directory in Pyodide's filesystem. */
+async function compressPyodideArtifacts(fs: PyodideInterface["FS"], cwd: string, artifacts: string[]): Promise<Uint8Array> {
+  let zip = new ZipWriter(new Uint8ArrayWriter());
+
+  // add all requested files
+  await Promise.all(artifacts.map(filename => {
+    if (filename.startsWith("/")) filename = filename.slice(1);
+    let stat: { mode: number } | undefined;
+    try { stat = fs.stat(`${cwd}/${filename}`); } catch { };
+    if (stat !== undefined && !fs.isDir(stat.mode)) {
+      return zip.add(filename, new Uint8ArrayReader(fs.readFile(`${cwd}/${filename}`)), { useWebWorkers: false });
+    } else {
+      return zip.add(filename, undefined, { directory: true, useWebWorkers: false });
+    };
+  }));
+
+  // finish the file and return its contents
+  return await zip.close();
+};
+
 
 //
 // -------------------- patches --------------------